@@ -4,6 +4,8 @@
 package v1alpha1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -19,14 +21,166 @@ type ClusterSpec struct {
 	Token ClusterToken `json:"token"`
 
 	// server defines the address to a slurmrestd.
-	Server string `json:"server"`
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// servers defines addresses to additional slurmrestd, in order of
+	// preference after server. Requests fail over to the next healthy
+	// server when one becomes unavailable. Servers may only differ by scheme
+	// and host.
+	// +optional
+	Servers []string `json:"servers,omitempty"`
+
+	// tls configures TLS for the connection to slurmrestd.
+	// +optional
+	TLS *ClusterTLS `json:"tls,omitempty"`
+
+	// deletionPolicy controls how the cluster is deleted while NodeSets still
+	// reference it. `Block` waits until those NodeSets are deleted.
+	// `DrainNodeSets` scales them to zero, draining their Slurm nodes, and
+	// waits until they have no pods left. Defaults to `Block`.
+	// +kubebuilder:validation:Enum=Block;DrainNodeSets
+	// +optional
+	DeletionPolicy ClusterDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// restApiVersion pins the Slurm REST API version used to talk to
+	// slurmrestd (e.g. `v0041`). When unset, the newest version served by
+	// slurmrestd and supported by the operator is negotiated.
+	// +kubebuilder:validation:Enum=v0040;v0041
+	// +optional
+	RestAPIVersion string `json:"restApiVersion,omitempty"`
+
+	// powerSave configures how the Slurm power saving programs of the cluster
+	// authenticate to the operator.
+	// +optional
+	PowerSave *ClusterPowerSave `json:"powerSave,omitempty"`
+}
+
+// ClusterPowerSave configures the Slurm power saving programs of a cluster.
+type ClusterPowerSave struct {
+	// tokenSecretRef defines a secret holding the `token` that the
+	// ResumeProgram and SuspendProgram of the cluster present as a bearer
+	// token. Requests for the cluster are rejected when unset.
+	TokenSecretRef string `json:"tokenSecretRef"`
 }
 
+// PowerSaveTokenSecretKey is the key of the bearer token in the secret
+// referenced by `ClusterPowerSave.TokenSecretRef`.
+const PowerSaveTokenSecretKey = "token"
+
+// ClusterDeletionPolicy defines how a cluster is deleted while NodeSets still
+// reference it.
+type ClusterDeletionPolicy string
+
+const (
+	// ClusterDeletionPolicyBlock waits until referencing NodeSets are deleted.
+	ClusterDeletionPolicyBlock ClusterDeletionPolicy = "Block"
+	// ClusterDeletionPolicyDrainNodeSets scales referencing NodeSets to zero
+	// and waits until they have no pods.
+	ClusterDeletionPolicyDrainNodeSets ClusterDeletionPolicy = "DrainNodeSets"
+)
+
+// ClusterToken defines how the operator authenticates to slurmrestd. Exactly
+// one of secretRef or jwt must be set.
 type ClusterToken struct {
 	// secretRef defines a secret to read the valid auth token to the cluster.
-	SecretRef string `json:"secretRef"`
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// jwt configures the operator to mint and rotate its own tokens, signed
+	// with the Slurm `auth/jwt` HS256 key.
+	// +optional
+	JWT *ClusterTokenJWT `json:"jwt,omitempty"`
 }
 
+// ClusterTokenJWT configures operator-minted HS256 tokens.
+type ClusterTokenJWT struct {
+	// keySecretRef defines a secret holding the `jwt_hs256.key` that
+	// slurmctld uses to verify tokens.
+	KeySecretRef string `json:"keySecretRef"`
+
+	// username is the Slurm user the tokens authenticate as.
+	Username string `json:"username"`
+
+	// lifetime is how long each minted token is valid. Tokens are refreshed
+	// when less than a fifth of their lifetime remains. Defaults to 1h.
+	// +optional
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+}
+
+const (
+	// JWTKeySecretKey is the key of the HS256 signing key in the secret
+	// referenced by `ClusterTokenJWT.KeySecretRef`.
+	JWTKeySecretKey = "jwt_hs256.key"
+	// DefaultJWTLifetime is the lifetime of minted tokens when none is set.
+	DefaultJWTLifetime = time.Hour
+	// MinJWTLifetime is the shortest allowed lifetime of minted tokens.
+	MinJWTLifetime = 5 * time.Minute
+)
+
+// ClusterTLS configures how the slurmrestd server is verified and how the
+// operator authenticates to it.
+type ClusterTLS struct {
+	// caBundle references PEM encoded CA certificates used to verify the
+	// server certificate. When unset, the system roots are used.
+	// +optional
+	CABundle *ClusterTLSCABundle `json:"caBundle,omitempty"`
+
+	// clientCertSecretRef defines a kubernetes.io/tls secret whose `tls.crt`
+	// and `tls.key` are presented to the server for mutual TLS.
+	// +optional
+	ClientCertSecretRef string `json:"clientCertSecretRef,omitempty"`
+
+	// serverName overrides the name used to verify the server certificate.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// pinnedCertificates are hex encoded SHA-256 fingerprints of server
+	// certificates. When set, the server certificate must match one of them,
+	// in addition to being verified.
+	// +optional
+	PinnedCertificates []string `json:"pinnedCertificates,omitempty"`
+}
+
+// DefaultCABundleKey is the key read from a CA bundle reference when none is set.
+const DefaultCABundleKey = "ca.crt"
+
+// ClusterTLSCABundle references a key in a secret or a configmap.
+type ClusterTLSCABundle struct {
+	// secretRef defines a secret to read the CA bundle from.
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// configMapRef defines a configmap to read the CA bundle from.
+	// +optional
+	ConfigMapRef string `json:"configMapRef,omitempty"`
+
+	// key is the key holding the CA bundle. Defaults to `ca.crt`.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// Cluster condition types.
+const (
+	// ClusterSecretResolved indicates whether the token secret was found and
+	// contains an auth token.
+	ClusterSecretResolved = "SecretResolved"
+	// ClusterClientConfigured indicates whether a Slurm client was created for
+	// the cluster.
+	ClusterClientConfigured = "ClientConfigured"
+	// ClusterControllerReachable indicates whether slurmrestd answered and a
+	// slurmctld responded to a ping.
+	ClusterControllerReachable = "ControllerReachable"
+	// ClusterAuthenticated indicates whether slurmrestd accepted the auth token.
+	ClusterAuthenticated = "Authenticated"
+	// ClusterTokenExpiring indicates whether the static auth token expires
+	// soon, or has expired.
+	ClusterTokenExpiring = "TokenExpiring"
+	// ClusterDeletionBlocked indicates that deletion of the cluster waits on
+	// NodeSets that reference it.
+	ClusterDeletionBlocked = "DeletionBlocked"
+)
+
 // ClusterStatus defines the observed state of Cluster
 type ClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -36,6 +190,55 @@ type ClusterStatus struct {
 	// was established.
 	IsReady bool `json:"isReady,omitempty"`
 
+	// server is the slurmrestd endpoint the operator is currently using.
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// healthyServers are the slurmrestd endpoints that passed their last
+	// health check.
+	// +optional
+	HealthyServers []string `json:"healthyServers,omitempty"`
+
+	// lastPingTime is when the Slurm controller became reachable, or another
+	// slurmctld responded to the ping. It is not updated on every ping.
+	// +optional
+	LastPingTime *metav1.Time `json:"lastPingTime,omitempty"`
+
+	// pingLatency is the latency of the ping at lastPingTime.
+	// +optional
+	PingLatency *metav1.Duration `json:"pingLatency,omitempty"`
+
+	// tokenExpiresAt is when the auth token in use expires.
+	// +optional
+	TokenExpiresAt *metav1.Time `json:"tokenExpiresAt,omitempty"`
+
+	// restApiVersion is the Slurm REST API version in use.
+	// +optional
+	RestAPIVersion string `json:"restApiVersion,omitempty"`
+
+	// slurmClusterName is the cluster name reported by slurmrestd.
+	// +optional
+	SlurmClusterName string `json:"slurmClusterName,omitempty"`
+
+	// slurmVersion is the Slurm release reported by slurmrestd.
+	// +optional
+	SlurmVersion string `json:"slurmVersion,omitempty"`
+
+	// nodes is the number of nodes known to slurmctld.
+	// +optional
+	Nodes int32 `json:"nodes,omitempty"`
+
+	// nodeSetNodes is the number of nodes known to slurmctld that are backed
+	// by NodeSet pods.
+	// +optional
+	NodeSetNodes int32 `json:"nodeSetNodes,omitempty"`
+
+	// partitions are the Slurm partitions and the state of their nodes.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Partitions []ClusterPartitionStatus `json:"partitions,omitempty"`
+
 	// Represents the latest available observations of a Cluster's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -45,9 +248,48 @@ type ClusterStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+// ClusterPartitionStatus defines the observed state of a Slurm partition.
+type ClusterPartitionStatus struct {
+	// name is the partition name.
+	Name string `json:"name"`
+
+	// state is the partition state (e.g. `UP`).
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// nodes is the number of nodes in the partition.
+	Nodes int32 `json:"nodes"`
+
+	// allocated is the number of fully allocated nodes.
+	// +optional
+	Allocated int32 `json:"allocated,omitempty"`
+
+	// mixed is the number of partially allocated nodes.
+	// +optional
+	Mixed int32 `json:"mixed,omitempty"`
+
+	// idle is the number of idle nodes.
+	// +optional
+	Idle int32 `json:"idle,omitempty"`
+
+	// down is the number of down nodes.
+	// +optional
+	Down int32 `json:"down,omitempty"`
+
+	// drain is the number of draining or drained nodes.
+	// +optional
+	Drain int32 `json:"drain,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.isReady"
+//+kubebuilder:printcolumn:name="SERVER",type="string",JSONPath=".status.server",priority=1
+//+kubebuilder:printcolumn:name="API",type="string",JSONPath=".status.restApiVersion",priority=1
+//+kubebuilder:printcolumn:name="SLURM",type="string",JSONPath=".status.slurmVersion",priority=1
+//+kubebuilder:printcolumn:name="NODES",type="integer",JSONPath=".status.nodes",priority=1
+//+kubebuilder:printcolumn:name="NODESET-NODES",type="integer",JSONPath=".status.nodeSetNodes",priority=1
+//+kubebuilder:printcolumn:name="PARTITIONS",type="string",JSONPath=".status.partitions[*].name",priority=1
 //+kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Cluster is the Schema for the clusters API
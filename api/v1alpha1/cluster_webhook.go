@@ -5,8 +5,12 @@ package v1alpha1
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
@@ -37,6 +41,17 @@ var _ webhook.CustomDefaulter = &Cluster{}
 func (r *Cluster) Default(ctx context.Context, obj runtime.Object) error {
 	cluster := obj.(*Cluster)
 	clusterlog.Info("default", "cluster", klog.KObj(cluster))
+
+	if cluster.Spec.TLS != nil && cluster.Spec.TLS.CABundle != nil && cluster.Spec.TLS.CABundle.Key == "" {
+		cluster.Spec.TLS.CABundle.Key = DefaultCABundleKey
+	}
+	if cluster.Spec.Token.JWT != nil && cluster.Spec.Token.JWT.Lifetime == nil {
+		cluster.Spec.Token.JWT.Lifetime = &metav1.Duration{Duration: DefaultJWTLifetime}
+	}
+	if cluster.Spec.DeletionPolicy == "" {
+		cluster.Spec.DeletionPolicy = ClusterDeletionPolicyBlock
+	}
+
 	return nil
 }
 
@@ -78,11 +93,47 @@ func validateCluster(r *Cluster) (admission.Warnings, []error) {
 	var warns admission.Warnings
 	var errs []error
 
-	if r.Spec.Server == "" {
-		errs = append(errs, fmt.Errorf("`Cluster.Spec.Server` cannot be empty"))
+	if r.Spec.Server == "" && len(r.Spec.Servers) == 0 {
+		errs = append(errs, fmt.Errorf("`Cluster.Spec.Server` and `Cluster.Spec.Servers` cannot both be empty"))
+	}
+	for _, server := range r.Spec.Servers {
+		if server == "" {
+			errs = append(errs, fmt.Errorf("`Cluster.Spec.Servers` cannot contain an empty server"))
+			break
+		}
+	}
+	if (r.Spec.Token.SecretRef == "") == (r.Spec.Token.JWT == nil) {
+		errs = append(errs, fmt.Errorf("`Cluster.Spec.Token` must set exactly one of `SecretRef` or `JWT`"))
+	}
+	if jwt := r.Spec.Token.JWT; jwt != nil {
+		if jwt.KeySecretRef == "" {
+			errs = append(errs, fmt.Errorf("`Cluster.Spec.Token.JWT.KeySecretRef` cannot be empty"))
+		}
+		if jwt.Username == "" {
+			errs = append(errs, fmt.Errorf("`Cluster.Spec.Token.JWT.Username` cannot be empty"))
+		}
+		if jwt.Lifetime != nil && jwt.Lifetime.Duration < MinJWTLifetime {
+			errs = append(errs, fmt.Errorf("`Cluster.Spec.Token.JWT.Lifetime` cannot be less than %v", MinJWTLifetime))
+		}
 	}
-	if r.Spec.Token.SecretRef == "" {
-		errs = append(errs, fmt.Errorf("`Cluster.Spec.Token.SecretRef` cannot be empty"))
+	if tls := r.Spec.TLS; tls != nil {
+		if ca := tls.CABundle; ca != nil {
+			if (ca.SecretRef == "") == (ca.ConfigMapRef == "") {
+				errs = append(errs, fmt.Errorf("`Cluster.Spec.TLS.CABundle` must set exactly one of `SecretRef` or `ConfigMapRef`"))
+			}
+		}
+		for _, pin := range tls.PinnedCertificates {
+			if b, err := hex.DecodeString(strings.ReplaceAll(pin, ":", "")); err != nil || len(b) != sha256.Size {
+				errs = append(errs, fmt.Errorf("`Cluster.Spec.TLS.PinnedCertificates` contains an invalid SHA-256 fingerprint %q", pin))
+			}
+		}
+		if len(tls.PinnedCertificates) > 0 || tls.CABundle != nil || tls.ClientCertSecretRef != "" {
+			for _, server := range append([]string{r.Spec.Server}, r.Spec.Servers...) {
+				if strings.HasPrefix(server, "http://") {
+					warns = append(warns, fmt.Sprintf("`Cluster.Spec.TLS` is ignored for server %q", server))
+				}
+			}
+		}
 	}
 
 	return warns, errs
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_validateCluster(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     ClusterSpec
+		wantErrs int
+	}{
+		{
+			name:     "Empty",
+			spec:     ClusterSpec{},
+			wantErrs: 2,
+		},
+		{
+			name: "Server",
+			spec: ClusterSpec{
+				Server: "http://slurmrestd:6820",
+				Token:  ClusterToken{SecretRef: "token"},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "Servers",
+			spec: ClusterSpec{
+				Servers: []string{"http://slurmrestd-0:6820", "http://slurmrestd-1:6820"},
+				Token:   ClusterToken{SecretRef: "token"},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "Empty entry in servers",
+			spec: ClusterSpec{
+				Servers: []string{"http://slurmrestd-0:6820", ""},
+				Token:   ClusterToken{SecretRef: "token"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "JWT",
+			spec: ClusterSpec{
+				Server: "http://slurmrestd:6820",
+				Token: ClusterToken{
+					JWT: &ClusterTokenJWT{
+						KeySecretRef: "slurm-jwt-key",
+						Username:     "slurm",
+					},
+				},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "JWT and SecretRef",
+			spec: ClusterSpec{
+				Server: "http://slurmrestd:6820",
+				Token: ClusterToken{
+					SecretRef: "token",
+					JWT: &ClusterTokenJWT{
+						KeySecretRef: "slurm-jwt-key",
+						Username:     "slurm",
+					},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "JWT incomplete",
+			spec: ClusterSpec{
+				Server: "http://slurmrestd:6820",
+				Token: ClusterToken{
+					JWT: &ClusterTokenJWT{
+						Lifetime: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+			wantErrs: 3,
+		},
+		{
+			name: "TLS CA bundle without reference",
+			spec: ClusterSpec{
+				Server: "https://slurmrestd:6820",
+				Token:  ClusterToken{SecretRef: "token"},
+				TLS: &ClusterTLS{
+					CABundle: &ClusterTLSCABundle{},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "TLS invalid pin",
+			spec: ClusterSpec{
+				Server: "https://slurmrestd:6820",
+				Token:  ClusterToken{SecretRef: "token"},
+				TLS: &ClusterTLS{
+					CABundle: &ClusterTLSCABundle{
+						ConfigMapRef: "ca",
+					},
+					PinnedCertificates: []string{"abcd"},
+				},
+			},
+			wantErrs: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := validateCluster(&Cluster{Spec: tt.spec})
+			if len(errs) != tt.wantErrs {
+				t.Errorf("validateCluster() errs = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
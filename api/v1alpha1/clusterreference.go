@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterKey returns the key of the Cluster referenced by the NodeSet.
+func (o *NodeSet) ClusterKey() types.NamespacedName {
+	namespace := o.Spec.ClusterNamespace
+	if namespace == "" {
+		namespace = o.GetNamespace()
+	}
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      o.Spec.ClusterName,
+	}
+}
+
+// IsClusterReferenceGranted returns true when the NodeSet may reference its
+// Cluster. References within the namespace of the NodeSet are always allowed,
+// otherwise a ClusterReferenceGrant in the namespace of the Cluster must allow
+// it.
+func IsClusterReferenceGranted(ctx context.Context, reader client.Reader, nodeset *NodeSet) (bool, error) {
+	clusterKey := nodeset.ClusterKey()
+	if clusterKey.Namespace == nodeset.GetNamespace() {
+		return true, nil
+	}
+	grantList := &ClusterReferenceGrantList{}
+	if err := reader.List(ctx, grantList, client.InNamespace(clusterKey.Namespace)); err != nil {
+		return false, err
+	}
+	for _, grant := range grantList.Items {
+		if grant.Allows(nodeset.GetNamespace(), clusterKey.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
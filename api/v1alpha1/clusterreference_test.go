@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newGrant(namespace string, from []string, clusterNames ...string) *ClusterReferenceGrant {
+	grant := &ClusterReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "grant",
+		},
+		Spec: ClusterReferenceGrantSpec{
+			ClusterNames: clusterNames,
+		},
+	}
+	for _, namespace := range from {
+		grant.Spec.From = append(grant.Spec.From, ClusterReferenceGrantFrom{Namespace: namespace})
+	}
+	return grant
+}
+
+func newClusterRefNodeSet(namespace, clusterNamespace string) *NodeSet {
+	return &NodeSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "foo",
+		},
+		Spec: NodeSetSpec{
+			ClusterName:      "slurm",
+			ClusterNamespace: clusterNamespace,
+		},
+	}
+}
+
+func TestNodeSet_ClusterKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodeset *NodeSet
+		want    types.NamespacedName
+	}{
+		{
+			name:    "Same namespace",
+			nodeset: newClusterRefNodeSet("tenant", ""),
+			want:    types.NamespacedName{Namespace: "tenant", Name: "slurm"},
+		},
+		{
+			name:    "Other namespace",
+			nodeset: newClusterRefNodeSet("tenant", "platform"),
+			want:    types.NamespacedName{Namespace: "platform", Name: "slurm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.nodeset.ClusterKey(); got != tt.want {
+				t.Errorf("ClusterKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterReferenceGrant_Allows(t *testing.T) {
+	tests := []struct {
+		name        string
+		grant       *ClusterReferenceGrant
+		namespace   string
+		clusterName string
+		want        bool
+	}{
+		{
+			name:        "All clusters",
+			grant:       newGrant("platform", []string{"tenant"}),
+			namespace:   "tenant",
+			clusterName: "slurm",
+			want:        true,
+		},
+		{
+			name:        "Named cluster",
+			grant:       newGrant("platform", []string{"other", "tenant"}, "slurm"),
+			namespace:   "tenant",
+			clusterName: "slurm",
+			want:        true,
+		},
+		{
+			name:        "Other cluster",
+			grant:       newGrant("platform", []string{"tenant"}, "other"),
+			namespace:   "tenant",
+			clusterName: "slurm",
+			want:        false,
+		},
+		{
+			name:        "Other namespace",
+			grant:       newGrant("platform", []string{"other"}),
+			namespace:   "tenant",
+			clusterName: "slurm",
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.grant.Allows(tt.namespace, tt.clusterName); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateClusterReference(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		nodeset *NodeSet
+		grants  []client.Object
+		wantErr bool
+	}{
+		{
+			name:    "Same namespace",
+			nodeset: newClusterRefNodeSet("tenant", ""),
+			wantErr: false,
+		},
+		{
+			name:    "Same namespace, explicit",
+			nodeset: newClusterRefNodeSet("tenant", "tenant"),
+			wantErr: false,
+		},
+		{
+			name:    "Granted",
+			nodeset: newClusterRefNodeSet("tenant", "platform"),
+			grants:  []client.Object{newGrant("platform", []string{"tenant"}, "slurm")},
+			wantErr: false,
+		},
+		{
+			name:    "No grant",
+			nodeset: newClusterRefNodeSet("tenant", "platform"),
+			wantErr: true,
+		},
+		{
+			name:    "Grant in other namespace",
+			nodeset: newClusterRefNodeSet("tenant", "platform"),
+			grants:  []client.Object{newGrant("tenant", []string{"tenant"})},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.grants...).Build()
+			err := validateClusterReference(context.TODO(), reader, tt.nodeset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateClusterReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ClusterReferenceGrantKind = "ClusterReferenceGrant"
+)
+
+var (
+	ClusterReferenceGrantGVK        = GroupVersion.WithKind(ClusterReferenceGrantKind)
+	ClusterReferenceGrantAPIVersion = GroupVersion.String()
+)
+
+// ClusterReferenceGrantSpec defines which namespaces may reference the
+// Clusters in the namespace of the grant.
+type ClusterReferenceGrantSpec struct {
+	// from lists the namespaces whose NodeSets may reference the Clusters.
+	// +kubebuilder:validation:MinItems=1
+	From []ClusterReferenceGrantFrom `json:"from"`
+
+	// clusterNames limits the grant to the named Clusters. When empty, all
+	// Clusters in the namespace of the grant may be referenced.
+	// +optional
+	ClusterNames []string `json:"clusterNames,omitempty"`
+}
+
+// ClusterReferenceGrantFrom describes a namespace that may reference Clusters.
+type ClusterReferenceGrantFrom struct {
+	// namespace is the namespace of the referencing NodeSets.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=crg
+//+kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterReferenceGrant allows NodeSets in other namespaces to reference the
+// Clusters in its namespace. It is owned by the Cluster owner.
+type ClusterReferenceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterReferenceGrantSpec `json:"spec,omitempty"`
+}
+
+// Allows returns true when the grant lets NodeSets in the namespace reference
+// the named Cluster.
+func (o *ClusterReferenceGrant) Allows(namespace, clusterName string) bool {
+	if len(o.Spec.ClusterNames) > 0 && !slices.Contains(o.Spec.ClusterNames, clusterName) {
+		return false
+	}
+	return slices.ContainsFunc(o.Spec.From, func(from ClusterReferenceGrantFrom) bool {
+		return from.Namespace == namespace
+	})
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterReferenceGrantList contains a list of ClusterReferenceGrant
+type ClusterReferenceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterReferenceGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterReferenceGrant{}, &ClusterReferenceGrantList{})
+}
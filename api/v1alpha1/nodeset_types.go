@@ -30,6 +30,12 @@ type NodeSetSpec struct {
 	// belongs to. This will be matched with the name in Cluster CRD.
 	ClusterName string `json:"clusterName"`
 
+	// clusterNamespace is the namespace of the Cluster CRD. When empty, the
+	// Cluster is looked up in the namespace of the NodeSet. A Cluster in
+	// another namespace must grant the reference with a ClusterReferenceGrant.
+	// +optional
+	ClusterNamespace string `json:"clusterNamespace,omitempty"`
+
 	// replicas is the desired number of replicas of the given Template.
 	// These are replicas in the sense that they are instantiations of the
 	// same Template, but individual replicas also have a consistent identity.
@@ -37,6 +43,38 @@ type NodeSetSpec struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// autoscaling lets the NodeSet controller scale replicas from the running
+	// and pending jobs in Slurm, without an external autoscaler. While set,
+	// the controller manages `replicas`.
+	// +optional
+	Autoscaling *NodeSetAutoscaling `json:"autoscaling,omitempty"`
+
+	// powerSave lets Slurm power saving decide which pods run. A pod is
+	// created when slurmctld resumes its node, and deleted when slurmctld
+	// suspends it, through the power save endpoint of the operator. `replicas`
+	// bounds the ordinals that can be resumed.
+	// +optional
+	PowerSave *NodeSetPowerSave `json:"powerSave,omitempty"`
+
+	// scaleIn delays and commits scale-in, so flapping replicas do not
+	// repeatedly drain and undrain the Slurm nodes of the pods.
+	// +optional
+	ScaleIn *NodeSetScaleIn `json:"scaleIn,omitempty"`
+
+	// podDeletion configures how pods deleted outside of the controller are
+	// handled. Such a pod has its Slurm node set DOWN as soon as it is gone,
+	// when the node is still running jobs. The drain finalizer holds back its
+	// removal until the node is drained instead.
+	// +optional
+	PodDeletion *NodeSetPodDeletion `json:"podDeletion,omitempty"`
+
+	// remediation recreates the pods whose Slurm node stays DOWN, FAIL, or
+	// NOT_RESPONDING, and resumes the Slurm node once the new pod runs. Nodes
+	// whose reason was set outside of the controller (e.g. by an admin) are
+	// left alone.
+	// +optional
+	Remediation *NodeSetRemediation `json:"remediation,omitempty"`
+
 	// selector is a label query over pods that should match the replica count.
 	// It must match the pod template's labels.
 	// If empty, defaulted to labels on Pod Template.
@@ -181,12 +219,305 @@ type RollingUpdateNodeSetStrategy struct {
 	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
+// NodeSetAutoscaling configures the built-in Slurm-aware autoscaler.
+type NodeSetAutoscaling struct {
+	// minReplicas is the lower bound of replicas.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// maxReplicas is the upper bound of replicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// partition is the Slurm partition served by the NodeSet. Only pending
+	// jobs that may run in this partition request more replicas. If empty,
+	// all pending jobs are counted.
+	// +optional
+	Partition string `json:"partition,omitempty"`
+
+	// scaleUp limits how fast replicas are added.
+	// +optional
+	ScaleUp *NodeSetScalingPolicy `json:"scaleUp,omitempty"`
+
+	// scaleDown limits how fast replicas are removed.
+	// +optional
+	ScaleDown *NodeSetScalingPolicy `json:"scaleDown,omitempty"`
+}
+
+// NodeSetScalingPolicy limits replica changes in one direction.
+type NodeSetScalingPolicy struct {
+	// maxStep is the largest change of replicas at once. If zero, there is no
+	// limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxStep int32 `json:"maxStep,omitempty"`
+
+	// period is the shortest time between two changes of replicas.
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+}
+
+// NodeSetAutoscalingStatus is the last decision of the built-in autoscaler.
+type NodeSetAutoscalingStatus struct {
+	// desiredReplicas is the number of replicas the Slurm jobs call for,
+	// within the replica bounds.
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// busyNodes is the number of NodeSet Slurm nodes running jobs.
+	// +optional
+	BusyNodes int32 `json:"busyNodes,omitempty"`
+
+	// pendingNodes is the number of nodes requested by pending jobs.
+	// +optional
+	PendingNodes int32 `json:"pendingNodes,omitempty"`
+
+	// lastScaleUpTime is the last time replicas were added.
+	// +optional
+	LastScaleUpTime *metav1.Time `json:"lastScaleUpTime,omitempty"`
+
+	// lastScaleDownTime is the last time replicas were removed.
+	// +optional
+	LastScaleDownTime *metav1.Time `json:"lastScaleDownTime,omitempty"`
+}
+
+// NodeSetPowerSave configures Slurm power saving for a NodeSet.
+type NodeSetPowerSave struct {
+	// enabled lets Slurm power saving decide which pods run.
+	Enabled bool `json:"enabled"`
+}
+
+// NodeSetScaleIn configures how pods are condemned when replicas decrease.
+type NodeSetScaleIn struct {
+	// stabilizationWindow is how long a lower replica count must hold before
+	// pods are condemned. When replicas change within the window, the highest
+	// replica count seen is used.
+	// +optional
+	StabilizationWindow *metav1.Duration `json:"stabilizationWindow,omitempty"`
+
+	// minDrainDuration is how long a started scale-in is committed to. Until
+	// it passes, the condemned pods keep draining even when replicas increase
+	// again.
+	// +optional
+	MinDrainDuration *metav1.Duration `json:"minDrainDuration,omitempty"`
+
+	// drainTimeout is how long a condemned pod may wait for its Slurm node to
+	// drain before drainTimeoutAction is applied. It also applies to pods
+	// replaced by rolling updates. If unset, pods wait for their jobs to end.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+
+	// drainTimeoutAction is applied once drainTimeout passed.
+	// Defaults to Wait.
+	// +kubebuilder:validation:Enum=Wait;RequeueJobs;CancelJobs;ForceDelete
+	// +optional
+	DrainTimeoutAction DrainTimeoutActionType `json:"drainTimeoutAction,omitempty"`
+
+	// policy selects which pods are condemned first, from the state of their
+	// Slurm nodes. Pods that are not running and ready, or already cordoned,
+	// are always condemned first. Ties fall back to the deletion cost, then
+	// the ordinal. If empty, pods are condemned by their deletion cost,
+	// deadline, and ordinal.
+	// +kubebuilder:validation:Enum=PreferIdle;LeastAllocatedCPUs;EarliestDeadline;HighestOrdinal;Newest
+	// +optional
+	Policy ScaleInPolicyType `json:"policy,omitempty"`
+
+	// manageNodeWeight sets the Weight of each Slurm node from the scale-in
+	// order of its pod. Slurm schedules jobs on the nodes with the lowest weight
+	// first, so the pods condemned first get the highest weight and stay free
+	// of new jobs. Weights set in slurm.conf are overridden, and restored once
+	// disabled.
+	// +optional
+	ManageNodeWeight bool `json:"manageNodeWeight,omitempty"`
+}
+
+// ScaleInPolicyType is a string enumeration of the strategies that select
+// which pods are condemned first on scale-in.
+// +enum
+type ScaleInPolicyType string
+
+const (
+	// PreferIdleScaleInPolicyType condemns pods whose Slurm node runs no jobs
+	// first.
+	PreferIdleScaleInPolicyType ScaleInPolicyType = "PreferIdle"
+
+	// LeastAllocatedCPUsScaleInPolicyType condemns pods whose Slurm node has
+	// the fewest CPUs allocated to jobs first.
+	LeastAllocatedCPUsScaleInPolicyType ScaleInPolicyType = "LeastAllocatedCPUs"
+
+	// EarliestDeadlineScaleInPolicyType condemns pods whose Slurm node jobs
+	// end the earliest first.
+	EarliestDeadlineScaleInPolicyType ScaleInPolicyType = "EarliestDeadline"
+
+	// HighestOrdinalScaleInPolicyType condemns pods with the highest ordinal
+	// first.
+	HighestOrdinalScaleInPolicyType ScaleInPolicyType = "HighestOrdinal"
+
+	// NewestScaleInPolicyType condemns the most recently created pods first.
+	NewestScaleInPolicyType ScaleInPolicyType = "Newest"
+)
+
+// NodeSetPodDeletion configures how pods deleted outside of the controller are
+// handled.
+type NodeSetPodDeletion struct {
+	// requeueJobs allows the jobs running on the Slurm node of the deleted pod
+	// to be requeued before the node is set DOWN, so Slurm requeues them
+	// instead of ending them with NODE_FAIL.
+	// +optional
+	RequeueJobs bool `json:"requeueJobs,omitempty"`
+
+	// drainFinalizer puts a finalizer on new pods. A deleted pod is then
+	// cordoned and its Slurm node drained, and the pod is only removed once
+	// the node is drained or drainTimeout passed. The kubelet still stops the
+	// containers after the termination grace period of the pod.
+	// +optional
+	DrainFinalizer bool `json:"drainFinalizer,omitempty"`
+
+	// drainTimeout is how long the drain finalizer holds back a deleted pod,
+	// counted from the deletion request. Defaults to the termination grace
+	// period of the pod.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+}
+
+// NodeSetRemediation configures how pods whose Slurm node stays DOWN, FAIL, or
+// NOT_RESPONDING are remediated.
+type NodeSetRemediation struct {
+	// gracePeriod is how long the Slurm node must stay DOWN, FAIL, or
+	// NOT_RESPONDING before its pod is recreated.
+	// Defaults to 5m.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
+	// maxRemediations is the number of pods of the NodeSet that may be
+	// recreated within window.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRemediations *int32 `json:"maxRemediations,omitempty"`
+
+	// window is the period over which maxRemediations is counted.
+	// Defaults to 10m.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// slurmUser is the `SlurmUser` of the Slurm cluster. slurmctld sets the
+	// reasons of the nodes it sets DOWN or FAIL as this user, other reasons
+	// (e.g. set by an admin) are left alone.
+	// Defaults to "slurm".
+	// +optional
+	SlurmUser string `json:"slurmUser,omitempty"`
+}
+
+// DrainTimeoutActionType is a string enumeration of the actions applied to a
+// condemned pod whose Slurm node did not drain within the drain timeout.
+// +enum
+type DrainTimeoutActionType string
+
+const (
+	// WaitDrainTimeoutActionType keeps waiting for the jobs to end. The drain
+	// timeout is only reported.
+	WaitDrainTimeoutActionType DrainTimeoutActionType = "Wait"
+
+	// RequeueJobsDrainTimeoutActionType makes the jobs on the Slurm node
+	// requeueable, then sets the node DOWN so Slurm requeues them.
+	RequeueJobsDrainTimeoutActionType DrainTimeoutActionType = "RequeueJobs"
+
+	// CancelJobsDrainTimeoutActionType cancels the jobs running on the Slurm
+	// node, including their allocation on other nodes.
+	CancelJobsDrainTimeoutActionType DrainTimeoutActionType = "CancelJobs"
+
+	// ForceDeleteDrainTimeoutActionType deletes the pod while its jobs are
+	// still running.
+	ForceDeleteDrainTimeoutActionType DrainTimeoutActionType = "ForceDelete"
+)
+
+// NodeSetScaleInStatus is the pending and the committed scale-in, and the
+// latest drain timeouts.
+type NodeSetScaleInStatus struct {
+	// pendingReplicas is the lower replica count waiting for the
+	// stabilization window.
+	// +optional
+	PendingReplicas *int32 `json:"pendingReplicas,omitempty"`
+
+	// pendingSince is when the lower replica count was first seen.
+	// +optional
+	PendingSince *metav1.Time `json:"pendingSince,omitempty"`
+
+	// replicas is the replica count the started scale-in is committed to.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// startTime is when pods were first condemned for the scale-in.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// drainTimeouts are the latest condemned pods whose Slurm node did not
+	// drain within the drain timeout.
+	// +optional
+	// +listType=atomic
+	DrainTimeouts []NodeSetDrainTimeout `json:"drainTimeouts,omitempty"`
+}
+
+// NodeSetDrainTimeout records the action applied to a condemned pod whose Slurm
+// node did not drain within the drain timeout.
+type NodeSetDrainTimeout struct {
+	// pod is the name of the condemned pod.
+	Pod string `json:"pod"`
+
+	// action is the action applied.
+	Action DrainTimeoutActionType `json:"action"`
+
+	// jobs are the Slurm jobs that were running on the node of the pod.
+	// +optional
+	// +listType=atomic
+	Jobs []int32 `json:"jobs,omitempty"`
+
+	// time is when the action was applied.
+	Time metav1.Time `json:"time"`
+}
+
+// NodeSetRemediationStatus is the latest remediations of the NodeSet.
+type NodeSetRemediationStatus struct {
+	// remediations are the latest pods recreated for the state of their Slurm
+	// node. The same pod coming back often hints at flapping hardware.
+	// +optional
+	// +listType=atomic
+	Remediations []NodeSetPodRemediation `json:"remediations,omitempty"`
+}
+
+// NodeSetPodRemediation records a pod recreated for the state of its Slurm
+// node.
+type NodeSetPodRemediation struct {
+	// pod is the name of the recreated pod.
+	Pod string `json:"pod"`
+
+	// state is the state of the Slurm node (e.g. `DOWN+NOT_RESPONDING`).
+	State string `json:"state"`
+
+	// reason is the reason of the Slurm node.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// time is when the pod was recreated.
+	Time metav1.Time `json:"time"`
+}
+
+// NodeSet condition types.
+const (
+	// NodeSetSlurmUnreachable indicates whether the Slurm cluster of the
+	// NodeSet cannot be reached. While true, pods are not deleted for scale-in
+	// or rolling updates because their Slurm nodes cannot be drained.
+	NodeSetSlurmUnreachable = "SlurmUnreachable"
+)
+
 // NodeSetStatus defines the observed state of NodeSet
 type NodeSetStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
 	// Total number of non-terminated pods targeted by this NodeSet (their labels match the Selector).
+	// The scale subresource reports it as the current replicas.
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
 
@@ -202,6 +533,12 @@ type NodeSetStatus struct {
 	// +optional
 	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
 
+	// usableReplicas is the number of ready pods targeted by this NodeSet whose Slurm node can run
+	// jobs. The Slurm node is IDLE, MIXED, or ALLOCATED, and not DRAIN, FAIL, MAINTENANCE, or
+	// NOT_RESPONDING.
+	// +optional
+	UsableReplicas int32 `json:"usableReplicas,omitempty"`
+
 	// Total number of unavailable pods targeted by this NodeSet. This is the total number of
 	// pods that are still required for the NodeSet to have 100% available capacity. They may
 	// either be pods that are running but not yet available or pods that still have not been created.
@@ -231,6 +568,20 @@ type NodeSetStatus struct {
 	// +optional
 	SlurmDrain int32 `json:"slurmDrain,omitempty"`
 
+	// autoscaling is the last decision of the built-in autoscaler.
+	// +optional
+	Autoscaling *NodeSetAutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// scaleIn is the pending and the committed scale-in, and the latest drain
+	// timeouts.
+	// +optional
+	ScaleIn *NodeSetScaleInStatus `json:"scaleIn,omitempty"`
+
+	// remediation is the latest remediations of pods whose Slurm node stayed
+	// DOWN, FAIL, or NOT_RESPONDING.
+	// +optional
+	Remediation *NodeSetRemediationStatus `json:"remediation,omitempty"`
+
 	// observedGeneration is the most recent generation observed for this NodeSet. It corresponds to the
 	// NodeSet's generation, which is updated on mutation by the API Server.
 	// +optional
@@ -254,17 +605,19 @@ type NodeSetStatus struct {
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 
-	// Add Selector to status for HPA support in the scale subresource.
+	// selector is the label selector of the NodeSet pods, in string form. The scale subresource
+	// reports it, so the HPA can find the pods for resource metrics.
 	Selector string `json:"selector"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:shortName=nodesets;nss
-//+kubebuilder:subresource:scale:specpath=".spec.replicas",statuspath=".status.currentNumberScheduled",selectorpath=".status.selector"
+//+kubebuilder:subresource:scale:specpath=".spec.replicas",statuspath=".status.replicas",selectorpath=".status.selector"
 //+kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".status.replicas",priority=0,description="The current number of pods."
 //+kubebuilder:printcolumn:name="UPDATED",type="integer",JSONPath=".status.updatedReplicas",priority=0,description="The number of pods updated."
 //+kubebuilder:printcolumn:name="READY",type="integer",JSONPath=".status.readyReplicas",priority=0,description="The number of pods ready."
+//+kubebuilder:printcolumn:name="USABLE",type="integer",JSONPath=".status.usableReplicas",priority=1,description="The number of pods usable by Slurm."
 //+kubebuilder:printcolumn:name="IDLE",type="integer",JSONPath=".status.slurmIdle",priority=1,description="The number of IDLE slurm nodes."
 //+kubebuilder:printcolumn:name="ALLOCATED",type="integer",JSONPath=".status.slurmAllocated",priority=1,description="The number of ALLOCATED/MIXED slurm nodes."
 //+kubebuilder:printcolumn:name="DOWN",type="integer",JSONPath=".status.slurmDown",priority=1,description="The number of DOWN slurm nodes."
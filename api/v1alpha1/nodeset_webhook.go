@@ -14,6 +14,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -28,7 +29,7 @@ func (r *NodeSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		WithDefaulter(r).
-		WithValidator(r).
+		WithValidator(&nodeSetValidator{NodeSet: r, reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
@@ -88,6 +89,9 @@ func (r *NodeSet) ValidateUpdate(ctx context.Context, oldObj runtime.Object, new
 	if newNodeSet.Spec.ClusterName != oldNodeSet.Spec.ClusterName {
 		errs = append(errs, fmt.Errorf("updates to `NodeSet.Spec.ClusterName` is forbidden. %v", errMsgStub))
 	}
+	if newNodeSet.Spec.ClusterNamespace != oldNodeSet.Spec.ClusterNamespace {
+		errs = append(errs, fmt.Errorf("updates to `NodeSet.Spec.ClusterNamespace` is forbidden. %v", errMsgStub))
+	}
 	if newNodeSet.Spec.ServiceName != oldNodeSet.Spec.ServiceName {
 		errs = append(errs, fmt.Errorf("updates to `NodeSet.Spec.ServiceName` is forbidden. %v", errMsgStub))
 	}
@@ -103,6 +107,47 @@ func (r *NodeSet) ValidateDelete(ctx context.Context, obj runtime.Object) (admis
 	return nil, nil
 }
 
+// nodeSetValidator extends the NodeSet validation with checks that need to
+// read other objects.
+type nodeSetValidator struct {
+	*NodeSet
+	reader client.Reader
+}
+
+var _ webhook.CustomValidator = &nodeSetValidator{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (v *nodeSetValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	warns, err := v.NodeSet.ValidateCreate(ctx, obj)
+	if err != nil {
+		return warns, err
+	}
+	return warns, validateClusterReference(ctx, v.reader, obj.(*NodeSet))
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (v *nodeSetValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	warns, err := v.NodeSet.ValidateUpdate(ctx, oldObj, newObj)
+	if err != nil {
+		return warns, err
+	}
+	return warns, validateClusterReference(ctx, v.reader, newObj.(*NodeSet))
+}
+
+// validateClusterReference rejects NodeSets that reference a Cluster in
+// another namespace without a ClusterReferenceGrant.
+func validateClusterReference(ctx context.Context, reader client.Reader, nodeset *NodeSet) error {
+	granted, err := IsClusterReferenceGranted(ctx, reader, nodeset)
+	if err != nil {
+		return fmt.Errorf("failed to check the reference to Cluster(%s): %w", nodeset.ClusterKey(), err)
+	}
+	if !granted {
+		return fmt.Errorf("`NodeSet.Spec.ClusterNamespace` references Cluster(%s), but no ClusterReferenceGrant in namespace %q allows namespace %q",
+			nodeset.ClusterKey(), nodeset.ClusterKey().Namespace, nodeset.GetNamespace())
+	}
+	return nil
+}
+
 func validateNodeSet(r *NodeSet) (admission.Warnings, []error) {
 	var warns admission.Warnings
 	var errs []error
@@ -121,6 +166,70 @@ func validateNodeSet(r *NodeSet) (admission.Warnings, []error) {
 			r.Spec.UpdateStrategy.Type, RollingUpdateNodeSetStrategyType, OnDeleteNodeSetStrategyType))
 	}
 
+	if r.Spec.Autoscaling != nil && r.Spec.Autoscaling.MinReplicas > r.Spec.Autoscaling.MaxReplicas {
+		errs = append(errs, fmt.Errorf("`NodeSet.Spec.Autoscaling.MinReplicas` must not exceed `NodeSet.Spec.Autoscaling.MaxReplicas`. Got: %d > %d",
+			r.Spec.Autoscaling.MinReplicas, r.Spec.Autoscaling.MaxReplicas))
+	}
+
+	if r.Spec.Autoscaling != nil && r.Spec.PowerSave != nil && r.Spec.PowerSave.Enabled {
+		errs = append(errs, fmt.Errorf("`NodeSet.Spec.Autoscaling` and `NodeSet.Spec.PowerSave` cannot be used together"))
+	}
+
+	if scaleIn := r.Spec.ScaleIn; scaleIn != nil {
+		if scaleIn.StabilizationWindow != nil && scaleIn.StabilizationWindow.Duration < 0 {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.ScaleIn.StabilizationWindow` must not be negative. Got: %v",
+				scaleIn.StabilizationWindow.Duration))
+		}
+		if scaleIn.MinDrainDuration != nil && scaleIn.MinDrainDuration.Duration < 0 {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.ScaleIn.MinDrainDuration` must not be negative. Got: %v",
+				scaleIn.MinDrainDuration.Duration))
+		}
+		if scaleIn.DrainTimeout != nil && scaleIn.DrainTimeout.Duration < 0 {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.ScaleIn.DrainTimeout` must not be negative. Got: %v",
+				scaleIn.DrainTimeout.Duration))
+		}
+		switch scaleIn.DrainTimeoutAction {
+		case "", WaitDrainTimeoutActionType, RequeueJobsDrainTimeoutActionType,
+			CancelJobsDrainTimeoutActionType, ForceDeleteDrainTimeoutActionType:
+			// valid
+		default:
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.ScaleIn.DrainTimeoutAction` is not valid. Got: %v. Expected of: %s; %s; %s; %s",
+				scaleIn.DrainTimeoutAction, WaitDrainTimeoutActionType, RequeueJobsDrainTimeoutActionType,
+				CancelJobsDrainTimeoutActionType, ForceDeleteDrainTimeoutActionType))
+		}
+		switch scaleIn.Policy {
+		case "", PreferIdleScaleInPolicyType, LeastAllocatedCPUsScaleInPolicyType, EarliestDeadlineScaleInPolicyType,
+			HighestOrdinalScaleInPolicyType, NewestScaleInPolicyType:
+			// valid
+		default:
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.ScaleIn.Policy` is not valid. Got: %v. Expected of: %s; %s; %s; %s; %s",
+				scaleIn.Policy, PreferIdleScaleInPolicyType, LeastAllocatedCPUsScaleInPolicyType, EarliestDeadlineScaleInPolicyType,
+				HighestOrdinalScaleInPolicyType, NewestScaleInPolicyType))
+		}
+	}
+
+	if podDeletion := r.Spec.PodDeletion; podDeletion != nil {
+		if podDeletion.DrainTimeout != nil && podDeletion.DrainTimeout.Duration < 0 {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.PodDeletion.DrainTimeout` must not be negative. Got: %v",
+				podDeletion.DrainTimeout.Duration))
+		}
+	}
+
+	if remediation := r.Spec.Remediation; remediation != nil {
+		if remediation.GracePeriod != nil && remediation.GracePeriod.Duration < 0 {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.Remediation.GracePeriod` must not be negative. Got: %v",
+				remediation.GracePeriod.Duration))
+		}
+		if remediation.MaxRemediations != nil && *remediation.MaxRemediations < 1 {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.Remediation.MaxRemediations` must be at least 1. Got: %v",
+				*remediation.MaxRemediations))
+		}
+		if remediation.Window != nil && remediation.Window.Duration < 0 {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.Remediation.Window` must not be negative. Got: %v",
+				remediation.Window.Duration))
+		}
+	}
+
 	if r.Spec.PersistentVolumeClaimRetentionPolicy != nil {
 		switch r.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted {
 		case RetainPersistentVolumeClaimRetentionPolicyType:
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_validateNodeSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     NodeSetSpec
+		wantErrs int
+	}{
+		{
+			name: "Valid",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+			},
+			wantErrs: 0,
+		},
+		{
+			name:     "Empty",
+			spec:     NodeSetSpec{},
+			wantErrs: 2,
+		},
+		{
+			name: "Autoscaling",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				Autoscaling: &NodeSetAutoscaling{
+					MinReplicas: 1,
+					MaxReplicas: 4,
+				},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "Autoscaling min exceeds max",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				Autoscaling: &NodeSetAutoscaling{
+					MinReplicas: 4,
+					MaxReplicas: 1,
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "Autoscaling with power save",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				Autoscaling: &NodeSetAutoscaling{
+					MaxReplicas: 4,
+				},
+				PowerSave: &NodeSetPowerSave{
+					Enabled: true,
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "Scale-in",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				ScaleIn: &NodeSetScaleIn{
+					StabilizationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+					MinDrainDuration:    &metav1.Duration{Duration: time.Minute},
+					DrainTimeout:        &metav1.Duration{Duration: time.Hour},
+					DrainTimeoutAction:  RequeueJobsDrainTimeoutActionType,
+					Policy:              PreferIdleScaleInPolicyType,
+					ManageNodeWeight:    true,
+				},
+				PodDeletion: &NodeSetPodDeletion{
+					RequeueJobs:    true,
+					DrainFinalizer: true,
+					DrainTimeout:   &metav1.Duration{Duration: 10 * time.Minute},
+				},
+				Remediation: &NodeSetRemediation{
+					GracePeriod:     &metav1.Duration{Duration: 5 * time.Minute},
+					MaxRemediations: ptr.To[int32](2),
+					Window:          &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "Scale-in negative durations",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				ScaleIn: &NodeSetScaleIn{
+					StabilizationWindow: &metav1.Duration{Duration: -time.Minute},
+					MinDrainDuration:    &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			wantErrs: 2,
+		},
+		{
+			name: "Scale-in invalid drain timeout action and policy",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				ScaleIn: &NodeSetScaleIn{
+					DrainTimeout:       &metav1.Duration{Duration: -time.Hour},
+					DrainTimeoutAction: "Evict",
+					Policy:             "Random",
+				},
+			},
+			wantErrs: 3,
+		},
+		{
+			name: "Pod deletion negative drain timeout",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				PodDeletion: &NodeSetPodDeletion{
+					DrainFinalizer: true,
+					DrainTimeout:   &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "Invalid remediation",
+			spec: NodeSetSpec{
+				ServiceName: "slurmd",
+				UpdateStrategy: NodeSetUpdateStrategy{
+					Type: RollingUpdateNodeSetStrategyType,
+				},
+				Remediation: &NodeSetRemediation{
+					GracePeriod:     &metav1.Duration{Duration: -time.Minute},
+					MaxRemediations: ptr.To[int32](0),
+					Window:          &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			wantErrs: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := validateNodeSet(&NodeSet{Spec: tt.spec})
+			if len(errs) != tt.wantErrs {
+				t.Errorf("validateNodeSet() errs = %v, wantErrs %v", errs, tt.wantErrs)
+			}
+		})
+	}
+}
@@ -3,6 +3,10 @@
 
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // Prefixes
 const (
 	SlinkyPrefix = "slinky.slurm.net/"
@@ -10,11 +14,49 @@ const (
 	NodeSetPrefix = "nodeset." + SlinkyPrefix
 )
 
+// Well Known Finalizers
+const (
+	// FinalizerCluster holds back Cluster deletion until no NodeSets reference it.
+	// NOTE: Set by the Cluster controller.
+	FinalizerCluster = SlinkyPrefix + "cluster"
+
+	// FinalizerPodDrain holds back NodeSet Pod deletion until its Slurm node is drained.
+	// NOTE: Set by the NodeSet controller when `podDeletion.drainFinalizer` is enabled.
+	FinalizerPodDrain = NodeSetPrefix + "pod-drain"
+)
+
 // Well Known Annotations
 const (
 	// AnnotationPodCordon indicates NodeSet Pods that should be DRAIN[ING|ED] in Slurm.
 	AnnotationPodCordon = NodeSetPrefix + "pod-cordon"
 
+	// AnnotationPodCordonTime stores a time.RFC3339 timestamp, indicating when the NodeSet Pod was cordoned. The drain
+	// timeout of a condemned pod is counted from it.
+	// NOTE: Set by the NodeSet controller.
+	AnnotationPodCordonTime = NodeSetPrefix + "pod-cordon-time"
+
+	// AnnotationPodSlurmDrain stores the reason of a DRAIN set on the Slurm node of the NodeSet Pod by someone other
+	// than the NodeSet controller (e.g. `scontrol update state=drain`). Pods with it are preferred for scale-in. It is
+	// removed once the Slurm node is undrained.
+	// NOTE: Set by the NodeSet controller.
+	AnnotationPodSlurmDrain = NodeSetPrefix + "pod-slurm-drain"
+
+	// AnnotationPodSlurmUnhealthyTime stores a time.RFC3339 timestamp, indicating when the Slurm node of the NodeSet Pod
+	// was first seen DOWN, FAIL, or NOT_RESPONDING. The remediation grace period is counted from it.
+	// NOTE: Set by the NodeSet controller when `remediation` is enabled.
+	AnnotationPodSlurmUnhealthyTime = NodeSetPrefix + "pod-slurm-unhealthy-time"
+
+	// AnnotationPodSlurmPowerDown stores a time.RFC3339 timestamp, indicating when the NodeSet was scaled-in for the
+	// power down requested on the Slurm node of the NodeSet Pod (e.g. `scontrol power down`). Pods with it are
+	// condemned first on scale-in.
+	// NOTE: Set by the NodeSet controller.
+	AnnotationPodSlurmPowerDown = NodeSetPrefix + "pod-slurm-power-down"
+
+	// AnnotationPodSlurmWeight stores the weight the Slurm node of the NodeSet Pod had before `manageNodeWeight` set
+	// it. The weight is restored, and the annotation removed, once `manageNodeWeight` is disabled.
+	// NOTE: Set by the NodeSet controller.
+	AnnotationPodSlurmWeight = NodeSetPrefix + "pod-slurm-weight"
+
 	// LabelPodDeletionCost can be used to set to an int32 that represent the cost of deleting a pod compared to other
 	// pods belonging to the same ReplicaSet. Pods with lower deletion cost are preferred to be deleted before pods
 	// with higher deletion cost.
@@ -26,6 +68,15 @@ const (
 	// workload by. Pods an earlier daedline are preferred to be deleted before pods with a later deadline.
 	// NOTE: this is honored on a best-effort basis, and does not offer guarantees on pod deletion order.
 	AnnotationPodDeadline = NodeSetPrefix + "pod-deadline"
+
+	// AnnotationIgnoreSlurmUnreachable, when "true" on a NodeSet, lets it scale-in and update pods while its Slurm
+	// cluster is unreachable, without draining the Slurm nodes first. Intended for clusters being torn down.
+	AnnotationIgnoreSlurmUnreachable = NodeSetPrefix + "ignore-slurm-unreachable"
+
+	// AnnotationPowerSaveResumed lists the comma separated ordinals of the NodeSet pods that Slurm power saving has
+	// resumed. Only these pods are run when the NodeSet has power saving enabled.
+	// NOTE: Set by the power save endpoint of the operator.
+	AnnotationPowerSaveResumed = NodeSetPrefix + "power-save-resumed"
 )
 
 // Well Known Labels
@@ -38,3 +89,21 @@ const (
 	// NOTE: Set by the NodeSet controller.
 	LabelNodeSetPodIndex = NodeSetPrefix + "pod-index"
 )
+
+// Well Known Pod Conditions
+const (
+	// PodConditionSlurmNodeReady is the readiness gate of NodeSet Pods. It is true when the Slurm node of the Pod is
+	// registered and responding, and not DOWN or INVALID, so the Pod is only Ready once Slurm can schedule on it.
+	// NOTE: Set by the NodeSet controller.
+	PodConditionSlurmNodeReady corev1.PodConditionType = SlinkyPrefix + "SlurmNodeReady"
+
+	// PodConditionSlurmNodeState reports the state of the Slurm node of the NodeSet Pod (e.g. `IDLE+DRAIN`) in its
+	// message. It is false while the Slurm node is not registered.
+	// NOTE: Set by the NodeSet controller.
+	PodConditionSlurmNodeState corev1.PodConditionType = SlinkyPrefix + "SlurmNodeState"
+
+	// PodConditionSlurmNodeReason reports the reason of the Slurm node of the NodeSet Pod in its message. It is false
+	// while the Slurm node has no reason.
+	// NOTE: Set by the NodeSet controller.
+	PodConditionSlurmNodeReason corev1.PodConditionType = SlinkyPrefix + "SlurmNodeReason"
+)
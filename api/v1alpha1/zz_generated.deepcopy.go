@@ -19,7 +19,7 @@ func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -73,10 +73,153 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPartitionStatus) DeepCopyInto(out *ClusterPartitionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPartitionStatus.
+func (in *ClusterPartitionStatus) DeepCopy() *ClusterPartitionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPartitionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPowerSave) DeepCopyInto(out *ClusterPowerSave) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPowerSave.
+func (in *ClusterPowerSave) DeepCopy() *ClusterPowerSave {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPowerSave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReferenceGrant) DeepCopyInto(out *ClusterReferenceGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReferenceGrant.
+func (in *ClusterReferenceGrant) DeepCopy() *ClusterReferenceGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReferenceGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterReferenceGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReferenceGrantFrom) DeepCopyInto(out *ClusterReferenceGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReferenceGrantFrom.
+func (in *ClusterReferenceGrantFrom) DeepCopy() *ClusterReferenceGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReferenceGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReferenceGrantList) DeepCopyInto(out *ClusterReferenceGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterReferenceGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReferenceGrantList.
+func (in *ClusterReferenceGrantList) DeepCopy() *ClusterReferenceGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReferenceGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterReferenceGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReferenceGrantSpec) DeepCopyInto(out *ClusterReferenceGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]ClusterReferenceGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterNames != nil {
+		in, out := &in.ClusterNames, &out.ClusterNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReferenceGrantSpec.
+func (in *ClusterReferenceGrantSpec) DeepCopy() *ClusterReferenceGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReferenceGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
-	out.Token = in.Token
+	in.Token.DeepCopyInto(&out.Token)
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClusterTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PowerSave != nil {
+		in, out := &in.PowerSave, &out.PowerSave
+		*out = new(ClusterPowerSave)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -92,6 +235,29 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
+	if in.HealthyServers != nil {
+		in, out := &in.HealthyServers, &out.HealthyServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastPingTime != nil {
+		in, out := &in.LastPingTime, &out.LastPingTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PingLatency != nil {
+		in, out := &in.PingLatency, &out.PingLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TokenExpiresAt != nil {
+		in, out := &in.TokenExpiresAt, &out.TokenExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = make([]ClusterPartitionStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -111,9 +277,54 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTLS) DeepCopyInto(out *ClusterTLS) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(ClusterTLSCABundle)
+		**out = **in
+	}
+	if in.PinnedCertificates != nil {
+		in, out := &in.PinnedCertificates, &out.PinnedCertificates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTLS.
+func (in *ClusterTLS) DeepCopy() *ClusterTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTLSCABundle) DeepCopyInto(out *ClusterTLSCABundle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTLSCABundle.
+func (in *ClusterTLSCABundle) DeepCopy() *ClusterTLSCABundle {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTLSCABundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterToken) DeepCopyInto(out *ClusterToken) {
 	*out = *in
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(ClusterTokenJWT)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterToken.
@@ -126,6 +337,26 @@ func (in *ClusterToken) DeepCopy() *ClusterToken {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTokenJWT) DeepCopyInto(out *ClusterTokenJWT) {
+	*out = *in
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTokenJWT.
+func (in *ClusterTokenJWT) DeepCopy() *ClusterTokenJWT {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTokenJWT)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 	*out = *in
@@ -153,6 +384,75 @@ func (in *NodeSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetAutoscaling) DeepCopyInto(out *NodeSetAutoscaling) {
+	*out = *in
+	if in.ScaleUp != nil {
+		in, out := &in.ScaleUp, &out.ScaleUp
+		*out = new(NodeSetScalingPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleDown != nil {
+		in, out := &in.ScaleDown, &out.ScaleDown
+		*out = new(NodeSetScalingPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetAutoscaling.
+func (in *NodeSetAutoscaling) DeepCopy() *NodeSetAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetAutoscalingStatus) DeepCopyInto(out *NodeSetAutoscalingStatus) {
+	*out = *in
+	if in.LastScaleUpTime != nil {
+		in, out := &in.LastScaleUpTime, &out.LastScaleUpTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScaleDownTime != nil {
+		in, out := &in.LastScaleDownTime, &out.LastScaleDownTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetAutoscalingStatus.
+func (in *NodeSetAutoscalingStatus) DeepCopy() *NodeSetAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetDrainTimeout) DeepCopyInto(out *NodeSetDrainTimeout) {
+	*out = *in
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetDrainTimeout.
+func (in *NodeSetDrainTimeout) DeepCopy() *NodeSetDrainTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetDrainTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSetList) DeepCopyInto(out *NodeSetList) {
 	*out = *in
@@ -200,6 +500,199 @@ func (in *NodeSetPersistentVolumeClaimRetentionPolicy) DeepCopy() *NodeSetPersis
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetPodDeletion) DeepCopyInto(out *NodeSetPodDeletion) {
+	*out = *in
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetPodDeletion.
+func (in *NodeSetPodDeletion) DeepCopy() *NodeSetPodDeletion {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetPodDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetPodRemediation) DeepCopyInto(out *NodeSetPodRemediation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetPodRemediation.
+func (in *NodeSetPodRemediation) DeepCopy() *NodeSetPodRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetPodRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetPowerSave) DeepCopyInto(out *NodeSetPowerSave) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetPowerSave.
+func (in *NodeSetPowerSave) DeepCopy() *NodeSetPowerSave {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetPowerSave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetRemediation) DeepCopyInto(out *NodeSetRemediation) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxRemediations != nil {
+		in, out := &in.MaxRemediations, &out.MaxRemediations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetRemediation.
+func (in *NodeSetRemediation) DeepCopy() *NodeSetRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetRemediationStatus) DeepCopyInto(out *NodeSetRemediationStatus) {
+	*out = *in
+	if in.Remediations != nil {
+		in, out := &in.Remediations, &out.Remediations
+		*out = make([]NodeSetPodRemediation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetRemediationStatus.
+func (in *NodeSetRemediationStatus) DeepCopy() *NodeSetRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetScaleIn) DeepCopyInto(out *NodeSetScaleIn) {
+	*out = *in
+	if in.StabilizationWindow != nil {
+		in, out := &in.StabilizationWindow, &out.StabilizationWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinDrainDuration != nil {
+		in, out := &in.MinDrainDuration, &out.MinDrainDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetScaleIn.
+func (in *NodeSetScaleIn) DeepCopy() *NodeSetScaleIn {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetScaleIn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetScaleInStatus) DeepCopyInto(out *NodeSetScaleInStatus) {
+	*out = *in
+	if in.PendingReplicas != nil {
+		in, out := &in.PendingReplicas, &out.PendingReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PendingSince != nil {
+		in, out := &in.PendingSince, &out.PendingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DrainTimeouts != nil {
+		in, out := &in.DrainTimeouts, &out.DrainTimeouts
+		*out = make([]NodeSetDrainTimeout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetScaleInStatus.
+func (in *NodeSetScaleInStatus) DeepCopy() *NodeSetScaleInStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetScaleInStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetScalingPolicy) DeepCopyInto(out *NodeSetScalingPolicy) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetScalingPolicy.
+func (in *NodeSetScalingPolicy) DeepCopy() *NodeSetScalingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetScalingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSetSpec) DeepCopyInto(out *NodeSetSpec) {
 	*out = *in
@@ -208,6 +701,31 @@ func (in *NodeSetSpec) DeepCopyInto(out *NodeSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(NodeSetAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PowerSave != nil {
+		in, out := &in.PowerSave, &out.PowerSave
+		*out = new(NodeSetPowerSave)
+		**out = **in
+	}
+	if in.ScaleIn != nil {
+		in, out := &in.ScaleIn, &out.ScaleIn
+		*out = new(NodeSetScaleIn)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDeletion != nil {
+		in, out := &in.PodDeletion, &out.PodDeletion
+		*out = new(NodeSetPodDeletion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(NodeSetRemediation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = new(v1.LabelSelector)
@@ -261,6 +779,21 @@ func (in *NodeSetSpec) DeepCopy() *NodeSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSetStatus) DeepCopyInto(out *NodeSetStatus) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(NodeSetAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleIn != nil {
+		in, out := &in.ScaleIn, &out.ScaleIn
+		*out = new(NodeSetScaleInStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(NodeSetRemediationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CollisionCount != nil {
 		in, out := &in.CollisionCount, &out.CollisionCount
 		*out = new(int32)
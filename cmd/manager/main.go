@@ -25,6 +25,8 @@ import (
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/cluster"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset"
+	"github.com/SlinkyProject/slurm-operator/internal/externalmetrics"
+	"github.com/SlinkyProject/slurm-operator/internal/powersave"
 	"github.com/SlinkyProject/slurm-operator/internal/resources"
 	//+kubebuilder:scaffold:imports
 )
@@ -47,6 +49,10 @@ type Flags struct {
 	probeAddr            string
 	secureMetrics        bool
 	enableHTTP2          bool
+	powerSaveAddr        string
+	powerSaveCert        string
+	externalMetricsAddr  string
+	externalMetricsCert  string
 }
 
 func parseFlags(flags *Flags) {
@@ -67,6 +73,34 @@ func parseFlags(flags *Flags) {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&flags.enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(
+		&flags.powerSaveAddr,
+		"power-save-bind-address",
+		"0",
+		("The address the Slurm power saving endpoint binds to. " +
+			"Use \"0\" to disable the endpoint."),
+	)
+	flag.StringVar(
+		&flags.powerSaveCert,
+		"power-save-cert-dir",
+		"",
+		("The directory holding the tls.crt and tls.key of the Slurm power saving endpoint. " +
+			"A self-signed certificate is generated when empty."),
+	)
+	flag.StringVar(
+		&flags.externalMetricsAddr,
+		"external-metrics-bind-address",
+		"0",
+		("The address the external metrics API server binds to. " +
+			"Use \"0\" to disable the server."),
+	)
+	flag.StringVar(
+		&flags.externalMetricsCert,
+		"external-metrics-cert-dir",
+		"",
+		("The directory holding the tls.crt and tls.key of the external metrics API server. " +
+			"A self-signed certificate is generated when empty."),
+	)
 	flag.Parse()
 }
 
@@ -130,6 +164,28 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeSet")
 		os.Exit(1)
 	}
+	if flags.powerSaveAddr != "0" {
+		if err := mgr.Add(&powersave.Server{
+			Client:      mgr.GetClient(),
+			BindAddress: flags.powerSaveAddr,
+			CertDir:     flags.powerSaveCert,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up power save server")
+			os.Exit(1)
+		}
+	}
+	if flags.externalMetricsAddr != "0" {
+		if err := mgr.Add(&externalmetrics.Server{
+			Client:        mgr.GetClient(),
+			APIReader:     mgr.GetAPIReader(),
+			SlurmClusters: slurmClusters,
+			BindAddress:   flags.externalMetricsAddr,
+			CertDir:       flags.externalMetricsCert,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up external metrics server")
+			os.Exit(1)
+		}
+	}
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	managerRolePath = "../../config/rbac/role.yaml"
+	helmRolePath    = "../../helm/slurm-operator/templates/operator/rbac.yaml"
+)
+
+var helmActionRegexp = regexp.MustCompile(`{{-?[^}]*-?}}`)
+
+// readHelmClusterRole returns the ClusterRole of the chart template, with the
+// template actions replaced by placeholders.
+func readHelmClusterRole(t *testing.T) *rbacv1.ClusterRole {
+	t.Helper()
+	data, err := os.ReadFile(helmRolePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, doc := range strings.Split(string(data), "\n---\n") {
+		doc = helmActionRegexp.ReplaceAllString(doc, "placeholder")
+		role := &rbacv1.ClusterRole{}
+		if err := yaml.Unmarshal([]byte(doc), role); err != nil {
+			continue
+		}
+		if role.Kind == "ClusterRole" {
+			return role
+		}
+	}
+	t.Fatalf("no ClusterRole found in %s", helmRolePath)
+	return nil
+}
+
+// allows reports whether any of the rules grants the verb on the resource.
+func allows(rules []rbacv1.PolicyRule, apiGroup, resource, verb string) bool {
+	for _, rule := range rules {
+		if hasOrWildcard(rule.APIGroups, apiGroup) &&
+			hasOrWildcard(rule.Resources, resource) &&
+			hasOrWildcard(rule.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOrWildcard(items []string, item string) bool {
+	for _, i := range items {
+		if i == item || i == rbacv1.ResourceAll {
+			return true
+		}
+	}
+	return false
+}
+
+// Test_helmClusterRole checks that the chart grants the operator every
+// permission generated from the kubebuilder RBAC markers.
+func Test_helmClusterRole(t *testing.T) {
+	data, err := os.ReadFile(managerRolePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	managerRole := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(data, managerRole); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	helmRole := readHelmClusterRole(t)
+
+	for _, rule := range managerRole.Rules {
+		for _, apiGroup := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				for _, verb := range rule.Verbs {
+					if !allows(helmRole.Rules, apiGroup, resource, verb) {
+						t.Errorf("%s does not grant %q on %q in API group %q, as %s does",
+							helmRolePath, verb, resource, apiGroup, managerRolePath)
+					}
+				}
+			}
+		}
+	}
+}
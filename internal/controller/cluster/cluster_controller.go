@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -18,11 +19,13 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/flowcontrol"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
@@ -38,18 +41,54 @@ const (
 
 func init() {
 	flag.IntVar(&maxConcurrentReconciles, "cluster-workers", maxConcurrentReconciles, "Max concurrent workers for Cluster controller.")
+	flag.DurationVar(&tokenExpiryWindow, "cluster-token-expiry-window", tokenExpiryWindow, "Warn when a static Cluster token expires within this duration.")
 }
 
 var (
-	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
-	durationStore = durationstore.NewDurationStore(durationstore.Greater)
+	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue,
+	// the soonest requeue wins so periodic pings are not delayed by token refreshes
+	durationStore = durationstore.NewDurationStore(durationstore.Less)
 
 	maxConcurrentReconciles = 1
 
 	onceBackoffGC     sync.Once
 	failedPodsBackoff = flowcontrol.NewBackOff(1*time.Second, 15*time.Minute)
 	requeueSecretTime = 10 * time.Second
+	negotiateTimeout  = 10 * time.Second
 	requeueReadyTime  = 30 * time.Second
+	tokenExpiryWindow = 7 * 24 * time.Hour
+)
+
+const (
+	authTokenKey = "auth-token"
+)
+
+// Cluster condition reasons.
+const (
+	reasonSecretFound              = "SecretFound"
+	reasonSecretNotFound           = "SecretNotFound"
+	reasonSecretError              = "SecretError"
+	reasonKeyMissing               = "KeyMissing"
+	reasonSecretNotResolved        = "SecretNotResolved"
+	reasonClientCreated            = "ClientCreated"
+	reasonClientError              = "ClientError"
+	reasonInvalidServer            = "InvalidServer"
+	reasonTLSError                 = "TLSError"
+	reasonClientNotConfigured      = "ClientNotConfigured"
+	reasonControllerUp             = "ControllerUp"
+	reasonControllerDown           = "ControllerDown"
+	reasonRequestFailed            = "RequestFailed"
+	reasonAuthenticationFailed     = "AuthenticationFailed"
+	reasonTokenAccepted            = "TokenAccepted"
+	reasonTokenRejected            = "TokenRejected"
+	reasonTokenRotated             = "TokenRotated"
+	reasonTokenNoExpiry            = "NoExpiry"
+	reasonTokenValid               = "TokenValid"
+	reasonTokenExpiring            = "TokenExpiring"
+	reasonTokenExpired             = "TokenExpired"
+	reasonNodeSetsReferenced       = "NodeSetsReferenced"
+	reasonNodeSetsDraining         = "NodeSetsDraining"
+	reasonVersionNegotiationFailed = "VersionNegotiationFailed"
 )
 
 // ClusterReconciler reconciles a Cluster object
@@ -61,13 +100,16 @@ type ClusterReconciler struct {
 	EventCh       chan event.GenericEvent
 
 	slurmControl  slurmcontrol.SlurmControlInterface
-	eventRecorder record.EventRecorderLogger
+	eventRecorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=clusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=clusters/finalizers,verbs=update
+//+kubebuilder:rbac:groups=slinky.slurm.net,resources=nodesets,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -106,35 +148,78 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.EventCh == nil {
 		return fmt.Errorf("EventCh cannot be nil")
 	}
-	r.eventRecorder = record.NewBroadcaster().NewRecorder(r.Scheme, corev1.EventSource{Component: "cluster-controller"})
+	r.eventRecorder = mgr.GetEventRecorderFor("cluster-controller")
 	r.slurmControl = slurmcontrol.NewSlurmControl(r.SlurmClusters)
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("cluster-controller").
-		For(&slinkyv1alpha1.Cluster{}).
+		// Ignore status-only updates, the status is refreshed on every ping.
+		For(&slinkyv1alpha1.Cluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&slinkyv1alpha1.NodeSet{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForNodeSets),
+		).
 		Watches(
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForSecrets),
 		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForConfigMaps),
+		).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
 		}).
 		Complete(r)
 }
 
+// enqueueRequestsForNodeSets queues the cluster referenced by the NodeSet, so
+// a deleting cluster notices when its NodeSets scale down or go away.
+func (r *ClusterReconciler) enqueueRequestsForNodeSets(
+	ctx context.Context,
+	o client.Object,
+) []reconcile.Request {
+	nodeset, ok := o.(*slinkyv1alpha1.NodeSet)
+	if !ok || nodeset.Spec.ClusterName == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: nodeset.ClusterKey(),
+		},
+	}
+}
+
 func (r *ClusterReconciler) enqueueRequestsForSecrets(
 	ctx context.Context,
 	o client.Object,
+) []reconcile.Request {
+	return r.enqueueRequestsForReferences(ctx, o, &corev1.Secret{}, clusterSecretRefs)
+}
+
+func (r *ClusterReconciler) enqueueRequestsForConfigMaps(
+	ctx context.Context,
+	o client.Object,
+) []reconcile.Request {
+	return r.enqueueRequestsForReferences(ctx, o, &corev1.ConfigMap{}, clusterConfigMapRefs)
+}
+
+// enqueueRequestsForReferences queues the clusters that reference the object,
+// as returned by refsFn, or all clusters when the object was deleted.
+func (r *ClusterReconciler) enqueueRequestsForReferences(
+	ctx context.Context,
+	o client.Object,
+	obj client.Object,
+	refsFn func(cluster *slinkyv1alpha1.Cluster) []string,
 ) []reconcile.Request {
 	requests := make([]reconcile.Request, 0)
 	isDeleted := false
 
-	// Lookup secret
-	secret := &corev1.Secret{}
-	secretNamespacedName := types.NamespacedName{
+	// Lookup object
+	namespacedName := types.NamespacedName{
 		Namespace: o.GetNamespace(),
 		Name:      o.GetName(),
 	}
-	if err := r.Get(ctx, secretNamespacedName, secret); err != nil {
+	if err := r.Get(ctx, namespacedName, obj); err != nil {
 		if apierrors.IsNotFound(err) {
 			isDeleted = true
 		}
@@ -149,11 +234,11 @@ func (r *ClusterReconciler) enqueueRequestsForSecrets(
 		return requests
 	}
 
-	// Queue a cluster request when the referenced secret was changed or deleted
+	// Queue a cluster request when the referenced object was changed or deleted
 	for _, cluster := range clusterList.Items {
 		if !isDeleted &&
 			((o.GetNamespace() != cluster.GetNamespace()) ||
-				(o.GetName() != cluster.Spec.Token.SecretRef)) {
+				!slices.Contains(refsFn(&cluster), o.GetName())) {
 			continue
 		}
 		requests = append(requests, reconcile.Request{
@@ -166,3 +251,35 @@ func (r *ClusterReconciler) enqueueRequestsForSecrets(
 
 	return requests
 }
+
+// clusterSecretRefs returns the names of all secrets referenced by the cluster.
+func clusterSecretRefs(cluster *slinkyv1alpha1.Cluster) []string {
+	refs := []string{}
+	if cluster.Spec.Token.SecretRef != "" {
+		refs = append(refs, cluster.Spec.Token.SecretRef)
+	}
+	if jwt := cluster.Spec.Token.JWT; jwt != nil && jwt.KeySecretRef != "" {
+		refs = append(refs, jwt.KeySecretRef)
+	}
+	if tls := cluster.Spec.TLS; tls != nil {
+		if tls.CABundle != nil && tls.CABundle.SecretRef != "" {
+			refs = append(refs, tls.CABundle.SecretRef)
+		}
+		if tls.ClientCertSecretRef != "" {
+			refs = append(refs, tls.ClientCertSecretRef)
+		}
+	}
+	return refs
+}
+
+// clusterConfigMapRefs returns the names of all configmaps referenced by the
+// cluster.
+func clusterConfigMapRefs(cluster *slinkyv1alpha1.Cluster) []string {
+	refs := []string{}
+	if tls := cluster.Spec.TLS; tls != nil {
+		if tls.CABundle != nil && tls.CABundle.ConfigMapRef != "" {
+			refs = append(refs, tls.CABundle.ConfigMapRef)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+// addFinalizer ensures the cluster carries the finalizer that holds back its
+// deletion until no NodeSets reference it.
+func (r *ClusterReconciler) addFinalizer(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+) error {
+	if controllerutil.ContainsFinalizer(cluster, slinkyv1alpha1.FinalizerCluster) {
+		return nil
+	}
+	patch := client.MergeFrom(cluster.DeepCopy())
+	controllerutil.AddFinalizer(cluster, slinkyv1alpha1.FinalizerCluster)
+	return r.Patch(ctx, cluster, patch)
+}
+
+// syncClusterDeletion handles a cluster marked for deletion. The slurm client
+// is kept until no NodeSets reference the cluster, so their pods are still
+// drained in Slurm before being deleted.
+func (r *ClusterReconciler) syncClusterDeletion(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+) error {
+	logger := log.FromContext(ctx)
+	clusterName := types.NamespacedName{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.GetName(),
+	}
+
+	if !controllerutil.ContainsFinalizer(cluster, slinkyv1alpha1.FinalizerCluster) {
+		r.slurmClientDelete(ctx, clusterName)
+		return nil
+	}
+
+	if err := r.slurmClientUpdate(ctx, cluster, status); err != nil {
+		return err
+	}
+
+	nodesets, err := r.clusterNodeSets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	pending := []string{}
+	for i := range nodesets {
+		nodeset := &nodesets[i]
+		if cluster.Spec.DeletionPolicy == slinkyv1alpha1.ClusterDeletionPolicyDrainNodeSets {
+			if err := r.scaleDownNodeSet(ctx, cluster, nodeset); err != nil {
+				return err
+			}
+			if isNodeSetScaledDown(nodeset) {
+				continue
+			}
+		}
+		pending = append(pending, klog.KObj(nodeset).String())
+	}
+
+	if len(pending) > 0 {
+		reason := reasonNodeSetsReferenced
+		message := fmt.Sprintf("Waiting for NodeSets to be deleted: %s", strings.Join(pending, ", "))
+		if cluster.Spec.DeletionPolicy == slinkyv1alpha1.ClusterDeletionPolicyDrainNodeSets {
+			reason = reasonNodeSetsDraining
+			message = fmt.Sprintf("Waiting for NodeSets to drain and scale down: %s", strings.Join(pending, ", "))
+		}
+		logger.Info("Cluster deletion is blocked", "reason", reason, "nodesets", pending)
+		setCondition(cluster, status, slinkyv1alpha1.ClusterDeletionBlocked, metav1.ConditionTrue,
+			reason, message)
+		return nil
+	}
+
+	patch := client.MergeFrom(cluster.DeepCopy())
+	controllerutil.RemoveFinalizer(cluster, slinkyv1alpha1.FinalizerCluster)
+	if err := r.Patch(ctx, cluster, patch); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.slurmClientDelete(ctx, clusterName)
+
+	return nil
+}
+
+// clusterNodeSets returns the NodeSets that reference the cluster.
+func (r *ClusterReconciler) clusterNodeSets(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+) ([]slinkyv1alpha1.NodeSet, error) {
+	nodesetList := &slinkyv1alpha1.NodeSetList{}
+	if err := r.List(ctx, nodesetList); err != nil {
+		return nil, err
+	}
+	clusterKey := client.ObjectKeyFromObject(cluster)
+	nodesets := make([]slinkyv1alpha1.NodeSet, 0, len(nodesetList.Items))
+	for _, nodeset := range nodesetList.Items {
+		if nodeset.ClusterKey() == clusterKey {
+			nodesets = append(nodesets, nodeset)
+		}
+	}
+	return nodesets, nil
+}
+
+// scaleDownNodeSet sets the NodeSet replicas to zero, so the NodeSet
+// controller drains and deletes its pods.
+func (r *ClusterReconciler) scaleDownNodeSet(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+	nodeset *slinkyv1alpha1.NodeSet,
+) error {
+	if nodeset.GetDeletionTimestamp() != nil || ptr.Deref(nodeset.Spec.Replicas, 1) == 0 {
+		return nil
+	}
+	patch := client.MergeFrom(nodeset.DeepCopy())
+	nodeset.Spec.Replicas = ptr.To[int32](0)
+	if err := r.Patch(ctx, nodeset, patch); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	log.FromContext(ctx).Info("Scaled down NodeSet for Cluster deletion", "nodeset", klog.KObj(nodeset))
+	r.eventRecorder.Eventf(cluster, corev1.EventTypeNormal, reasonNodeSetsDraining,
+		"Scaled down NodeSet %s for deletion", klog.KObj(nodeset))
+	return nil
+}
+
+// isNodeSetScaledDown returns true when the NodeSet was observed at zero
+// replicas and has no pods left.
+func isNodeSetScaledDown(nodeset *slinkyv1alpha1.NodeSet) bool {
+	return ptr.Deref(nodeset.Spec.Replicas, 1) == 0 &&
+		nodeset.Status.ObservedGeneration >= nodeset.GetGeneration() &&
+		nodeset.Status.Replicas == 0
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	slurmfake "github.com/SlinkyProject/slurm-client/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+)
+
+func newNodeSet(name, clusterName string, replicas int32) *slinkyv1alpha1.NodeSet {
+	return &slinkyv1alpha1.NodeSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+		Spec: slinkyv1alpha1.NodeSetSpec{
+			ClusterName: clusterName,
+			Replicas:    ptr.To(replicas),
+		},
+		Status: slinkyv1alpha1.NodeSetStatus{
+			Replicas: replicas,
+		},
+	}
+}
+
+func newNodeSetInNamespace(namespace, name, clusterName, clusterNamespace string) *slinkyv1alpha1.NodeSet {
+	nodeset := newNodeSet(name, clusterName, 2)
+	nodeset.Namespace = namespace
+	nodeset.Spec.ClusterNamespace = clusterNamespace
+	return nodeset
+}
+
+func newDeletingCluster(name string, policy slinkyv1alpha1.ClusterDeletionPolicy) *slinkyv1alpha1.Cluster {
+	cluster := newCluster(name)
+	cluster.Spec.DeletionPolicy = policy
+	cluster.DeletionTimestamp = ptr.To(metav1.Now())
+	cluster.Finalizers = []string{slinkyv1alpha1.FinalizerCluster}
+	return cluster
+}
+
+func TestClusterReconciler_addFinalizer(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	cluster := newCluster("foo")
+	c := fake.NewClientBuilder().WithObjects(cluster.DeepCopy()).Build()
+	r := newClusterController(c, resources.NewClusters())
+	if err := r.addFinalizer(context.TODO(), cluster); err != nil {
+		t.Fatalf("addFinalizer() error = %v", err)
+	}
+	got := &slinkyv1alpha1.Cluster{}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(cluster), got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(got, slinkyv1alpha1.FinalizerCluster) {
+		t.Errorf("Finalizers = %v, want %v", got.Finalizers, slinkyv1alpha1.FinalizerCluster)
+	}
+}
+
+func TestClusterReconciler_syncClusterDeletion(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "foo-token",
+		},
+		Data: map[string][]byte{
+			authTokenKey: []byte(slurmfake.FakeSecret),
+		},
+	}
+	tests := []struct {
+		name           string
+		cluster        *slinkyv1alpha1.Cluster
+		nodesets       []client.Object
+		wantDeleted    bool
+		wantReason     string
+		wantReplicas   map[string]int32
+		wantHasCluster bool
+	}{
+		{
+			name:        "No NodeSets",
+			cluster:     newDeletingCluster("foo", slinkyv1alpha1.ClusterDeletionPolicyBlock),
+			wantDeleted: true,
+		},
+		{
+			name:    "Other cluster NodeSets",
+			cluster: newDeletingCluster("foo", slinkyv1alpha1.ClusterDeletionPolicyBlock),
+			nodesets: []client.Object{
+				newNodeSet("bar", "bar", 2),
+			},
+			wantDeleted: true,
+		},
+		{
+			name:    "Same name in other namespace",
+			cluster: newDeletingCluster("foo", slinkyv1alpha1.ClusterDeletionPolicyBlock),
+			nodesets: []client.Object{
+				newNodeSetInNamespace("tenant", "compute", "foo", ""),
+			},
+			wantDeleted: true,
+		},
+		{
+			name:    "Block cross-namespace NodeSets",
+			cluster: newDeletingCluster("foo", slinkyv1alpha1.ClusterDeletionPolicyBlock),
+			nodesets: []client.Object{
+				newNodeSetInNamespace("tenant", "compute", "foo", corev1.NamespaceDefault),
+			},
+			wantReason:     reasonNodeSetsReferenced,
+			wantHasCluster: true,
+		},
+		{
+			name:    "Block",
+			cluster: newDeletingCluster("foo", slinkyv1alpha1.ClusterDeletionPolicyBlock),
+			nodesets: []client.Object{
+				newNodeSet("compute", "foo", 2),
+			},
+			wantReason:     reasonNodeSetsReferenced,
+			wantReplicas:   map[string]int32{"compute": 2},
+			wantHasCluster: true,
+		},
+		{
+			name:    "Drain scales down",
+			cluster: newDeletingCluster("foo", slinkyv1alpha1.ClusterDeletionPolicyDrainNodeSets),
+			nodesets: []client.Object{
+				newNodeSet("compute", "foo", 2),
+			},
+			wantReason:     reasonNodeSetsDraining,
+			wantReplicas:   map[string]int32{"compute": 0},
+			wantHasCluster: true,
+		},
+		{
+			name:    "Drain complete",
+			cluster: newDeletingCluster("foo", slinkyv1alpha1.ClusterDeletionPolicyDrainNodeSets),
+			nodesets: []client.Object{
+				newNodeSet("compute", "foo", 0),
+			},
+			wantDeleted:  true,
+			wantReplicas: map[string]int32{"compute": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append([]client.Object{tt.cluster.DeepCopy(), secret.DeepCopy()}, tt.nodesets...)
+			c := fake.NewClientBuilder().WithObjects(objects...).Build()
+			clusterName := types.NamespacedName{Namespace: tt.cluster.Namespace, Name: tt.cluster.Name}
+			endpoints, err := resources.NewEndpoints(clusterServers(tt.cluster), nil)
+			if err != nil {
+				t.Fatalf("NewEndpoints() error = %v", err)
+			}
+			slurmClusters := resources.NewClusters()
+			slurmClusters.AddWithEndpoints(clusterName, slurmfake.NewFakeClient(), endpoints, "")
+			defer slurmClusters.Remove(clusterName)
+			r := newClusterController(c, slurmClusters)
+
+			cluster := tt.cluster.DeepCopy()
+			status := cluster.Status.DeepCopy()
+			if err := r.syncClusterDeletion(context.TODO(), cluster, status); err != nil {
+				t.Fatalf("syncClusterDeletion() error = %v", err)
+			}
+
+			err = c.Get(context.TODO(), clusterName, &slinkyv1alpha1.Cluster{})
+			if got := apierrors.IsNotFound(err); got != tt.wantDeleted {
+				t.Errorf("Cluster deleted = %v, want %v", got, tt.wantDeleted)
+			}
+			if got := slurmClusters.Has(clusterName); got != tt.wantHasCluster {
+				t.Errorf("SlurmClusters.Has() = %v, want %v", got, tt.wantHasCluster)
+			}
+			if tt.wantReason != "" {
+				if got := conditionStatus(status, slinkyv1alpha1.ClusterDeletionBlocked); got != metav1.ConditionTrue {
+					t.Errorf("DeletionBlocked = %v, want %v", got, metav1.ConditionTrue)
+				}
+				if got := conditionReason(status, slinkyv1alpha1.ClusterDeletionBlocked); got != tt.wantReason {
+					t.Errorf("DeletionBlocked reason = %v, want %v", got, tt.wantReason)
+				}
+			}
+			for name, want := range tt.wantReplicas {
+				nodeset := &slinkyv1alpha1.NodeSet{}
+				key := types.NamespacedName{Namespace: tt.cluster.Namespace, Name: name}
+				if err := c.Get(context.TODO(), key, nodeset); err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				if got := ptr.Deref(nodeset.Spec.Replicas, 1); got != want {
+					t.Errorf("NodeSet %s replicas = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_isNodeSetScaledDown(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodeset *slinkyv1alpha1.NodeSet
+		want    bool
+	}{
+		{
+			name:    "Replicas",
+			nodeset: newNodeSet("foo", "foo", 1),
+			want:    false,
+		},
+		{
+			name: "Pods remaining",
+			nodeset: func() *slinkyv1alpha1.NodeSet {
+				nodeset := newNodeSet("foo", "foo", 0)
+				nodeset.Status.Replicas = 1
+				return nodeset
+			}(),
+			want: false,
+		},
+		{
+			name: "Not observed",
+			nodeset: func() *slinkyv1alpha1.NodeSet {
+				nodeset := newNodeSet("foo", "foo", 0)
+				nodeset.Generation = 2
+				nodeset.Status.ObservedGeneration = 1
+				return nodeset
+			}(),
+			want: false,
+		},
+		{
+			name:    "Scaled down",
+			nodeset: newNodeSet("foo", "foo", 0),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNodeSetScaledDown(tt.nodeset); got != tt.want {
+				t.Errorf("isNodeSetScaledDown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
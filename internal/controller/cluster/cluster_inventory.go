@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/cluster/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/podinfo"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+// syncInventory reports what slurmctld knows about the cluster. The last
+// known inventory is kept while the cluster is not ready.
+func (r *ClusterReconciler) syncInventory(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+) {
+	logger := log.FromContext(ctx)
+
+	if !status.IsReady {
+		return
+	}
+
+	inventory, err := r.slurmControl.GetInventory(ctx, cluster)
+	if err != nil {
+		logger.Error(err, "unable to get cluster inventory", "cluster", klog.KObj(cluster))
+		return
+	}
+	calculateInventoryStatus(status, inventory)
+}
+
+// calculateInventoryStatus reports the Slurm cluster information, node totals,
+// and partitions from the inventory.
+func calculateInventoryStatus(
+	status *slinkyv1alpha1.ClusterStatus,
+	inventory *slurmcontrol.Inventory,
+) {
+	if inventory == nil {
+		return
+	}
+
+	if inventory.Info != nil {
+		status.SlurmClusterName = inventory.Info.ClusterName
+		status.SlurmVersion = inventory.Info.SlurmVersion
+	}
+
+	partitions := make(map[string]*slinkyv1alpha1.ClusterPartitionStatus, len(inventory.Partitions))
+	for _, partition := range inventory.Partitions {
+		partitions[partition.Name] = &slinkyv1alpha1.ClusterPartitionStatus{
+			Name:  partition.Name,
+			State: strings.Join(partition.State, "+"),
+			Nodes: partition.TotalNodes,
+		}
+	}
+
+	status.Nodes = 0
+	status.NodeSetNodes = 0
+	for _, node := range inventory.Nodes {
+		status.Nodes++
+		if isNodeSetNode(node) {
+			status.NodeSetNodes++
+		}
+		for _, name := range node.Partitions {
+			partition, ok := partitions[name]
+			if !ok {
+				continue
+			}
+			countNodeState(partition, node)
+		}
+	}
+
+	status.Partitions = make([]slinkyv1alpha1.ClusterPartitionStatus, 0, len(partitions))
+	for _, partition := range partitions {
+		status.Partitions = append(status.Partitions, *partition)
+	}
+	slices.SortFunc(status.Partitions, func(a, b slinkyv1alpha1.ClusterPartitionStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// isNodeSetNode returns true when the Slurm node carries the pod info of a
+// NodeSet pod. NodeSets may live in other namespaces than the cluster.
+func isNodeSetNode(node slurmapi.Node) bool {
+	podInfo := podinfo.PodInfo{}
+	if err := podinfo.ParseIntoPodInfo(ptr.To(node.Comment), &podInfo); err != nil {
+		return false
+	}
+	return podInfo.PodName != "" && podInfo.Namespace != ""
+}
+
+// countNodeState adds the node to the state breakdown of the partition.
+func countNodeState(partition *slinkyv1alpha1.ClusterPartitionStatus, node slurmapi.Node) {
+	switch {
+	case node.State.Has(slurmapi.NodeStateAllocated):
+		partition.Allocated++
+	case node.State.Has(slurmapi.NodeStateMixed):
+		partition.Mixed++
+	case node.State.Has(slurmapi.NodeStateIdle):
+		partition.Idle++
+	case node.State.Has(slurmapi.NodeStateDown):
+		partition.Down++
+	}
+	if node.State.Has(slurmapi.NodeStateDrain) {
+		partition.Drain++
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/set"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/cluster/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+func Test_calculateInventoryStatus(t *testing.T) {
+	podNodeComment := `{"namespace":"default","podName":"compute-0"}`
+	tests := []struct {
+		name      string
+		status    *slinkyv1alpha1.ClusterStatus
+		inventory *slurmcontrol.Inventory
+		want      *slinkyv1alpha1.ClusterStatus
+	}{
+		{
+			name: "No inventory keeps status",
+			status: &slinkyv1alpha1.ClusterStatus{
+				SlurmVersion: "24.11.1",
+				Nodes:        2,
+			},
+			inventory: nil,
+			want: &slinkyv1alpha1.ClusterStatus{
+				SlurmVersion: "24.11.1",
+				Nodes:        2,
+			},
+		},
+		{
+			name:   "Inventory",
+			status: &slinkyv1alpha1.ClusterStatus{},
+			inventory: &slurmcontrol.Inventory{
+				Info: &slurmapi.ClusterInfo{
+					ClusterName:  "slurm",
+					SlurmVersion: "24.11.1",
+				},
+				Nodes: []slurmapi.Node{
+					{
+						Name:       "compute-0",
+						State:      set.New(slurmapi.NodeStateIdle),
+						Comment:    podNodeComment,
+						Partitions: []string{"all", "debug"},
+					},
+					{
+						Name:       "compute-1",
+						State:      set.New(slurmapi.NodeStateMixed, slurmapi.NodeStateDrain),
+						Comment:    `{"namespace":"other","podName":"compute-1"}`,
+						Partitions: []string{"all"},
+					},
+					{
+						Name:       "static-0",
+						State:      set.New(slurmapi.NodeStateDown),
+						Partitions: []string{"all", "unknown"},
+					},
+				},
+				Partitions: []slurmapi.Partition{
+					{
+						Name:       "debug",
+						State:      []string{"UP"},
+						TotalNodes: 1,
+					},
+					{
+						Name:       "all",
+						State:      []string{"UP"},
+						TotalNodes: 3,
+					},
+				},
+			},
+			want: &slinkyv1alpha1.ClusterStatus{
+				SlurmClusterName: "slurm",
+				SlurmVersion:     "24.11.1",
+				Nodes:            3,
+				NodeSetNodes:     2,
+				Partitions: []slinkyv1alpha1.ClusterPartitionStatus{
+					{
+						Name:  "all",
+						State: "UP",
+						Nodes: 3,
+						Mixed: 1,
+						Idle:  1,
+						Down:  1,
+						Drain: 1,
+					},
+					{
+						Name:  "debug",
+						State: "UP",
+						Nodes: 1,
+						Idle:  1,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calculateInventoryStatus(tt.status, tt.inventory)
+			if !apiequality.Semantic.DeepEqual(tt.status, tt.want) {
+				t.Errorf("calculateInventoryStatus() = %+v, want %+v", tt.status, tt.want)
+			}
+		})
+	}
+}
@@ -5,21 +5,25 @@ package cluster
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
 
-	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
-	"github.com/SlinkyProject/slurm-client/pkg/object"
-	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	nodesetcontroller "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
 	"github.com/SlinkyProject/slurm-operator/internal/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
 )
 
 // Sync implements control logic for synchronizing a Cluster.
@@ -40,34 +44,35 @@ func (r *ClusterReconciler) Sync(ctx context.Context, req reconcile.Request) err
 	// Make a copy now to avoid mutation errors.
 	cluster = cluster.DeepCopy()
 
-	if err := r.syncCluster(ctx, cluster); err != nil {
+	status := cluster.Status.DeepCopy()
+
+	if err := r.syncCluster(ctx, cluster, status); err != nil {
 		errors := []error{err}
-		if err := r.syncClusterStatus(ctx, cluster); err != nil {
+		if err := r.syncClusterStatus(ctx, cluster, status); err != nil {
 			errors = append(errors, err)
 		}
 		return utilerrors.NewAggregate(errors)
 	}
 
-	return r.syncClusterStatus(ctx, cluster)
+	return r.syncClusterStatus(ctx, cluster, status)
 }
 
 // syncCluster performs the main syncing logic.
 func (r *ClusterReconciler) syncCluster(
 	ctx context.Context,
 	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
 ) error {
-	clusterName := types.NamespacedName{
-		Namespace: cluster.GetNamespace(),
-		Name:      cluster.GetName(),
-	}
-
 	// Handle resources marked for deletion
 	if cluster.GetDeletionTimestamp() != nil {
-		r.slurmClientDelete(ctx, clusterName)
-		return nil
+		return r.syncClusterDeletion(ctx, cluster, status)
 	}
 
-	if err := r.slurmClientUpdate(ctx, cluster); err != nil {
+	if err := r.addFinalizer(ctx, cluster); err != nil {
+		return err
+	}
+
+	if err := r.slurmClientUpdate(ctx, cluster, status); err != nil {
 		return err
 	}
 
@@ -91,6 +96,7 @@ func (r *ClusterReconciler) slurmClientDelete(
 func (r *ClusterReconciler) slurmClientUpdate(
 	ctx context.Context,
 	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
 ) error {
 	logger := log.FromContext(ctx)
 	clusterName := types.NamespacedName{
@@ -98,57 +104,149 @@ func (r *ClusterReconciler) slurmClientUpdate(
 		Name:      cluster.GetName(),
 	}
 
-	// Lookup Secret from Cluster reference
-	secret := &corev1.Secret{}
-	secretName := cluster.Spec.Token.SecretRef
-	secretNamespacedName := types.NamespacedName{
-		Namespace: cluster.Namespace,
-		Name:      secretName,
+	// Resolve the auth token, reusing the current one when possible
+	currentToken := ""
+	if endpoints := r.SlurmClusters.GetEndpoints(clusterName); endpoints != nil {
+		currentToken = endpoints.Token()
 	}
-	if err := r.Get(ctx, secretNamespacedName, secret); err != nil {
-		if apierrors.IsNotFound(err) {
-			logger.Info("Secret not found, retry later", "secretName", secretName)
-			durationStore.Push(utils.KeyFunc(cluster), requeueSecretTime)
-			r.slurmClientDelete(ctx, clusterName)
-			return nil
-		}
-		logger.Info("Failed to get secret", "secretName", secretName, "error", err)
+	authToken, err := r.clusterAuthToken(ctx, cluster, status, currentToken)
+	if err != nil {
 		return err
 	}
-	authToken := string(secret.Data["auth-token"])
+	if authToken == "" {
+		r.slurmClientDelete(ctx, clusterName)
+		return nil
+	}
+
+	// Build TLS configuration from referenced secrets and configmaps
+	tlsConfig, tlsHash, err := r.clusterTLSConfig(ctx, cluster)
+	if err != nil {
+		logger.Info("Failed to build TLS configuration", "error", err)
+		setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionFalse,
+			reasonTLSError, err.Error())
+		r.slurmClientDelete(ctx, clusterName)
+		var apiStatus apierrors.APIStatus
+		if errors.As(err, &apiStatus) && !apierrors.IsNotFound(err) {
+			return err
+		}
+		durationStore.Push(utils.KeyFunc(cluster), requeueSecretTime)
+		return nil
+	}
 
 	// Lookup slurm client
-	server := cluster.Spec.Server
+	servers := clusterServers(cluster)
 	slurmClientOld := r.SlurmClusters.Get(clusterName)
+	endpointsOld := r.SlurmClusters.GetEndpoints(clusterName)
 
-	// Determine if client is unchanged
+	versionOld := r.SlurmClusters.GetVersion(clusterName)
+
+	// Determine if client is unchanged, the token is swapped in place so
+	// informers keep running
 	if (slurmClientOld != nil) &&
-		(slurmClientOld.GetServer() == server) &&
-		(slurmClientOld.GetToken() == authToken) {
+		(endpointsOld != nil && endpointsOld.Equal(servers) && endpointsOld.ConfigHash == tlsHash) &&
+		(cluster.Spec.RestAPIVersion == "" || cluster.Spec.RestAPIVersion == versionOld) {
+		if endpointsOld.Token() != authToken {
+			endpointsOld.SetToken(authToken)
+			logger.Info("Rotated slurm cluster auth token", "clusterName", clusterName.String())
+		}
+		setClientConfigured(cluster, status, servers)
 		return nil
 	}
 
 	// Create slurm client
+	var transport http.RoundTripper
+	if tlsConfig != nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		transport = t
+	}
+	endpoints, err := resources.NewEndpoints(servers, transport)
+	if err != nil {
+		logger.Error(err, "Invalid slurmrestd servers")
+		setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionFalse,
+			reasonInvalidServer, err.Error())
+		r.slurmClientDelete(ctx, clusterName)
+		return nil
+	}
+	endpoints.ConfigHash = tlsHash
+	endpoints.SetToken(authToken)
+
+	// Select the Slurm REST API version, negotiating it when not pinned
+	version := cluster.Spec.RestAPIVersion
+	if version == "" {
+		httpClient := &http.Client{
+			Transport: endpoints,
+			Timeout:   negotiateTimeout,
+		}
+		version, err = slurmapi.Negotiate(ctx, httpClient, servers[0])
+		if err != nil {
+			logger.Info("Failed to negotiate Slurm REST API version", "error", err)
+			setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionFalse,
+				reasonVersionNegotiationFailed, fmt.Sprintf("Failed to negotiate Slurm REST API version: %v", err))
+			r.slurmClientDelete(ctx, clusterName)
+			durationStore.Push(utils.KeyFunc(cluster), requeueSecretTime)
+			return nil
+		}
+	}
+
 	config := &slurmclient.Config{
-		Server:    server,
+		Server:    servers[0],
 		AuthToken: authToken,
+		HTTPClient: &http.Client{
+			Transport: endpoints,
+		},
 	}
 	options := &slurmclient.ClientOptions{
-		DisableFor: []object.Object{
-			&slurmtypes.V0041ControllerPing{},
-		},
+		DisableFor: slurmapi.UncachedObjects(),
 	}
 	slurmClient, err := slurmclient.NewClient(config, options)
 	if err != nil {
 		logger.Error(err, "Failed to create slurm client")
+		setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionFalse,
+			reasonClientError, fmt.Sprintf("Failed to create client for servers %v: %v", servers, err))
+		r.slurmClientDelete(ctx, clusterName)
+		return nil
+	}
+	slurmAPI, err := slurmapi.New(slurmClient, version)
+	if err != nil {
+		logger.Error(err, "Failed to create slurm client")
+		setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionFalse,
+			reasonClientError, err.Error())
+		r.slurmClientDelete(ctx, clusterName)
 		return nil
 	}
-	nodesetcontroller.SetEventHandler(slurmClient, r.EventCh)
+	nodesetcontroller.SetEventHandler(slurmAPI, r.EventCh)
 
 	// Add slurm client
-	if r.SlurmClusters.Add(clusterName, slurmClient) {
-		logger.Info("Added slurm cluster client", "clusterName", clusterName.String())
+	if r.SlurmClusters.AddWithEndpoints(clusterName, slurmClient, endpoints, version) {
+		logger.Info("Added slurm cluster client", "clusterName", clusterName.String(), "servers", servers,
+			"restApiVersion", version)
 	}
+	setClientConfigured(cluster, status, servers)
 
 	return nil
 }
+
+func setClientConfigured(
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+	servers []string,
+) {
+	setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionTrue,
+		reasonClientCreated, fmt.Sprintf("Client configured for servers %v", servers))
+}
+
+// clusterServers returns the slurmrestd servers of the cluster, in order of
+// preference, without duplicates.
+func clusterServers(cluster *slinkyv1alpha1.Cluster) []string {
+	servers := make([]string, 0, len(cluster.Spec.Servers)+1)
+	if cluster.Spec.Server != "" {
+		servers = append(servers, cluster.Spec.Server)
+	}
+	for _, server := range cluster.Spec.Servers {
+		if server != "" && !slices.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
@@ -6,14 +6,21 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"time"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/cluster/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
 	"github.com/SlinkyProject/slurm-operator/internal/utils"
 )
 
@@ -21,29 +28,115 @@ import (
 func (r *ClusterReconciler) syncClusterStatus(
 	ctx context.Context,
 	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
 ) error {
 	logger := log.FromContext(ctx)
-	status := cluster.Status.DeepCopy()
 
-	isReady := false
-	if ok, err := r.slurmControl.PingController(ctx, cluster); err != nil {
+	result, err := r.slurmControl.PingController(ctx, cluster)
+	if err != nil {
 		logger.Error(err, "unable to ping cluster", "cluster", klog.KObj(cluster))
-	} else if ok {
-		isReady = ok
 	}
-	status.IsReady = isReady
+	calculatePingStatus(cluster, status, result, err)
+	r.syncTokenExpiry(cluster, status, time.Now())
+	clusterName := types.NamespacedName{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.GetName(),
+	}
+	calculateServerStatus(status, r.SlurmClusters.GetEndpoints(clusterName))
+	status.RestAPIVersion = r.SlurmClusters.GetVersion(clusterName)
+	r.syncInventory(ctx, cluster, status)
 
 	if err := r.updateStatus(ctx, cluster, status); err != nil {
 		return fmt.Errorf("error updating Cluster(%s) status: %v", klog.KObj(cluster), err)
 	}
 
-	if !status.IsReady {
-		durationStore.Push(utils.KeyFunc(cluster), requeueReadyTime)
-	}
+	// Periodically ping the cluster so status reflects the current state.
+	durationStore.Push(utils.KeyFunc(cluster), requeueReadyTime)
 
 	return nil
 }
 
+// calculatePingStatus sets the reachability and authentication conditions
+// from the result of a controller ping.
+func calculatePingStatus(
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+	result *slurmcontrol.PingResult,
+	err error,
+) {
+	switch {
+	case result == nil || !result.HasClient:
+		setCondition(cluster, status, slinkyv1alpha1.ClusterControllerReachable, metav1.ConditionFalse,
+			reasonClientNotConfigured, "No Slurm client is configured")
+		setCondition(cluster, status, slinkyv1alpha1.ClusterAuthenticated, metav1.ConditionUnknown,
+			reasonClientNotConfigured, "No Slurm client is configured")
+	case slurmcontrol.IsAuthError(err):
+		setCondition(cluster, status, slinkyv1alpha1.ClusterControllerReachable, metav1.ConditionUnknown,
+			reasonAuthenticationFailed, "Cannot ping the controller without a valid auth token")
+		setCondition(cluster, status, slinkyv1alpha1.ClusterAuthenticated, metav1.ConditionFalse,
+			reasonTokenRejected, fmt.Sprintf("slurmrestd rejected the auth token: %v", err))
+	case err != nil:
+		setCondition(cluster, status, slinkyv1alpha1.ClusterControllerReachable, metav1.ConditionFalse,
+			reasonRequestFailed, fmt.Sprintf("Ping request failed: %v", err))
+		setCondition(cluster, status, slinkyv1alpha1.ClusterAuthenticated, metav1.ConditionUnknown,
+			reasonRequestFailed, "Cannot verify the auth token while slurmrestd is unreachable")
+	case !result.IsUp:
+		setCondition(cluster, status, slinkyv1alpha1.ClusterControllerReachable, metav1.ConditionFalse,
+			reasonControllerDown, "No slurmctld responded to the ping")
+		setCondition(cluster, status, slinkyv1alpha1.ClusterAuthenticated, metav1.ConditionTrue,
+			reasonTokenAccepted, "slurmrestd accepted the auth token")
+	default:
+		message := fmt.Sprintf("slurmctld %q responded to the ping", result.Hostname)
+		// The ping time and latency are only recorded when the condition
+		// changes, so an unchanged cluster is not written every ping.
+		reachable := apimeta.FindStatusCondition(status.Conditions, slinkyv1alpha1.ClusterControllerReachable)
+		if status.LastPingTime == nil || reachable == nil ||
+			reachable.Status != metav1.ConditionTrue || reachable.Message != message {
+			status.LastPingTime = ptr.To(metav1.Now())
+			status.PingLatency = &metav1.Duration{Duration: result.Latency}
+		}
+		setCondition(cluster, status, slinkyv1alpha1.ClusterControllerReachable, metav1.ConditionTrue,
+			reasonControllerUp, message)
+		setCondition(cluster, status, slinkyv1alpha1.ClusterAuthenticated, metav1.ConditionTrue,
+			reasonTokenAccepted, "slurmrestd accepted the auth token")
+	}
+
+	status.IsReady = apimeta.IsStatusConditionTrue(status.Conditions, slinkyv1alpha1.ClusterControllerReachable) &&
+		apimeta.IsStatusConditionTrue(status.Conditions, slinkyv1alpha1.ClusterAuthenticated)
+}
+
+// calculateServerStatus reports the active and healthy slurmrestd servers.
+func calculateServerStatus(
+	status *slinkyv1alpha1.ClusterStatus,
+	endpoints *resources.Endpoints,
+) {
+	if endpoints == nil {
+		status.Server = ""
+		status.HealthyServers = nil
+		return
+	}
+	status.Server = endpoints.Active()
+	status.HealthyServers = endpoints.Healthy()
+}
+
+// setCondition updates a condition on the status, only bumping the transition
+// time when the condition status changes.
+func setCondition(
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+	conditionType string,
+	conditionStatus metav1.ConditionStatus,
+	reason, message string,
+) {
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: cluster.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
 func (r *ClusterReconciler) updateStatus(
 	ctx context.Context,
 	cluster *slinkyv1alpha1.Cluster,
@@ -67,7 +160,7 @@ func inconsistentStatus(
 	cluster *slinkyv1alpha1.Cluster,
 	status *slinkyv1alpha1.ClusterStatus,
 ) bool {
-	return status.IsReady != cluster.Status.IsReady
+	return !apiequality.Semantic.DeepEqual(cluster.Status, *status)
 }
 
 func (r *ClusterReconciler) updateClusterStatus(
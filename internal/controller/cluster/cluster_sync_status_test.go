@@ -0,0 +1,388 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slurmfake "github.com/SlinkyProject/slurm-client/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/cluster/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+func newCluster(name string) *slinkyv1alpha1.Cluster {
+	return &slinkyv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+		Spec: slinkyv1alpha1.ClusterSpec{
+			Server: "http://slurmrestd:6820",
+			Token: slinkyv1alpha1.ClusterToken{
+				SecretRef: name + "-token",
+			},
+		},
+	}
+}
+
+func newClusterController(client client.Client, slurmClusters *resources.Clusters) *ClusterReconciler {
+	r := &ClusterReconciler{
+		Client:        client,
+		Scheme:        client.Scheme(),
+		SlurmClusters: slurmClusters,
+		eventRecorder: record.NewFakeRecorder(10),
+	}
+	r.slurmControl = slurmcontrol.NewSlurmControl(slurmClusters)
+	return r
+}
+
+func conditionStatus(status *slinkyv1alpha1.ClusterStatus, conditionType string) metav1.ConditionStatus {
+	condition := apimeta.FindStatusCondition(status.Conditions, conditionType)
+	if condition == nil {
+		return ""
+	}
+	return condition.Status
+}
+
+func conditionReason(status *slinkyv1alpha1.ClusterStatus, conditionType string) string {
+	condition := apimeta.FindStatusCondition(status.Conditions, conditionType)
+	if condition == nil {
+		return ""
+	}
+	return condition.Reason
+}
+
+func Test_calculatePingStatus(t *testing.T) {
+	type args struct {
+		result *slurmcontrol.PingResult
+		err    error
+	}
+	lastPingTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	reachable := metav1.Condition{
+		Type:    slinkyv1alpha1.ClusterControllerReachable,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonControllerUp,
+		Message: `slurmctld "slurmctld-0" responded to the ping`,
+	}
+	tests := []struct {
+		name          string
+		status        *slinkyv1alpha1.ClusterStatus
+		args          args
+		wantReady     bool
+		wantReachable metav1.ConditionStatus
+		wantAuth      metav1.ConditionStatus
+		wantPingTime  bool
+	}{
+		{
+			name: "No client",
+			args: args{
+				result: &slurmcontrol.PingResult{},
+			},
+			wantReady:     false,
+			wantReachable: metav1.ConditionFalse,
+			wantAuth:      metav1.ConditionUnknown,
+		},
+		{
+			name: "Unauthorized",
+			args: args{
+				result: &slurmcontrol.PingResult{HasClient: true},
+				err:    errors.New(http.StatusText(http.StatusUnauthorized)),
+			},
+			wantReady:     false,
+			wantReachable: metav1.ConditionUnknown,
+			wantAuth:      metav1.ConditionFalse,
+		},
+		{
+			name: "Request failed",
+			args: args{
+				result: &slurmcontrol.PingResult{HasClient: true},
+				err:    errors.New(http.StatusText(http.StatusBadGateway)),
+			},
+			wantReady:     false,
+			wantReachable: metav1.ConditionFalse,
+			wantAuth:      metav1.ConditionUnknown,
+		},
+		{
+			name: "Controller down",
+			args: args{
+				result: &slurmcontrol.PingResult{HasClient: true},
+			},
+			wantReady:     false,
+			wantReachable: metav1.ConditionFalse,
+			wantAuth:      metav1.ConditionTrue,
+		},
+		{
+			name: "Controller up",
+			args: args{
+				result: &slurmcontrol.PingResult{
+					HasClient: true,
+					IsUp:      true,
+					Hostname:  "slurmctld-0",
+					Latency:   5 * time.Millisecond,
+				},
+			},
+			wantReady:     true,
+			wantReachable: metav1.ConditionTrue,
+			wantAuth:      metav1.ConditionTrue,
+			wantPingTime:  true,
+		},
+		{
+			name: "Controller still up",
+			status: &slinkyv1alpha1.ClusterStatus{
+				Conditions:   []metav1.Condition{reachable},
+				LastPingTime: &lastPingTime,
+				PingLatency:  &metav1.Duration{Duration: time.Millisecond},
+			},
+			args: args{
+				result: &slurmcontrol.PingResult{
+					HasClient: true,
+					IsUp:      true,
+					Hostname:  "slurmctld-0",
+					Latency:   5 * time.Millisecond,
+				},
+			},
+			wantReady:     true,
+			wantReachable: metav1.ConditionTrue,
+			wantAuth:      metav1.ConditionTrue,
+			wantPingTime:  true,
+		},
+		{
+			name: "Controller failed over",
+			status: &slinkyv1alpha1.ClusterStatus{
+				Conditions:   []metav1.Condition{reachable},
+				LastPingTime: &lastPingTime,
+				PingLatency:  &metav1.Duration{Duration: time.Millisecond},
+			},
+			args: args{
+				result: &slurmcontrol.PingResult{
+					HasClient: true,
+					IsUp:      true,
+					Hostname:  "slurmctld-1",
+					Latency:   5 * time.Millisecond,
+				},
+			},
+			wantReady:     true,
+			wantReachable: metav1.ConditionTrue,
+			wantAuth:      metav1.ConditionTrue,
+			wantPingTime:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newCluster("foo")
+			if tt.status != nil {
+				cluster.Status = *tt.status
+			}
+			status := cluster.Status.DeepCopy()
+			calculatePingStatus(cluster, status, tt.args.result, tt.args.err)
+			if status.IsReady != tt.wantReady {
+				t.Errorf("IsReady = %v, want %v", status.IsReady, tt.wantReady)
+			}
+			if got := conditionStatus(status, slinkyv1alpha1.ClusterControllerReachable); got != tt.wantReachable {
+				t.Errorf("ControllerReachable = %v, want %v", got, tt.wantReachable)
+			}
+			if got := conditionStatus(status, slinkyv1alpha1.ClusterAuthenticated); got != tt.wantAuth {
+				t.Errorf("Authenticated = %v, want %v", got, tt.wantAuth)
+			}
+			if (status.LastPingTime != nil) != tt.wantPingTime {
+				t.Errorf("LastPingTime = %v, want set = %v", status.LastPingTime, tt.wantPingTime)
+			}
+			// The ping time and latency only change with the condition.
+			unchanged := tt.status != nil &&
+				apimeta.FindStatusCondition(status.Conditions, slinkyv1alpha1.ClusterControllerReachable).Message == reachable.Message
+			if unchanged && !status.LastPingTime.Equal(&lastPingTime) {
+				t.Errorf("LastPingTime = %v, want unchanged %v", status.LastPingTime, lastPingTime)
+			}
+			wantLatency := tt.args.result.Latency
+			if unchanged {
+				wantLatency = tt.status.PingLatency.Duration
+			}
+			if tt.wantPingTime && status.PingLatency.Duration != wantLatency {
+				t.Errorf("PingLatency = %v, want %v", status.PingLatency.Duration, wantLatency)
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_slurmClientUpdate(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	cluster := newCluster("foo")
+	clusterName := types.NamespacedName{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+	tests := []struct {
+		name           string
+		objects        []client.Object
+		wantSecret     metav1.ConditionStatus
+		wantClient     metav1.ConditionStatus
+		wantHasCluster bool
+	}{
+		{
+			name:           "Secret not found",
+			objects:        []client.Object{cluster.DeepCopy()},
+			wantSecret:     metav1.ConditionFalse,
+			wantClient:     metav1.ConditionFalse,
+			wantHasCluster: false,
+		},
+		{
+			name: "Token missing",
+			objects: []client.Object{
+				cluster.DeepCopy(),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cluster.Namespace,
+						Name:      cluster.Spec.Token.SecretRef,
+					},
+				},
+			},
+			wantSecret:     metav1.ConditionFalse,
+			wantClient:     metav1.ConditionFalse,
+			wantHasCluster: false,
+		},
+		{
+			name: "Client unchanged",
+			objects: []client.Object{
+				cluster.DeepCopy(),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cluster.Namespace,
+						Name:      cluster.Spec.Token.SecretRef,
+					},
+					Data: map[string][]byte{
+						authTokenKey: []byte(slurmfake.FakeSecret),
+					},
+				},
+			},
+			wantSecret:     metav1.ConditionTrue,
+			wantClient:     metav1.ConditionTrue,
+			wantHasCluster: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			endpoints, err := resources.NewEndpoints(clusterServers(cluster), nil)
+			if err != nil {
+				t.Fatalf("NewEndpoints() error = %v", err)
+			}
+			slurmClusters := resources.NewClusters()
+			slurmClusters.AddWithEndpoints(clusterName, slurmfake.NewFakeClient(), endpoints, "")
+			defer slurmClusters.Remove(clusterName)
+			r := newClusterController(c, slurmClusters)
+			status := cluster.Status.DeepCopy()
+			if err := r.slurmClientUpdate(context.TODO(), cluster, status); err != nil {
+				t.Errorf("slurmClientUpdate() error = %v", err)
+			}
+			if got := conditionStatus(status, slinkyv1alpha1.ClusterSecretResolved); got != tt.wantSecret {
+				t.Errorf("SecretResolved = %v, want %v", got, tt.wantSecret)
+			}
+			if got := conditionStatus(status, slinkyv1alpha1.ClusterClientConfigured); got != tt.wantClient {
+				t.Errorf("ClientConfigured = %v, want %v", got, tt.wantClient)
+			}
+			if got := slurmClusters.Has(clusterName); got != tt.wantHasCluster {
+				t.Errorf("SlurmClusters.Has() = %v, want %v", got, tt.wantHasCluster)
+			}
+		})
+	}
+}
+
+func Test_clusterServers(t *testing.T) {
+	tests := []struct {
+		name string
+		spec slinkyv1alpha1.ClusterSpec
+		want []string
+	}{
+		{
+			name: "Server",
+			spec: slinkyv1alpha1.ClusterSpec{
+				Server: "http://slurmrestd-0:6820",
+			},
+			want: []string{"http://slurmrestd-0:6820"},
+		},
+		{
+			name: "Servers",
+			spec: slinkyv1alpha1.ClusterSpec{
+				Servers: []string{"http://slurmrestd-0:6820", "http://slurmrestd-1:6820"},
+			},
+			want: []string{"http://slurmrestd-0:6820", "http://slurmrestd-1:6820"},
+		},
+		{
+			name: "Server first without duplicates",
+			spec: slinkyv1alpha1.ClusterSpec{
+				Server:  "http://slurmrestd-1:6820",
+				Servers: []string{"http://slurmrestd-0:6820", "http://slurmrestd-1:6820", ""},
+			},
+			want: []string{"http://slurmrestd-1:6820", "http://slurmrestd-0:6820"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &slinkyv1alpha1.Cluster{Spec: tt.spec}
+			if got := clusterServers(cluster); !slices.Equal(got, tt.want) {
+				t.Errorf("clusterServers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_slurmClientUpdate_restAPIVersion(t *testing.T) {
+	cluster := newCluster("foo")
+	cluster.Spec.RestAPIVersion = slurmapi.V0040
+	clusterName := types.NamespacedName{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Spec.Token.SecretRef,
+		},
+		Data: map[string][]byte{
+			authTokenKey: []byte(slurmfake.FakeSecret),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(cluster, secret).Build()
+	endpoints, err := resources.NewEndpoints(clusterServers(cluster), nil)
+	if err != nil {
+		t.Fatalf("NewEndpoints() error = %v", err)
+	}
+	slurmClient := slurmfake.NewFakeClient()
+	slurmClusters := resources.NewClusters()
+	slurmClusters.AddWithEndpoints(clusterName, slurmClient, endpoints, slurmapi.V0041)
+	defer slurmClusters.Remove(clusterName)
+
+	r := newClusterController(c, slurmClusters)
+	status := cluster.Status.DeepCopy()
+	if err := r.slurmClientUpdate(context.TODO(), cluster, status); err != nil {
+		t.Fatalf("slurmClientUpdate() error = %v", err)
+	}
+	if got := slurmClusters.Get(clusterName); got == slurmClient {
+		t.Errorf("slurm client was kept, want it replaced for the pinned version")
+	}
+	if got := slurmClusters.GetVersion(clusterName); got != slurmapi.V0040 {
+		t.Errorf("GetVersion() = %v, want %v", got, slurmapi.V0040)
+	}
+	if got := conditionStatus(status, slinkyv1alpha1.ClusterClientConfigured); got != metav1.ConditionTrue {
+		t.Errorf("ClientConfigured = %v, want %v", got, metav1.ConditionTrue)
+	}
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+// clusterTLSConfig builds the TLS configuration for the connection to
+// slurmrestd from the secrets and configmaps referenced by the cluster. It also
+// returns a hash of everything the configuration was built from, so the client
+// can be rebuilt when referenced material rotates. A nil configuration means
+// the cluster does not configure TLS.
+func (r *ClusterReconciler) clusterTLSConfig(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+) (*tls.Config, string, error) {
+	spec := cluster.Spec.TLS
+	if spec == nil {
+		return nil, "", nil
+	}
+
+	h := sha256.New()
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: spec.ServerName,
+	}
+	writeHash(h, "serverName", []byte(spec.ServerName))
+
+	if ca := spec.CABundle; ca != nil {
+		data, err := r.getCABundle(ctx, cluster.Namespace, ca)
+		if err != nil {
+			return nil, "", err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, "", fmt.Errorf("CA bundle contains no PEM encoded certificates")
+		}
+		config.RootCAs = pool
+		writeHash(h, "caBundle", data)
+	}
+
+	if name := spec.ClientCertSecretRef; name != "" {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: cluster.Namespace, Name: name}
+		if err := r.Get(ctx, key, secret); err != nil {
+			return nil, "", fmt.Errorf("failed to get client certificate Secret %q: %w", name, err)
+		}
+		certPEM := secret.Data[corev1.TLSCertKey]
+		keyPEM := secret.Data[corev1.TLSPrivateKeyKey]
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid client certificate in Secret %q: %w", name, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+		writeHash(h, "clientCert", certPEM)
+		writeHash(h, "clientKey", keyPEM)
+	}
+
+	if len(spec.PinnedCertificates) > 0 {
+		pins := make([]string, 0, len(spec.PinnedCertificates))
+		for _, pin := range spec.PinnedCertificates {
+			pins = append(pins, normalizeFingerprint(pin))
+		}
+		config.VerifyConnection = verifyPinnedCertificate(pins)
+		writeHash(h, "pins", []byte(strings.Join(pins, ",")))
+	}
+
+	return config, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// getCABundle reads the CA bundle from the referenced secret or configmap.
+func (r *ClusterReconciler) getCABundle(
+	ctx context.Context,
+	namespace string,
+	ca *slinkyv1alpha1.ClusterTLSCABundle,
+) ([]byte, error) {
+	key := ca.Key
+	if key == "" {
+		key = slinkyv1alpha1.DefaultCABundleKey
+	}
+
+	if ca.SecretRef != "" {
+		secret := &corev1.Secret{}
+		name := types.NamespacedName{Namespace: namespace, Name: ca.SecretRef}
+		if err := r.Get(ctx, name, secret); err != nil {
+			return nil, fmt.Errorf("failed to get CA bundle Secret %q: %w", ca.SecretRef, err)
+		}
+		data := secret.Data[key]
+		if len(data) == 0 {
+			return nil, fmt.Errorf("CA bundle Secret %q has no %q key", ca.SecretRef, key)
+		}
+		return data, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	name := types.NamespacedName{Namespace: namespace, Name: ca.ConfigMapRef}
+	if err := r.Get(ctx, name, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get CA bundle ConfigMap %q: %w", ca.ConfigMapRef, err)
+	}
+	data := []byte(configMap.Data[key])
+	if len(data) == 0 {
+		data = configMap.BinaryData[key]
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("CA bundle ConfigMap %q has no %q key", ca.ConfigMapRef, key)
+	}
+	return data, nil
+}
+
+// verifyPinnedCertificate returns a tls.Config.VerifyConnection function that
+// rejects servers whose leaf certificate does not match one of the pins.
+func verifyPinnedCertificate(pins []string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("server presented no certificate")
+		}
+		sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		fingerprint := hex.EncodeToString(sum[:])
+		if !slices.Contains(pins, fingerprint) {
+			return fmt.Errorf("server certificate fingerprint %s is not pinned", fingerprint)
+		}
+		return nil
+	}
+}
+
+// normalizeFingerprint lowercases a hex fingerprint and drops colons.
+func normalizeFingerprint(pin string) string {
+	return strings.ToLower(strings.ReplaceAll(pin, ":", ""))
+}
+
+func writeHash(h hash.Hash, name string, data []byte) {
+	_, _ = fmt.Fprintf(h, "%s:%d:", name, len(data))
+	_, _ = h.Write(data)
+}
@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+)
+
+func newKeyPairPEM(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "slurm-operator"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func TestClusterReconciler_clusterTLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	sum := sha256.Sum256(server.Certificate().Raw)
+	serverPin := hex.EncodeToString(sum[:])
+	certPEM, keyPEM := newKeyPairPEM(t)
+
+	caConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm-ca",
+		},
+		Data: map[string]string{
+			slinkyv1alpha1.DefaultCABundleKey: string(caPEM),
+		},
+	}
+	clientSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm-client-tls",
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		tls          *slinkyv1alpha1.ClusterTLS
+		objects      []client.Object
+		wantNil      bool
+		wantErr      bool
+		wantNotFound bool
+		wantConnErr  bool
+		wantCode     int
+	}{
+		{
+			name:    "No TLS",
+			tls:     nil,
+			wantNil: true,
+		},
+		{
+			name: "CA bundle from configmap",
+			tls: &slinkyv1alpha1.ClusterTLS{
+				CABundle: &slinkyv1alpha1.ClusterTLSCABundle{
+					ConfigMapRef: caConfigMap.Name,
+				},
+			},
+			objects:  []client.Object{caConfigMap},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "CA bundle not found",
+			tls: &slinkyv1alpha1.ClusterTLS{
+				CABundle: &slinkyv1alpha1.ClusterTLSCABundle{
+					SecretRef: "missing",
+				},
+			},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name: "CA bundle missing key",
+			tls: &slinkyv1alpha1.ClusterTLS{
+				CABundle: &slinkyv1alpha1.ClusterTLSCABundle{
+					ConfigMapRef: caConfigMap.Name,
+					Key:          "other.crt",
+				},
+			},
+			objects: []client.Object{caConfigMap},
+			wantErr: true,
+		},
+		{
+			name: "Client certificate and pinned server",
+			tls: &slinkyv1alpha1.ClusterTLS{
+				CABundle: &slinkyv1alpha1.ClusterTLSCABundle{
+					ConfigMapRef: caConfigMap.Name,
+				},
+				ClientCertSecretRef: clientSecret.Name,
+				PinnedCertificates:  []string{serverPin},
+			},
+			objects:  []client.Object{caConfigMap, clientSecret},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "Pin mismatch",
+			tls: &slinkyv1alpha1.ClusterTLS{
+				CABundle: &slinkyv1alpha1.ClusterTLSCABundle{
+					ConfigMapRef: caConfigMap.Name,
+				},
+				PinnedCertificates: []string{hex.EncodeToString(make([]byte, sha256.Size))},
+			},
+			objects:     []client.Object{caConfigMap},
+			wantConnErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			r := newClusterController(c, resources.NewClusters())
+			cluster := newCluster("foo")
+			cluster.Spec.TLS = tt.tls
+
+			config, hash, err := r.clusterTLSConfig(context.TODO(), cluster)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clusterTLSConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if apierrors.IsNotFound(err) != tt.wantNotFound {
+				t.Errorf("clusterTLSConfig() IsNotFound = %v, want %v", apierrors.IsNotFound(err), tt.wantNotFound)
+			}
+			if err != nil {
+				return
+			}
+			if (config == nil) != tt.wantNil {
+				t.Fatalf("clusterTLSConfig() config = %v, wantNil %v", config, tt.wantNil)
+			}
+			if config == nil {
+				return
+			}
+			if hash == "" {
+				t.Errorf("clusterTLSConfig() hash is empty")
+			}
+
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = config
+			res, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if (err != nil) != tt.wantConnErr {
+				t.Fatalf("Get() error = %v, wantConnErr %v", err, tt.wantConnErr)
+			}
+			if err == nil {
+				_ = res.Body.Close()
+				if res.StatusCode != tt.wantCode {
+					t.Errorf("Get() StatusCode = %v, want %v", res.StatusCode, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_clusterSecretRefs(t *testing.T) {
+	cluster := newCluster("foo")
+	cluster.Spec.TLS = &slinkyv1alpha1.ClusterTLS{
+		CABundle: &slinkyv1alpha1.ClusterTLSCABundle{
+			SecretRef: "ca",
+		},
+		ClientCertSecretRef: "client",
+	}
+	got := clusterSecretRefs(cluster)
+	want := []string{"foo-token", "ca", "client"}
+	if len(got) != len(want) {
+		t.Fatalf("clusterSecretRefs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("clusterSecretRefs() = %v, want %v", got, want)
+		}
+	}
+	if got := clusterConfigMapRefs(cluster); len(got) != 0 {
+		t.Errorf("clusterConfigMapRefs() = %v, want []", got)
+	}
+}
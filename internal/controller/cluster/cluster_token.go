@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/jwt"
+)
+
+// clusterAuthToken returns the auth token for the cluster, either read from the
+// token secret or minted with the JWT signing key. The current token is reused
+// while it remains valid. An empty token is returned, with the conditions set
+// and a requeue scheduled, when the referenced secret is not usable yet.
+func (r *ClusterReconciler) clusterAuthToken(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+	current string,
+) (string, error) {
+	if cluster.Spec.Token.JWT != nil {
+		return r.mintAuthToken(ctx, cluster, status, current)
+	}
+
+	secretName := cluster.Spec.Token.SecretRef
+	data, err := r.getSecretKey(ctx, cluster, status, secretName, authTokenKey)
+	if err != nil || data == nil {
+		return "", err
+	}
+	setCondition(cluster, status, slinkyv1alpha1.ClusterSecretResolved, metav1.ConditionTrue,
+		reasonSecretFound, fmt.Sprintf("Read auth token from Secret %q", secretName))
+
+	// Static tokens are usually JWTs, surface their expiry when they have one
+	status.TokenExpiresAt = nil
+	if claims, err := jwt.Parse(string(data)); err == nil && !claims.Expiry().IsZero() {
+		status.TokenExpiresAt = ptr.To(metav1.NewTime(claims.Expiry()))
+	}
+
+	return string(data), nil
+}
+
+// syncTokenExpiry sets the TokenExpiring condition, emits a Warning event when
+// a static token enters the expiry window or expires, and requeues for the
+// next transition.
+func (r *ClusterReconciler) syncTokenExpiry(
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+	now time.Time,
+) {
+	if cluster.Spec.Token.JWT != nil {
+		setCondition(cluster, status, slinkyv1alpha1.ClusterTokenExpiring, metav1.ConditionFalse,
+			reasonTokenRotated, "Token is minted and rotated by the operator")
+		return
+	}
+	if status.TokenExpiresAt == nil {
+		setCondition(cluster, status, slinkyv1alpha1.ClusterTokenExpiring, metav1.ConditionFalse,
+			reasonTokenNoExpiry, "Token has no expiry")
+		return
+	}
+
+	secretName := cluster.Spec.Token.SecretRef
+	expiresAt := status.TokenExpiresAt.Time
+	remaining := expiresAt.Sub(now)
+	oldReason := ""
+	if old := apimeta.FindStatusCondition(status.Conditions, slinkyv1alpha1.ClusterTokenExpiring); old != nil {
+		oldReason = old.Reason
+	}
+	var reason, message string
+	switch {
+	case remaining <= 0:
+		reason = reasonTokenExpired
+		message = fmt.Sprintf("Token in Secret %q expired at %s", secretName, expiresAt.UTC().Format(time.RFC3339))
+		setCondition(cluster, status, slinkyv1alpha1.ClusterTokenExpiring, metav1.ConditionTrue, reason, message)
+	case remaining <= tokenExpiryWindow:
+		reason = reasonTokenExpiring
+		message = fmt.Sprintf("Token in Secret %q expires at %s", secretName, expiresAt.UTC().Format(time.RFC3339))
+		setCondition(cluster, status, slinkyv1alpha1.ClusterTokenExpiring, metav1.ConditionTrue, reason, message)
+		durationStore.Push(utils.KeyFunc(cluster), remaining)
+	default:
+		setCondition(cluster, status, slinkyv1alpha1.ClusterTokenExpiring, metav1.ConditionFalse,
+			reasonTokenValid, fmt.Sprintf("Token in Secret %q expires at %s", secretName, expiresAt.UTC().Format(time.RFC3339)))
+		durationStore.Push(utils.KeyFunc(cluster), remaining-tokenExpiryWindow)
+		return
+	}
+
+	// Only emit on transition, the cluster is requeued often
+	if oldReason != reason {
+		r.eventRecorder.Event(cluster, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// mintAuthToken returns a token signed with the JWT signing key. The current
+// token is reused when it was signed with the same key for the same user, and
+// more than a fifth of its lifetime remains.
+func (r *ClusterReconciler) mintAuthToken(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+	current string,
+) (string, error) {
+	logger := log.FromContext(ctx)
+	spec := cluster.Spec.Token.JWT
+
+	key, err := r.getSecretKey(ctx, cluster, status, spec.KeySecretRef, slinkyv1alpha1.JWTKeySecretKey)
+	if err != nil || key == nil {
+		return "", err
+	}
+	setCondition(cluster, status, slinkyv1alpha1.ClusterSecretResolved, metav1.ConditionTrue,
+		reasonSecretFound, fmt.Sprintf("Read signing key from Secret %q", spec.KeySecretRef))
+
+	lifetime := slinkyv1alpha1.DefaultJWTLifetime
+	if spec.Lifetime != nil {
+		lifetime = spec.Lifetime.Duration
+	}
+	refreshBefore := lifetime / 5
+	now := time.Now()
+
+	token := current
+	claims, err := jwt.Verify(key, current)
+	if err != nil ||
+		claims.Username != spec.Username ||
+		claims.Expiry().Sub(now) <= refreshBefore {
+		token, err = jwt.Mint(key, spec.Username, now, lifetime)
+		if err != nil {
+			return "", err
+		}
+		claims, err = jwt.Parse(token)
+		if err != nil {
+			return "", err
+		}
+		logger.Info("Minted auth token", "username", spec.Username, "expiresAt", claims.Expiry())
+	}
+
+	status.TokenExpiresAt = ptr.To(metav1.NewTime(claims.Expiry()))
+	durationStore.Push(utils.KeyFunc(cluster), max(claims.Expiry().Sub(now)-refreshBefore, time.Second))
+
+	return token, nil
+}
+
+// getSecretKey reads a key from a secret in the cluster namespace. When the
+// secret or key does not exist, the conditions are set, a requeue is scheduled,
+// and nil is returned.
+func (r *ClusterReconciler) getSecretKey(
+	ctx context.Context,
+	cluster *slinkyv1alpha1.Cluster,
+	status *slinkyv1alpha1.ClusterStatus,
+	secretName, key string,
+) ([]byte, error) {
+	logger := log.FromContext(ctx)
+
+	secret := &corev1.Secret{}
+	secretNamespacedName := types.NamespacedName{
+		Namespace: cluster.Namespace,
+		Name:      secretName,
+	}
+	if err := r.Get(ctx, secretNamespacedName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Secret not found, retry later", "secretName", secretName)
+			setCondition(cluster, status, slinkyv1alpha1.ClusterSecretResolved, metav1.ConditionFalse,
+				reasonSecretNotFound, fmt.Sprintf("Secret %q not found", secretName))
+			setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionFalse,
+				reasonSecretNotResolved, fmt.Sprintf("Waiting for Secret %q", secretName))
+			durationStore.Push(utils.KeyFunc(cluster), requeueSecretTime)
+			return nil, nil
+		}
+		logger.Info("Failed to get secret", "secretName", secretName, "error", err)
+		setCondition(cluster, status, slinkyv1alpha1.ClusterSecretResolved, metav1.ConditionUnknown,
+			reasonSecretError, fmt.Sprintf("Failed to get Secret %q: %v", secretName, err))
+		return nil, err
+	}
+
+	data := secret.Data[key]
+	if len(data) == 0 {
+		logger.Info("Secret key is missing, retry later", "secretName", secretName, "key", key)
+		setCondition(cluster, status, slinkyv1alpha1.ClusterSecretResolved, metav1.ConditionFalse,
+			reasonKeyMissing, fmt.Sprintf("Secret %q has no %q key", secretName, key))
+		setCondition(cluster, status, slinkyv1alpha1.ClusterClientConfigured, metav1.ConditionFalse,
+			reasonSecretNotResolved, fmt.Sprintf("Waiting for Secret %q", secretName))
+		durationStore.Push(utils.KeyFunc(cluster), requeueSecretTime)
+		return nil, nil
+	}
+
+	return data, nil
+}
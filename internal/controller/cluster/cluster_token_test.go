@@ -0,0 +1,299 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slurmfake "github.com/SlinkyProject/slurm-client/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/jwt"
+)
+
+func newJWTCluster(name string) *slinkyv1alpha1.Cluster {
+	cluster := newCluster(name)
+	cluster.Spec.Token = slinkyv1alpha1.ClusterToken{
+		JWT: &slinkyv1alpha1.ClusterTokenJWT{
+			KeySecretRef: name + "-jwt-key",
+			Username:     "slurm",
+			Lifetime:     &metav1.Duration{Duration: time.Hour},
+		},
+	}
+	return cluster
+}
+
+func TestClusterReconciler_clusterAuthToken(t *testing.T) {
+	key := []byte("jwt-key")
+	cluster := newJWTCluster("foo")
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Spec.Token.JWT.KeySecretRef,
+		},
+		Data: map[string][]byte{
+			slinkyv1alpha1.JWTKeySecretKey: key,
+		},
+	}
+	mustMint := func(key []byte, username string, issued time.Time) string {
+		token, err := jwt.Mint(key, username, issued, time.Hour)
+		if err != nil {
+			t.Fatalf("Mint() error = %v", err)
+		}
+		return token
+	}
+	fresh := mustMint(key, "slurm", time.Now())
+
+	tests := []struct {
+		name       string
+		cluster    *slinkyv1alpha1.Cluster
+		objects    []client.Object
+		current    string
+		wantToken  string
+		wantReused bool
+		wantEmpty  bool
+		wantExpiry bool
+	}{
+		{
+			name:    "Static token",
+			cluster: newCluster("foo"),
+			objects: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cluster.Namespace,
+						Name:      "foo-token",
+					},
+					Data: map[string][]byte{
+						authTokenKey: []byte("static"),
+					},
+				},
+			},
+			wantToken: "static",
+		},
+		{
+			name:    "Static JWT token",
+			cluster: newCluster("foo"),
+			objects: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cluster.Namespace,
+						Name:      "foo-token",
+					},
+					Data: map[string][]byte{
+						authTokenKey: []byte(fresh),
+					},
+				},
+			},
+			wantToken:  fresh,
+			wantExpiry: true,
+		},
+		{
+			name:       "Mint without current token",
+			cluster:    cluster,
+			objects:    []client.Object{keySecret},
+			wantExpiry: true,
+		},
+		{
+			name:       "Reuse valid token",
+			cluster:    cluster,
+			objects:    []client.Object{keySecret},
+			current:    fresh,
+			wantReused: true,
+			wantExpiry: true,
+		},
+		{
+			name:       "Mint when key rotated",
+			cluster:    cluster,
+			objects:    []client.Object{keySecret},
+			current:    mustMint([]byte("old-key"), "slurm", time.Now()),
+			wantExpiry: true,
+		},
+		{
+			name:       "Mint when username changed",
+			cluster:    cluster,
+			objects:    []client.Object{keySecret},
+			current:    mustMint(key, "root", time.Now()),
+			wantExpiry: true,
+		},
+		{
+			name:       "Mint when close to expiry",
+			cluster:    cluster,
+			objects:    []client.Object{keySecret},
+			current:    mustMint(key, "slurm", time.Now().Add(-55*time.Minute)),
+			wantExpiry: true,
+		},
+		{
+			name:      "Key secret not found",
+			cluster:   cluster,
+			wantEmpty: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			r := newClusterController(c, resources.NewClusters())
+			status := tt.cluster.Status.DeepCopy()
+			got, err := r.clusterAuthToken(context.TODO(), tt.cluster, status, tt.current)
+			if err != nil {
+				t.Fatalf("clusterAuthToken() error = %v", err)
+			}
+			if (got == "") != tt.wantEmpty {
+				t.Fatalf("clusterAuthToken() = %q, wantEmpty %v", got, tt.wantEmpty)
+			}
+			if tt.wantToken != "" && got != tt.wantToken {
+				t.Errorf("clusterAuthToken() = %q, want %q", got, tt.wantToken)
+			}
+			if tt.current != "" && (got == tt.current) != tt.wantReused {
+				t.Errorf("clusterAuthToken() reused = %v, want %v", got == tt.current, tt.wantReused)
+			}
+			if (status.TokenExpiresAt != nil) != tt.wantExpiry {
+				t.Errorf("TokenExpiresAt = %v, want set = %v", status.TokenExpiresAt, tt.wantExpiry)
+			}
+			if tt.cluster.Spec.Token.JWT != nil && got != "" {
+				claims, err := jwt.Verify(key, got)
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				if claims.Username != "slurm" {
+					t.Errorf("Username = %v, want slurm", claims.Username)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_slurmClientUpdate_rotateToken(t *testing.T) {
+	cluster := newCluster("foo")
+	clusterName := types.NamespacedName{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Spec.Token.SecretRef,
+		},
+		Data: map[string][]byte{
+			authTokenKey: []byte("new-token"),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(cluster, secret).Build()
+	endpoints, err := resources.NewEndpoints(clusterServers(cluster), nil)
+	if err != nil {
+		t.Fatalf("NewEndpoints() error = %v", err)
+	}
+	endpoints.SetToken("old-token")
+	slurmClient := slurmfake.NewFakeClient()
+	slurmClusters := resources.NewClusters()
+	slurmClusters.AddWithEndpoints(clusterName, slurmClient, endpoints, "")
+	defer slurmClusters.Remove(clusterName)
+
+	r := newClusterController(c, slurmClusters)
+	status := cluster.Status.DeepCopy()
+	if err := r.slurmClientUpdate(context.TODO(), cluster, status); err != nil {
+		t.Fatalf("slurmClientUpdate() error = %v", err)
+	}
+	if got := slurmClusters.Get(clusterName); got != slurmClient {
+		t.Errorf("slurm client was replaced, want token swapped in place")
+	}
+	if got := endpoints.Token(); got != "new-token" {
+		t.Errorf("Token() = %v, want new-token", got)
+	}
+}
+
+func TestClusterReconciler_syncTokenExpiry(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		cluster    *slinkyv1alpha1.Cluster
+		expiresAt  *time.Time
+		oldReason  string
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantEvent  bool
+	}{
+		{
+			name:       "Minted token",
+			cluster:    newJWTCluster("foo"),
+			expiresAt:  ptr.To(now.Add(time.Minute)),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reasonTokenRotated,
+		},
+		{
+			name:       "No expiry",
+			cluster:    newCluster("foo"),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reasonTokenNoExpiry,
+		},
+		{
+			name:       "Valid",
+			cluster:    newCluster("foo"),
+			expiresAt:  ptr.To(now.Add(tokenExpiryWindow + time.Hour)),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reasonTokenValid,
+		},
+		{
+			name:       "Expiring",
+			cluster:    newCluster("foo"),
+			expiresAt:  ptr.To(now.Add(time.Hour)),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonTokenExpiring,
+			wantEvent:  true,
+		},
+		{
+			name:       "Still expiring",
+			cluster:    newCluster("foo"),
+			expiresAt:  ptr.To(now.Add(time.Hour)),
+			oldReason:  reasonTokenExpiring,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonTokenExpiring,
+			wantEvent:  false,
+		},
+		{
+			name:       "Expired",
+			cluster:    newCluster("foo"),
+			expiresAt:  ptr.To(now.Add(-time.Hour)),
+			oldReason:  reasonTokenExpiring,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonTokenExpired,
+			wantEvent:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newClusterController(fake.NewFakeClient(), resources.NewClusters())
+			recorder := r.eventRecorder.(*record.FakeRecorder)
+			status := tt.cluster.Status.DeepCopy()
+			if tt.expiresAt != nil {
+				status.TokenExpiresAt = ptr.To(metav1.NewTime(*tt.expiresAt))
+			}
+			if tt.oldReason != "" {
+				setCondition(tt.cluster, status, slinkyv1alpha1.ClusterTokenExpiring, metav1.ConditionTrue, tt.oldReason, "")
+			}
+			r.syncTokenExpiry(tt.cluster, status, now)
+			condition := apimeta.FindStatusCondition(status.Conditions, slinkyv1alpha1.ClusterTokenExpiring)
+			if condition == nil {
+				t.Fatalf("TokenExpiring condition not set")
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("TokenExpiring = %v/%v, want %v/%v", condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if got := len(recorder.Events) > 0; got != tt.wantEvent {
+				t.Errorf("event emitted = %v, want %v", got, tt.wantEvent)
+			}
+		})
+	}
+}
@@ -6,22 +6,44 @@ package slurmcontrol
 import (
 	"context"
 	"net/http"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
-	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
-
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
 )
 
 type SlurmControlInterface interface {
 	// PingController sends a ping request to check connectivity.
-	PingController(ctx context.Context, cluster *slinkyv1alpha1.Cluster) (bool, error)
+	PingController(ctx context.Context, cluster *slinkyv1alpha1.Cluster) (*PingResult, error)
+	// GetInventory returns the Slurm cluster information, nodes, and partitions.
+	GetInventory(ctx context.Context, cluster *slinkyv1alpha1.Cluster) (*Inventory, error)
+}
+
+// PingResult is the outcome of a controller ping.
+type PingResult struct {
+	// HasClient is false when no Slurm client is registered for the cluster.
+	HasClient bool
+	// IsUp is true when at least one slurmctld responded UP.
+	IsUp bool
+	// Hostname is the slurmctld that responded UP.
+	Hostname string
+	// Latency is the ping latency reported by slurmctld, falling back to the
+	// request round-trip time when not reported.
+	Latency time.Duration
+}
+
+// Inventory is what slurmctld knows about the cluster.
+type Inventory struct {
+	// Info is nil when slurmrestd did not report it.
+	Info       *slurmapi.ClusterInfo
+	Nodes      []slurmapi.Node
+	Partitions []slurmapi.Partition
 }
 
 // realSlurmControl is the default implementation of SlurmControlInterface.
@@ -30,38 +52,94 @@ type realSlurmControl struct {
 }
 
 // PingController implements SlurmControlInterface.
-func (r *realSlurmControl) PingController(ctx context.Context, cluster *slinkyv1alpha1.Cluster) (bool, error) {
+func (r *realSlurmControl) PingController(ctx context.Context, cluster *slinkyv1alpha1.Cluster) (*PingResult, error) {
 	logger := log.FromContext(ctx)
+	result := &PingResult{}
 
-	slurmClient := r.lookupClient(cluster)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(cluster)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for cluster, cannot do PingController()",
 			"cluster", klog.KObj(cluster))
-		return false, nil
+		return result, nil
 	}
+	result.HasClient = true
 
-	pingList := &slurmtypes.V0041ControllerPingList{}
-	if err := slurmClient.List(ctx, pingList); err != nil {
+	start := time.Now()
+	pingList, err := slurmAPI.PingControllers(ctx)
+	if err != nil {
 		if tolerateError(err) {
-			return false, nil
+			return result, nil
+		}
+		return result, err
+	}
+	elapsed := time.Since(start)
+	for _, ping := range pingList {
+		if ping.IsUp {
+			result.IsUp = true
+			result.Hostname = ping.Hostname
+			result.Latency = elapsed
+			if ping.Latency > 0 {
+				result.Latency = ping.Latency
+			}
+			return result, nil
 		}
-		return false, err
 	}
-	for _, ping := range pingList.Items {
-		if ptr.Deref(ping.Pinged, "") == slurmtypes.V0041ControllerPingPingedUP {
-			return true, nil
+
+	return result, nil
+}
+
+// inventoryTimeout bounds the cluster info request.
+const inventoryTimeout = 10 * time.Second
+
+// GetInventory implements SlurmControlInterface.
+func (r *realSlurmControl) GetInventory(ctx context.Context, cluster *slinkyv1alpha1.Cluster) (*Inventory, error) {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(cluster)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for cluster, cannot do GetInventory()",
+			"cluster", klog.KObj(cluster))
+		return nil, nil
+	}
+
+	inventory := &Inventory{}
+	clusterName := types.NamespacedName{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.GetName(),
+	}
+	if endpoints := r.slurmClusters.GetEndpoints(clusterName); endpoints != nil {
+		httpClient := &http.Client{
+			Transport: endpoints,
+			Timeout:   inventoryTimeout,
+		}
+		info, err := slurmapi.GetClusterInfo(ctx, httpClient, endpoints.Active(), slurmAPI.Version())
+		if err != nil {
+			logger.V(1).Info("unable to get Slurm cluster info", "cluster", klog.KObj(cluster), "error", err)
 		}
+		inventory.Info = info
+	}
+
+	nodes, err := slurmAPI.ListNodes(ctx)
+	if err != nil && !tolerateError(err) {
+		return nil, err
 	}
+	inventory.Nodes = nodes
 
-	return false, nil
+	partitions, err := slurmAPI.ListPartitions(ctx)
+	if err != nil && !tolerateError(err) {
+		return nil, err
+	}
+	inventory.Partitions = partitions
+
+	return inventory, nil
 }
 
-func (r *realSlurmControl) lookupClient(cluster *slinkyv1alpha1.Cluster) slurmclient.Client {
+func (r *realSlurmControl) lookupAPI(cluster *slinkyv1alpha1.Cluster) slurmapi.Interface {
 	clusterName := types.NamespacedName{
 		Namespace: cluster.GetNamespace(),
 		Name:      cluster.GetName(),
 	}
-	return r.slurmClusters.Get(clusterName)
+	return r.slurmClusters.GetAPI(clusterName)
 }
 
 var _ SlurmControlInterface = &realSlurmControl{}
@@ -83,3 +161,14 @@ func tolerateError(err error) bool {
 	}
 	return false
 }
+
+// IsAuthError returns true when the error indicates that slurmrestd rejected
+// the request credentials.
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errText := err.Error()
+	return strings.Contains(errText, http.StatusText(http.StatusUnauthorized)) ||
+		strings.Contains(errText, http.StatusText(http.StatusForbidden))
+}
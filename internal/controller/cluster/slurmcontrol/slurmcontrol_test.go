@@ -59,9 +59,11 @@ var _ = Describe("SlurmControlInterface", func() {
 			slurmcontrol = NewSlurmControl(clusters)
 
 			By("Pinging the Slurm control plane")
-			ok, err := slurmcontrol.PingController(ctx, cluster)
+			result, err := slurmcontrol.PingController(ctx, cluster)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(ok).To(BeTrue())
+			Expect(result.HasClient).To(BeTrue())
+			Expect(result.IsUp).To(BeTrue())
+			Expect(result.Hostname).To(Equal("foo"))
 		})
 
 		It("Should report control pane is down", func() {
@@ -83,9 +85,10 @@ var _ = Describe("SlurmControlInterface", func() {
 			slurmcontrol = NewSlurmControl(clusters)
 
 			By("Pinging the Slurm control plane")
-			ok, err := slurmcontrol.PingController(ctx, cluster)
+			result, err := slurmcontrol.PingController(ctx, cluster)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(ok).To(BeFalse())
+			Expect(result.HasClient).To(BeTrue())
+			Expect(result.IsUp).To(BeFalse())
 		})
 	})
 })
@@ -143,3 +146,57 @@ func Test_tolerateError(t *testing.T) {
 		})
 	}
 }
+
+func Test_IsAuthError(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Nil",
+			args: args{
+				err: nil,
+			},
+			want: false,
+		},
+		{
+			name: "Unauthorized",
+			args: args{
+				err: errors.New(http.StatusText(http.StatusUnauthorized)),
+			},
+			want: true,
+		},
+		{
+			name: "Forbidden",
+			args: args{
+				err: errors.New(http.StatusText(http.StatusForbidden)),
+			},
+			want: true,
+		},
+		{
+			name: "Aggregated",
+			args: args{
+				err: errors.New("[" + http.StatusText(http.StatusUnauthorized) + ", token expired]"),
+			},
+			want: true,
+		},
+		{
+			name: "BadGateway",
+			args: args{
+				err: errors.New(http.StatusText(http.StatusBadGateway)),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.args.err); got != tt.want {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
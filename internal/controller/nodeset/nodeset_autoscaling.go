@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+// autoscalingSyncPeriod is how often an autoscaled NodeSet is requeued,
+// because pending jobs do not trigger NodeSet events.
+var autoscalingSyncPeriod = 30 * time.Second
+
+// syncAutoscaling sets the NodeSet replicas from the Slurm jobs when the
+// built-in autoscaler is enabled. The NodeSet needs one replica for each of
+// its Slurm nodes running jobs, plus one for each node requested by pending
+// jobs in its partition. The replicas are kept while the Slurm cluster is
+// unreachable.
+func (r *NodeSetReconciler) syncAutoscaling(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	autoscaling := nodeset.Spec.Autoscaling
+	if autoscaling == nil {
+		nodeset.Status.Autoscaling = nil
+		return
+	}
+	scalingDurationStore.Push(key, autoscalingSyncPeriod)
+	if isSafeMode(nodeset) {
+		return
+	}
+
+	slurmNodeStatus, err := r.slurmControl.CalculateNodeStatus(ctx, nodeset, pods)
+	if err != nil {
+		logger.Error(err, "unable to get Slurm node status for autoscaling", "nodeset", klog.KObj(nodeset))
+		return
+	}
+	pending, err := r.slurmControl.CalculatePendingNodes(ctx, nodeset, autoscaling.Partition)
+	if err != nil {
+		logger.Error(err, "unable to get pending Slurm jobs for autoscaling", "nodeset", klog.KObj(nodeset))
+		return
+	}
+
+	status := &slinkyv1alpha1.NodeSetAutoscalingStatus{}
+	if nodeset.Status.Autoscaling != nil {
+		status = nodeset.Status.Autoscaling.DeepCopy()
+	}
+	status.BusyNodes = slurmNodeStatus.Allocated + slurmNodeStatus.Mixed
+	status.PendingNodes = pending
+	status.DesiredReplicas = utils.Clamp(status.BusyNodes+status.PendingNodes,
+		autoscaling.MinReplicas, autoscaling.MaxReplicas)
+
+	now := time.Now()
+	current := ptr.Deref(nodeset.Spec.Replicas, 1)
+	replicas, wait := calculateAutoscaling(autoscaling, status, current, now)
+	if wait > 0 {
+		scalingDurationStore.Push(key, wait)
+	}
+	if replicas != current {
+		// Patch a copy, the response would replace the status computed so far.
+		toUpdate := nodeset.DeepCopy()
+		toUpdate.Spec.Replicas = ptr.To(replicas)
+		if err := r.Patch(ctx, toUpdate, client.MergeFrom(nodeset)); err != nil {
+			logger.Error(err, "unable to autoscale NodeSet", "nodeset", klog.KObj(nodeset))
+			return
+		}
+		nodeset.Spec.Replicas = toUpdate.Spec.Replicas
+		nodeset.ResourceVersion = toUpdate.ResourceVersion
+		logger.Info("Autoscaled NodeSet", "nodeset", klog.KObj(nodeset),
+			"from", current, "to", replicas, "busyNodes", status.BusyNodes, "pendingNodes", status.PendingNodes)
+		r.eventRecorder.Eventf(nodeset, corev1.EventTypeNormal, AutoscaleReason,
+			"Scaled from %d to %d replicas: %d busy nodes, %d pending nodes",
+			current, replicas, status.BusyNodes, status.PendingNodes)
+		if replicas > current {
+			status.LastScaleUpTime = ptr.To(metav1.NewTime(now))
+		} else {
+			status.LastScaleDownTime = ptr.To(metav1.NewTime(now))
+		}
+	}
+	nodeset.Status.Autoscaling = status
+}
+
+// calculateAutoscaling returns the replicas to move to from the current
+// replicas, within the scaling policy of the direction. When the period of
+// the policy holds a change back, the remaining wait is returned.
+func calculateAutoscaling(
+	autoscaling *slinkyv1alpha1.NodeSetAutoscaling,
+	status *slinkyv1alpha1.NodeSetAutoscalingStatus,
+	current int32,
+	now time.Time,
+) (int32, time.Duration) {
+	switch {
+	case status.DesiredReplicas > current:
+		return applyScalingPolicy(autoscaling.ScaleUp, status.LastScaleUpTime, current, status.DesiredReplicas, now)
+	case status.DesiredReplicas < current:
+		return applyScalingPolicy(autoscaling.ScaleDown, status.LastScaleDownTime, current, status.DesiredReplicas, now)
+	default:
+		return current, 0
+	}
+}
+
+// applyScalingPolicy limits the change from current to desired replicas by
+// the step and period of the policy.
+func applyScalingPolicy(
+	policy *slinkyv1alpha1.NodeSetScalingPolicy,
+	lastScaleTime *metav1.Time,
+	current, desired int32,
+	now time.Time,
+) (int32, time.Duration) {
+	if policy == nil {
+		return desired, 0
+	}
+	if policy.Period != nil && lastScaleTime != nil {
+		next := lastScaleTime.Add(policy.Period.Duration)
+		if now.Before(next) {
+			return current, next.Sub(now)
+		}
+	}
+	if policy.MaxStep > 0 {
+		desired = utils.Clamp(desired, current-policy.MaxStep, current+policy.MaxStep)
+	}
+	return desired, 0
+}
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	slurminterceptor "github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func Test_calculateAutoscaling(t *testing.T) {
+	now := time.Now()
+	policy := &slinkyv1alpha1.NodeSetScalingPolicy{
+		MaxStep: 2,
+		Period:  &metav1.Duration{Duration: time.Minute},
+	}
+	tests := []struct {
+		name        string
+		autoscaling *slinkyv1alpha1.NodeSetAutoscaling
+		status      *slinkyv1alpha1.NodeSetAutoscalingStatus
+		current     int32
+		want        int32
+		wantWait    time.Duration
+	}{
+		{
+			name:        "Steady",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10},
+			status:      &slinkyv1alpha1.NodeSetAutoscalingStatus{DesiredReplicas: 3},
+			current:     3,
+			want:        3,
+		},
+		{
+			name:        "Scale up without policy",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10},
+			status:      &slinkyv1alpha1.NodeSetAutoscalingStatus{DesiredReplicas: 8},
+			current:     1,
+			want:        8,
+		},
+		{
+			name:        "Scale down without policy",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10},
+			status:      &slinkyv1alpha1.NodeSetAutoscalingStatus{DesiredReplicas: 0},
+			current:     5,
+			want:        0,
+		},
+		{
+			name:        "Scale up by step",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10, ScaleUp: policy},
+			status:      &slinkyv1alpha1.NodeSetAutoscalingStatus{DesiredReplicas: 8},
+			current:     1,
+			want:        3,
+		},
+		{
+			name:        "Scale down by step",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10, ScaleDown: policy},
+			status: &slinkyv1alpha1.NodeSetAutoscalingStatus{
+				DesiredReplicas:   0,
+				LastScaleDownTime: ptr.To(metav1.NewTime(now.Add(-2 * time.Minute))),
+			},
+			current: 5,
+			want:    3,
+		},
+		{
+			name:        "Scale down held by period",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10, ScaleDown: policy},
+			status: &slinkyv1alpha1.NodeSetAutoscalingStatus{
+				DesiredReplicas:   0,
+				LastScaleDownTime: ptr.To(metav1.NewTime(now.Add(-20 * time.Second))),
+			},
+			current:  5,
+			want:     5,
+			wantWait: 40 * time.Second,
+		},
+		{
+			name:        "Scale up period ignores scale down",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10, ScaleUp: policy},
+			status: &slinkyv1alpha1.NodeSetAutoscalingStatus{
+				DesiredReplicas:   4,
+				LastScaleDownTime: ptr.To(metav1.NewTime(now)),
+			},
+			current: 3,
+			want:    4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotWait := calculateAutoscaling(tt.autoscaling, tt.status, tt.current, now)
+			if got != tt.want {
+				t.Errorf("calculateAutoscaling() = %v, want %v", got, tt.want)
+			}
+			if gotWait.Round(time.Second) != tt.wantWait {
+				t.Errorf("calculateAutoscaling() wait = %v, want %v", gotWait, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_syncAutoscaling(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	newJob := func(id int32, state v0041.V0041JobInfoJobState, partition string, nodeCount int32) slurmtypes.V0041JobInfo {
+		return slurmtypes.V0041JobInfo{
+			V0041JobInfo: v0041.V0041JobInfo{
+				JobId:     ptr.To(id),
+				JobState:  ptr.To([]v0041.V0041JobInfoJobState{state}),
+				Partition: ptr.To(partition),
+				NodeCount: &v0041.V0041Uint32NoValStruct{Number: ptr.To(nodeCount)},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		autoscaling *slinkyv1alpha1.NodeSetAutoscaling
+		jobs        []slurmtypes.V0041JobInfo
+		safeMode    bool
+		want        int32
+		wantStatus  *slinkyv1alpha1.NodeSetAutoscalingStatus
+	}{
+		{
+			name: "Disabled",
+			want: 2,
+		},
+		{
+			name:        "Pending jobs scale up",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10, Partition: "debug"},
+			jobs: []slurmtypes.V0041JobInfo{
+				newJob(1, v0041.V0041JobInfoJobStatePENDING, "debug", 3),
+				newJob(2, v0041.V0041JobInfoJobStatePENDING, "gpu", 4),
+			},
+			want: 4,
+			wantStatus: &slinkyv1alpha1.NodeSetAutoscalingStatus{
+				DesiredReplicas: 4,
+				BusyNodes:       1,
+				PendingNodes:    3,
+			},
+		},
+		{
+			name:        "Bounded by max replicas",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 3},
+			jobs: []slurmtypes.V0041JobInfo{
+				newJob(1, v0041.V0041JobInfoJobStatePENDING, "debug", 8),
+			},
+			want: 3,
+			wantStatus: &slinkyv1alpha1.NodeSetAutoscalingStatus{
+				DesiredReplicas: 3,
+				BusyNodes:       1,
+				PendingNodes:    8,
+			},
+		},
+		{
+			name:        "Idle nodes scale down",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10},
+			want:        1,
+			wantStatus: &slinkyv1alpha1.NodeSetAutoscalingStatus{
+				DesiredReplicas: 1,
+				BusyNodes:       1,
+			},
+		},
+		{
+			name:        "Bounded by min replicas",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MinReplicas: 2, MaxReplicas: 10},
+			want:        2,
+			wantStatus: &slinkyv1alpha1.NodeSetAutoscalingStatus{
+				DesiredReplicas: 2,
+				BusyNodes:       1,
+			},
+		},
+		{
+			name:        "Safe mode keeps replicas",
+			autoscaling: &slinkyv1alpha1.NodeSetAutoscaling{MaxReplicas: 10},
+			safeMode:    true,
+			want:        2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 2)
+			nodeset.Spec.Autoscaling = tt.autoscaling
+			key := utils.KeyFunc(nodeset)
+			defer scalingDurationStore.Pop(key)
+
+			pods := []*corev1.Pod{
+				makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, "")),
+				makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 1, "")),
+			}
+			busyNode := newNodeSetPodSlurmNode(pods[0])
+			busyNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED})
+			nodeList := &slurmtypes.V0041NodeList{
+				Items: []slurmtypes.V0041Node{*busyNode, *newNodeSetPodSlurmNode(pods[1])},
+			}
+			jobList := &slurmtypes.V0041JobInfoList{Items: tt.jobs}
+			pingList := &slurmtypes.V0041ControllerPingList{
+				Items: []slurmtypes.V0041ControllerPing{
+					{
+						V0041ControllerPing: v0041.V0041ControllerPing{
+							Hostname: ptr.To("slurmctld-0"),
+							Pinged:   ptr.To(slurmtypes.V0041ControllerPingPingedUP),
+						},
+					},
+				},
+			}
+			fakeClient := newFakeClientList(slurminterceptor.Funcs{}, nodeList, jobList, pingList)
+			// The Slurm nodes were refreshed earlier in the sync.
+			refreshed := false
+			slurmClient := slurminterceptor.NewClient(fakeClient, slurminterceptor.Funcs{
+				List: func(ctx context.Context, list object.ObjectList, opts ...slurmclient.ListOption) error {
+					options := &slurmclient.ListOptions{}
+					refreshed = refreshed || options.ApplyOptions(opts).RefreshCache
+					return fakeClient.List(ctx, list, opts...)
+				},
+			})
+			slurmClusters := newSlurmClusters(clusterName, slurmClient)
+			if tt.safeMode {
+				slurmClusters = resources.NewClusters()
+			}
+
+			c := fake.NewClientBuilder().WithObjects(nodeset.DeepCopy()).Build()
+			r := newNodeSetController(c, slurmClusters)
+			r.syncSafeMode(context.TODO(), nodeset)
+			defer durationStore.Pop(key)
+			r.syncAutoscaling(context.TODO(), nodeset, pods)
+			if refreshed {
+				t.Errorf("syncAutoscaling() refreshed the Slurm nodes, want the cached nodes read")
+			}
+
+			got := &slinkyv1alpha1.NodeSet{}
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(nodeset), got); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if replicas := ptr.Deref(got.Spec.Replicas, 1); replicas != tt.want {
+				t.Errorf("replicas = %v, want %v", replicas, tt.want)
+			}
+			if replicas := ptr.Deref(nodeset.Spec.Replicas, 1); replicas != tt.want {
+				t.Errorf("in-memory replicas = %v, want %v", replicas, tt.want)
+			}
+			// The status computed earlier in the sync is kept.
+			if apimeta.FindStatusCondition(nodeset.Status.Conditions, slinkyv1alpha1.NodeSetSlurmUnreachable) == nil {
+				t.Errorf("Status.Conditions = %v, want %s kept", nodeset.Status.Conditions, slinkyv1alpha1.NodeSetSlurmUnreachable)
+			}
+			status := nodeset.Status.Autoscaling
+			if tt.wantStatus == nil {
+				if status != nil && !tt.safeMode {
+					t.Errorf("Status.Autoscaling = %+v, want nil", status)
+				}
+				return
+			}
+			if status == nil {
+				t.Fatalf("Status.Autoscaling = nil, want %+v", tt.wantStatus)
+			}
+			if status.DesiredReplicas != tt.wantStatus.DesiredReplicas ||
+				status.BusyNodes != tt.wantStatus.BusyNodes ||
+				status.PendingNodes != tt.wantStatus.PendingNodes {
+				t.Errorf("Status.Autoscaling = %+v, want %+v", status, tt.wantStatus)
+			}
+			if (tt.want > 2) != (status.LastScaleUpTime != nil) {
+				t.Errorf("LastScaleUpTime = %v", status.LastScaleUpTime)
+			}
+			if (tt.want < 2) != (status.LastScaleDownTime != nil) {
+				t.Errorf("LastScaleDownTime = %v", status.LastScaleDownTime)
+			}
+		})
+	}
+}
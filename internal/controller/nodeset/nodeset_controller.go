@@ -16,9 +16,11 @@ import (
 	"k8s.io/client-go/util/flowcontrol"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -44,6 +46,30 @@ const (
 	FailedPlacementReason = "FailedPlacement"
 	// FailedNodeSetPodReason is added to an event when the status of a Pod of a NodeSet is 'Failed'.
 	FailedNodeSetPodReason = "FailedNodeSetPod"
+	// ClusterReferenceNotGrantedReason is added to an event when a NodeSet references a Cluster in another namespace without a ClusterReferenceGrant.
+	ClusterReferenceNotGrantedReason = "ClusterReferenceNotGranted"
+	// AutoscaleReason is added to an event when the built-in autoscaler changes the replicas of a NodeSet.
+	AutoscaleReason = "Autoscale"
+	// DrainTimeoutReason is added to an event when a condemned Pod of a NodeSet did not drain within the drain timeout.
+	DrainTimeoutReason = "DrainTimeout"
+	// PodDeletedWithJobsReason is added to an event when a Pod of a NodeSet was deleted while its Slurm node ran jobs.
+	PodDeletedWithJobsReason = "PodDeletedWithJobs"
+	// RemediationReason is added to an event when a Pod of a NodeSet is recreated because its Slurm node stayed DOWN, FAIL, or NOT_RESPONDING.
+	RemediationReason = "Remediation"
+	// RebootReason is added to an event when a Pod of a NodeSet is recreated for the reboot requested on its Slurm node.
+	RebootReason = "Reboot"
+	// PowerDownReason is added to an event when a NodeSet is scaled-in for the power down requested on its Slurm nodes.
+	PowerDownReason = "PowerDown"
+)
+
+// Reasons for the NodeSet SlurmUnreachable condition
+const (
+	// SlurmReachableReason is set when the Slurm cluster responded to a ping.
+	SlurmReachableReason = "Reachable"
+	// SafeModeReason is set when the Slurm cluster is unreachable, so scale-in and rolling updates are paused.
+	SafeModeReason = "SafeMode"
+	// SafeModeIgnoredReason is set when the Slurm cluster is unreachable, but the NodeSet opted out of safe mode.
+	SafeModeIgnoredReason = "SafeModeIgnored"
 )
 
 func init() {
@@ -55,9 +81,13 @@ var (
 
 	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
 	durationStore = durationstore.NewDurationStore(durationstore.Greater)
+	// scalingDurationStore requeues NodeSets when an autoscaling or scale-in
+	// decision is due, unless the reconcile requeues sooner.
+	scalingDurationStore = durationstore.NewDurationStore(durationstore.Less)
 
 	onceBackoffGC     sync.Once
 	failedPodsBackoff = flowcontrol.NewBackOff(1*time.Second, 15*time.Minute)
+	safeModeBackoff   = flowcontrol.NewBackOff(5*time.Second, 5*time.Minute)
 )
 
 // NodeSetReconciler reconciles a NodeSet object
@@ -73,11 +103,14 @@ type NodeSetReconciler struct {
 	historyControl historycontrol.HistoryControlInterface
 	eventRecorder  record.EventRecorderLogger
 	expectations   *kubecontroller.UIDTrackingControllerExpectations
+
+	deletedPodQueue *deletedPodQueue
 }
 
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=nodesets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=nodesets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=nodesets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=slinky.slurm.net,resources=clusterreferencegrants,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
@@ -94,6 +127,7 @@ func (r *NodeSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 
 	onceBackoffGC.Do(func() {
 		go wait.Until(failedPodsBackoff.GC, BackoffGCInterval, ctx.Done())
+		go wait.Until(safeModeBackoff.GC, BackoffGCInterval, ctx.Done())
 	})
 
 	startTime := time.Now()
@@ -109,12 +143,17 @@ func (r *NodeSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		}
 		// clean the duration store
 		_ = durationStore.Pop(req.String())
+		_ = scalingDurationStore.Pop(req.String())
 	}()
 
 	retErr = r.Sync(ctx, req)
 	res = reconcile.Result{
 		RequeueAfter: durationStore.Pop(req.String()),
 	}
+	if scalingRequeue := scalingDurationStore.Pop(req.String()); scalingRequeue > 0 &&
+		(res.RequeueAfter == 0 || scalingRequeue < res.RequeueAfter) {
+		res.RequeueAfter = scalingRequeue
+	}
 	if retErr != nil {
 		logger.Error(retErr, "encountered an error while reconciling request", "request", req)
 	}
@@ -128,18 +167,52 @@ func (r *NodeSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.podControl = podcontrol.NewPodControl(r.Client, r.eventRecorder)
 	r.slurmControl = slurmcontrol.NewSlurmControl(r.SlurmClusters)
 	r.expectations = kubecontroller.NewUIDTrackingControllerExpectations(kubecontroller.NewControllerExpectations())
+	r.deletedPodQueue = newDeletedPodQueue()
 	podEventHandler := &podEventHandler{
-		Reader:       mgr.GetCache(),
-		expectations: r.expectations,
+		Reader:          mgr.GetCache(),
+		expectations:    r.expectations,
+		deletedPodQueue: r.deletedPodQueue,
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, podNodeNameField, indexPodNodeName); err != nil {
+		return err
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("nodeset-controller").
 		For(&slinkyv1alpha1.NodeSet{}).
 		Owns(&corev1.Pod{}).
 		Watches(&corev1.Pod{}, podEventHandler).
+		Watches(&slinkyv1alpha1.ClusterReferenceGrant{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForGrants)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForNode),
+			builder.WithPredicates(nodeMaintenancePredicate)).
 		WatchesRawSource(source.Channel(r.EventCh, podEventHandler)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
 		}).
 		Complete(r)
 }
+
+// enqueueRequestsForGrants queues the NodeSets that reference a Cluster in the
+// namespace of the grant, so they notice when their reference is granted or
+// revoked.
+func (r *NodeSetReconciler) enqueueRequestsForGrants(
+	ctx context.Context,
+	o client.Object,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	nodesetList := &slinkyv1alpha1.NodeSetList{}
+	if err := r.List(ctx, nodesetList); err != nil {
+		logger.Error(err, "failed to list NodeSets")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, nodeset := range nodesetList.Items {
+		if nodeset.GetNamespace() == o.GetNamespace() || nodeset.ClusterKey().Namespace != o.GetNamespace() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&nodeset),
+		})
+	}
+	return requests
+}
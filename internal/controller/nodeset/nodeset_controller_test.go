@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -33,6 +34,12 @@ import (
 func newFakeClientList(interceptorFuncs interceptor.Funcs, initObjLists ...object.ObjectList) slurmclient.Client {
 	updateFn := func(_ context.Context, obj object.Object, req any, opts ...slurmclient.UpdateOption) error {
 		switch o := obj.(type) {
+		case *slurmtypes.V0041JobInfo:
+			r, ok := req.(v0041.V0041JobDescMsg)
+			if !ok {
+				return errors.New("failed to cast request object")
+			}
+			o.Requeue = r.Requeue
 		case *slurmtypes.V0041Node:
 			r, ok := req.(v0041.V0041UpdateNodeMsg)
 			if !ok {
@@ -44,6 +51,11 @@ func newFakeClientList(interceptorFuncs interceptor.Funcs, initObjLists ...objec
 				switch stateReq {
 				case v0041.V0041UpdateNodeMsgStateUNDRAIN:
 					stateSet.Delete(v0041.V0041NodeStateDRAIN)
+				case v0041.V0041UpdateNodeMsgStateRESUME:
+					stateSet.Delete(v0041.V0041NodeStateDOWN, v0041.V0041NodeStateDRAIN)
+					stateSet.Insert(v0041.V0041NodeStateIDLE)
+				case v0041.V0041UpdateNodeMsgStateREBOOTCANCELED:
+					stateSet.Delete(v0041.V0041NodeStateREBOOTREQUESTED)
 				default:
 					stateSet.Insert(v0041.V0041NodeState(stateReq))
 				}
@@ -51,6 +63,9 @@ func newFakeClientList(interceptorFuncs interceptor.Funcs, initObjLists ...objec
 			o.State = ptr.To(stateSet.UnsortedList())
 			o.Comment = r.Comment
 			o.Reason = r.Reason
+			if r.Weight != nil {
+				o.Weight = r.Weight.Number
+			}
 		default:
 			return errors.New("failed to cast slurm object")
 		}
@@ -183,14 +198,35 @@ var _ = Describe("Nodeset controller", func() {
 				Expect(k8sClient.Status().Update(ctx, &pod)).To(Succeed())
 			}
 
+			By("Reading the NodeSet scale subresource")
+			// The HPA reads the current replicas and pod selector from the scale
+			// subresource, which must follow status.replicas and status.selector.
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, nodesetLookupKey, createdNodeset)).To(Succeed())
+				g.Expect(createdNodeset.Status.Replicas).Should(Equal(int32(replicas)))
+				scale := &autoscalingv1.Scale{}
+				g.Expect(k8sClient.SubResource("scale").Get(ctx, createdNodeset, scale)).To(Succeed())
+				g.Expect(scale.Spec.Replicas).Should(Equal(int32(replicas)))
+				g.Expect(scale.Status.Replicas).Should(Equal(createdNodeset.Status.Replicas))
+				g.Expect(scale.Status.Selector).Should(Equal(createdNodeset.Status.Selector))
+				g.Expect(scale.Status.Selector).Should(Equal("foo=bar"))
+			}, timeout, interval).Should(Succeed())
+
 			By("NodeSet scale down")
 
-			// Scale down a NodeSet to verify pods are deleted and
-			// Slurm nodes are drained and deleted
+			// Scale down a NodeSet through the scale subresource, like the HPA,
+			// to verify pods are deleted and Slurm nodes are drained and deleted
 			Eventually(func(g Gomega) {
 				g.Expect(k8sClient.Get(ctx, nodesetLookupKey, createdNodeset)).To(Succeed())
-				createdNodeset.Spec.Replicas = ptr.To[int32](0)
-				g.Expect(k8sClient.Update(ctx, createdNodeset)).To(Succeed())
+				scale := &autoscalingv1.Scale{
+					Spec: autoscalingv1.ScaleSpec{
+						Replicas: 0,
+					},
+				}
+				g.Expect(k8sClient.SubResource("scale").Update(ctx, createdNodeset,
+					k8sclient.WithSubResourceBody(scale))).To(Succeed())
+				g.Expect(k8sClient.Get(ctx, nodesetLookupKey, createdNodeset)).To(Succeed())
+				g.Expect(ptr.Deref(createdNodeset.Spec.Replicas, -1)).Should(Equal(int32(0)))
 			}, timeout, interval).Should(Succeed())
 
 			// Verify the Slurm nodes are marked as NodeStateDRAIN
@@ -8,10 +8,10 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -21,18 +21,18 @@ import (
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
-	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
-
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/podinfo"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
 )
 
 var _ handler.EventHandler = &podEventHandler{}
@@ -40,6 +40,14 @@ var _ handler.EventHandler = &podEventHandler{}
 type podEventHandler struct {
 	client.Reader
 	expectations *kubecontroller.UIDTrackingControllerExpectations
+
+	// deletedPodQueue holds the deleted pods whose Slurm nodes are handled by
+	// the reconcile.
+	deletedPodQueue *deletedPodQueue
+
+	// deletedPods are the UIDs of the pods whose deletion was handled, until
+	// they are removed.
+	deletedPods sync.Map
 }
 
 func enqueueNodeSet(q workqueue.TypedRateLimitingInterface[reconcile.Request], nodeset *slinkyv1alpha1.NodeSet) {
@@ -212,6 +220,9 @@ func (e *podEventHandler) Delete(
 	q workqueue.TypedRateLimitingInterface[reconcile.Request],
 ) {
 	e.deletePod(ctx, evt.Object, q)
+	if pod, ok := evt.Object.(*corev1.Pod); ok {
+		e.deletedPods.Delete(pod.UID)
+	}
 }
 
 // When a pod is deleted, enqueue the replica nodeset that manages the pod and update its expectations.
@@ -257,6 +268,13 @@ func (e *podEventHandler) deletePod(
 	}
 	logger.V(4).Info("Pod deleted", "delete_by", utilruntime.GetCaller(), "deletion_timestamp", pod.DeletionTimestamp, "pod", klog.KObj(pod))
 	e.expectations.DeletionObserved(logger, nodesetKey, kubecontroller.PodKey(pod))
+	// The drain finalizer holds the pod back until its Slurm node is drained,
+	// so its jobs are only handled once the pod is released.
+	if !controllerutil.ContainsFinalizer(pod, slinkyv1alpha1.FinalizerPodDrain) {
+		if _, handled := e.deletedPods.LoadOrStore(pod.UID, struct{}{}); !handled && e.deletedPodQueue != nil {
+			e.deletedPodQueue.Add(nodesetKey, pod)
+		}
+	}
 	enqueueNodeSet(q, nodeset)
 }
 
@@ -336,41 +354,41 @@ func (e *podEventHandler) getPodNodeSets(ctx context.Context, pod *corev1.Pod) [
 
 // SetEventHandler is a helper function to make slurm node updates propagate to
 // the nodeset controller via configured event channel.
-func SetEventHandler(client slurmclient.Client, eventCh chan event.GenericEvent) {
-	informer := client.GetInformer(slurmtypes.ObjectTypeV0041Node)
+func SetEventHandler(slurmAPI slurmapi.Interface, eventCh chan event.GenericEvent) {
+	informer := slurmAPI.Client().GetInformer(slurmAPI.NodeObjectType())
 	informer.SetEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			node, ok := obj.(*slurmtypes.V0041Node)
+			node, ok := slurmAPI.ToNode(obj)
 			if !ok {
 				return
 			}
 			podInfo := podinfo.PodInfo{}
-			_ = podinfo.ParseIntoPodInfo(node.Comment, &podInfo)
+			_ = podinfo.ParseIntoPodInfo(ptr.To(node.Comment), &podInfo)
 			eventCh <- podEvent(podInfo)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			oldNode, ok := oldObj.(*slurmtypes.V0041Node)
+			oldNode, ok := slurmAPI.ToNode(oldObj)
 			if !ok {
 				return
 			}
-			newNode, ok := newObj.(*slurmtypes.V0041Node)
+			newNode, ok := slurmAPI.ToNode(newObj)
 			if !ok {
 				return
 			}
-			if apiequality.Semantic.DeepEqual(newNode.State, oldNode.State) {
+			if newNode.State.Equal(oldNode.State) && newNode.Reason == oldNode.Reason {
 				return
 			}
 			podInfo := podinfo.PodInfo{}
-			_ = podinfo.ParseIntoPodInfo(newNode.Comment, &podInfo)
+			_ = podinfo.ParseIntoPodInfo(ptr.To(newNode.Comment), &podInfo)
 			eventCh <- podEvent(podInfo)
 		},
 		DeleteFunc: func(obj interface{}) {
-			node, ok := obj.(*slurmtypes.V0041Node)
+			node, ok := slurmAPI.ToNode(obj)
 			if !ok {
 				return
 			}
 			podInfo := podinfo.PodInfo{}
-			_ = podinfo.ParseIntoPodInfo(node.Comment, &podInfo)
+			_ = podinfo.ParseIntoPodInfo(ptr.To(node.Comment), &podInfo)
 			eventCh <- podEvent(podInfo)
 		},
 	})
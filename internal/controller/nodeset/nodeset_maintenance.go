@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+// maintenanceTaints are set on Kubernetes nodes about to be removed. They
+// drain the Slurm nodes even when the pods tolerate them.
+var maintenanceTaints = set.New(
+	// cluster-autoscaler
+	"ToBeDeletedByClusterAutoscaler",
+	// Karpenter
+	"karpenter.sh/disrupted",
+	"karpenter.sh/disruption",
+)
+
+// podNodeNameField indexes the pods by the Kubernetes node they are bound to.
+const podNodeNameField = "spec.nodeName"
+
+// indexPodNodeName returns the Kubernetes node of the pod, for podNodeNameField.
+func indexPodNodeName(o client.Object) []string {
+	pod, ok := o.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil
+	}
+	return []string{pod.Spec.NodeName}
+}
+
+// getNodeMaintenanceReason returns why the Slurm node of the pod should be
+// drained for the maintenance of its Kubernetes node, or an empty string.
+func (r *NodeSetReconciler) getNodeMaintenanceReason(
+	ctx context.Context,
+	pod *corev1.Pod,
+) (string, error) {
+	if pod.Spec.NodeName == "" {
+		return "", nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return nodeMaintenanceReason(node, pod), nil
+}
+
+// nodeMaintenanceReason returns why the Kubernetes node is under maintenance:
+// it is being deleted, is cordoned, has a maintenance taint, or has a NoExecute
+// taint that the pod does not tolerate. Other NoSchedule taints (e.g.
+// `dedicated=gpu:NoSchedule`) only steer new pods, so they are ignored.
+func nodeMaintenanceReason(node *corev1.Node, pod *corev1.Pod) string {
+	switch {
+	case node.DeletionTimestamp != nil:
+		return fmt.Sprintf("Node (%s) is being deleted", node.Name)
+	case node.Spec.Unschedulable:
+		return fmt.Sprintf("Node (%s) is cordoned", node.Name)
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		isTolerated := slices.ContainsFunc(pod.Spec.Tolerations, func(toleration corev1.Toleration) bool {
+			return toleration.ToleratesTaint(&taint)
+		})
+		isEvicting := taint.Effect == corev1.TaintEffectNoExecute && !isTolerated
+		if maintenanceTaints.Has(taint.Key) || isEvicting {
+			return fmt.Sprintf("Node (%s) has taint %s", node.Name, taint.ToString())
+		}
+	}
+
+	return ""
+}
+
+// nodeMaintenancePredicate passes the Kubernetes node updates that may start
+// or end its maintenance.
+var nodeMaintenancePredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+			(oldNode.DeletionTimestamp == nil) != (newNode.DeletionTimestamp == nil) ||
+			!apiequality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints)
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// enqueueRequestsForNode queues the NodeSets with pods on the Kubernetes node,
+// so their Slurm nodes follow its maintenance.
+func (r *NodeSetReconciler) enqueueRequestsForNode(
+	ctx context.Context,
+	o client.Object,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.MatchingFields{podNodeNameField: o.GetName()}); err != nil {
+		logger.Error(err, "failed to list Pods")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, pod := range podList.Items {
+		controllerRef := metav1.GetControllerOf(&pod)
+		if controllerRef == nil || controllerRef.Kind != slinkyv1alpha1.NodeSetKind {
+			continue
+		}
+		request := reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: controllerRef.Name},
+		}
+		if !slices.Contains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+)
+
+func newKubeNode(name string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func Test_nodeMaintenanceReason(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}
+	tests := []struct {
+		name        string
+		node        func(node *corev1.Node)
+		tolerations []corev1.Toleration
+		want        string
+	}{
+		{
+			name: "Healthy",
+			want: "",
+		},
+		{
+			name: "Cordoned",
+			node: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			},
+			want: "Node (node-0) is cordoned",
+		},
+		{
+			name: "Deleted",
+			node: func(node *corev1.Node) {
+				node.DeletionTimestamp = ptr.To(metav1.Now())
+				node.Finalizers = []string{"karpenter.sh/termination"}
+			},
+			want: "Node (node-0) is being deleted",
+		},
+		{
+			name: "Untolerated taint",
+			node: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{
+					{Key: "maintenance", Value: "kernel", Effect: corev1.TaintEffectNoExecute},
+				}
+			},
+			want: "Node (node-0) has taint maintenance=kernel:NoExecute",
+		},
+		{
+			name: "Untolerated NoSchedule taint",
+			node: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{
+					{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				}
+			},
+			want: "",
+		},
+		{
+			name: "Tolerated taint",
+			node: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{gpuTaint}
+			},
+			tolerations: []corev1.Toleration{
+				{Key: gpuTaint.Key, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+			want: "",
+		},
+		{
+			name: "PreferNoSchedule taint",
+			node: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{
+					{Key: "DeletionCandidateOfClusterAutoscaler", Effect: corev1.TaintEffectPreferNoSchedule},
+				}
+			},
+			want: "",
+		},
+		{
+			name: "Cluster autoscaler scale-down",
+			node: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{
+					{Key: "ToBeDeletedByClusterAutoscaler", Value: "1700000000", Effect: corev1.TaintEffectNoSchedule},
+				}
+			},
+			tolerations: []corev1.Toleration{
+				{Operator: corev1.TolerationOpExists},
+			},
+			want: "Node (node-0) has taint ToBeDeletedByClusterAutoscaler=1700000000:NoSchedule",
+		},
+		{
+			name: "Karpenter disruption",
+			node: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{
+					{Key: "karpenter.sh/disrupted", Effect: corev1.TaintEffectNoSchedule},
+				}
+			},
+			tolerations: []corev1.Toleration{
+				{Operator: corev1.TolerationOpExists},
+			},
+			want: "Node (node-0) has taint karpenter.sh/disrupted:NoSchedule",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newKubeNode("node-0")
+			if tt.node != nil {
+				tt.node(node)
+			}
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					NodeName:    node.Name,
+					Tolerations: tt.tolerations,
+				},
+			}
+			if got := nodeMaintenanceReason(node, pod); got != tt.want {
+				t.Errorf("nodeMaintenanceReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nodeMaintenancePredicate(t *testing.T) {
+	tests := []struct {
+		name    string
+		updated func(node *corev1.Node)
+		want    bool
+	}{
+		{
+			name: "Status update",
+			updated: func(node *corev1.Node) {
+				node.Status.Phase = corev1.NodeRunning
+			},
+			want: false,
+		},
+		{
+			name: "Cordoned",
+			updated: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			},
+			want: true,
+		},
+		{
+			name: "Tainted",
+			updated: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}}
+			},
+			want: true,
+		},
+		{
+			name: "Deleted",
+			updated: func(node *corev1.Node) {
+				node.DeletionTimestamp = ptr.To(metav1.Now())
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldNode := newKubeNode("node-0")
+			newNode := oldNode.DeepCopy()
+			tt.updated(newNode)
+			if got := nodeMaintenancePredicate.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode}); got != tt.want {
+				t.Errorf("nodeMaintenancePredicate.Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_enqueueRequestsForNode(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	foo := newNodeSet("foo", "slurm", 2)
+	bar := newNodeSet("bar", "slurm", 1)
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(foo, 0, ""),
+		nodesetutils.NewNodeSetPod(foo, 1, ""),
+		nodesetutils.NewNodeSetPod(bar, 0, ""),
+		{ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "other"}},
+	}
+	pods[0].Spec.NodeName = "node-0"
+	pods[1].Spec.NodeName = "node-0"
+	pods[2].Spec.NodeName = "node-1"
+	pods[3].Spec.NodeName = "node-0"
+	builder := fake.NewClientBuilder().WithIndex(&corev1.Pod{}, podNodeNameField, indexPodNodeName)
+	for _, pod := range pods {
+		builder.WithObjects(pod)
+	}
+	r := newNodeSetController(builder.Build(), resources.NewClusters())
+
+	got := r.enqueueRequestsForNode(context.TODO(), newKubeNode("node-0"))
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: corev1.NamespaceDefault, Name: "foo"}},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("enqueueRequestsForNode() = %v, want %v", got, want)
+	}
+}
+
+func TestNodeSetReconciler_syncSlurm_maintenance(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name       string
+		cordoned   bool
+		nodeState  []v0041.V0041NodeState
+		nodeReason string
+		wantDrain  bool
+		wantReason string
+	}{
+		{
+			name:       "Cordoned",
+			cordoned:   true,
+			nodeState:  []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+			wantDrain:  true,
+			wantReason: "slurm-operator: Node (node-0) is cordoned",
+		},
+		{
+			name:       "Uncordoned",
+			cordoned:   false,
+			nodeState:  []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN},
+			nodeReason: "slurm-operator: Node (node-0) is cordoned",
+			wantDrain:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 1)
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+			pod.Spec.NodeName = "node-0"
+			node := newKubeNode("node-0")
+			node.Spec.Unschedulable = tt.cordoned
+
+			slurmNode := newNodeSetPodSlurmNode(pod)
+			slurmNode.State = ptr.To(tt.nodeState)
+			if tt.nodeReason != "" {
+				slurmNode.Reason = ptr.To(tt.nodeReason)
+			}
+			nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*slurmNode}}
+			slurmClient := newFakeClientList(interceptor.Funcs{}, nodeList)
+			c := fake.NewClientBuilder().WithObjects(pod.DeepCopy(), node).Build()
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+			if err := r.syncSlurm(context.TODO(), nodeset, []*corev1.Pod{pod}); err != nil {
+				t.Fatalf("syncSlurm() error = %v", err)
+			}
+			// Pods that are kept are uncordoned and undrained on scale-in.
+			if err := r.makePodUncordonAndUndrain(context.TODO(), nodeset, pod); err != nil {
+				t.Fatalf("makePodUncordonAndUndrain() error = %v", err)
+			}
+
+			gotNode := &slurmtypes.V0041Node{}
+			if err := slurmClient.Get(context.TODO(), slurmNode.GetKey(), gotNode); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if isDrain := slices.Contains(ptr.Deref(gotNode.State, nil), v0041.V0041NodeStateDRAIN); isDrain != tt.wantDrain {
+				t.Errorf("syncSlurm() node state = %v, want DRAIN %v", ptr.Deref(gotNode.State, nil), tt.wantDrain)
+			}
+			if tt.wantDrain && ptr.Deref(gotNode.Reason, "") != tt.wantReason {
+				t.Errorf("syncSlurm() node reason = %q, want %q", ptr.Deref(gotNode.Reason, ""), tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/slurmcontrol"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+const (
+	// nodeRebootRequeue is how often a pod pending reboot is checked for its
+	// Slurm node to drain.
+	nodeRebootRequeue = 30 * time.Second
+)
+
+// syncNodeRequests honors the reboots and power downs requested on the Slurm
+// nodes of the NodeSet pods (e.g. `scontrol reboot`, `scontrol power down`).
+// A reboot recreates the pod with the same ordinal once its Slurm node is
+// drained. A power down scales-in the NodeSet, condemning that pod first. The
+// request is left to slurmctld, which drops it once the Slurm node of the
+// condemned pod is deleted, so the ordinal can be scaled-out again.
+func (r *NodeSetReconciler) syncNodeRequests(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+
+	if isSafeMode(nodeset) {
+		logger.Info("Slurm cluster is unreachable, skipping Slurm node reboot and power down requests",
+			"nodeset", klog.KObj(nodeset))
+		return nil
+	}
+
+	nodeRequests, err := r.slurmControl.GetNodeRequests(ctx, nodeset, pods)
+	if err != nil {
+		return err
+	}
+
+	var powerDownPods []string
+	for i, pod := range pods {
+		if utils.IsTerminating(pod) {
+			continue
+		}
+		switch nodeRequests[nodesetutils.GetNodeName(pod)] {
+		case slurmcontrol.NodeRequestReboot:
+			if err := r.rebootPod(ctx, nodeset, pod); err != nil {
+				return err
+			}
+		case slurmcontrol.NodeRequestPowerDown:
+			// Slurm power saving already decides which pods run.
+			if nodesetutils.IsPowerSave(nodeset) || utils.IsPodSlurmPowerDown(pod) {
+				continue
+			}
+			// The pods are marked before the NodeSet is scaled-in, so it is
+			// never scaled-in twice for the same pod.
+			toUpdate, err := r.makePodSlurmPowerDown(ctx, pod)
+			if err != nil {
+				return err
+			}
+			// The scale-in of this sync condemns the marked pods.
+			pods[i] = toUpdate
+			powerDownPods = append(powerDownPods, pod.Name)
+		}
+	}
+	if len(powerDownPods) == 0 {
+		return nil
+	}
+
+	current := ptr.Deref(nodeset.Spec.Replicas, 1)
+	replicas := max(current-int32(len(powerDownPods)), 0)
+	// Patch a copy, the response would replace the status computed so far.
+	toUpdate := nodeset.DeepCopy()
+	toUpdate.Spec.Replicas = ptr.To(replicas)
+	if err := r.Patch(ctx, toUpdate, client.MergeFrom(nodeset)); err != nil {
+		return err
+	}
+	nodeset.Spec.Replicas = toUpdate.Spec.Replicas
+	nodeset.ResourceVersion = toUpdate.ResourceVersion
+	logger.Info("Scaled-in NodeSet for Slurm node power down", "nodeset", klog.KObj(nodeset),
+		"from", current, "to", replicas, "pods", powerDownPods)
+	r.eventRecorder.Eventf(nodeset, corev1.EventTypeNormal, PowerDownReason,
+		"Scaled from %d to %d replicas, a power down was requested on the Slurm nodes of pods %v",
+		current, replicas, powerDownPods)
+
+	return nil
+}
+
+// rebootPod drains the Slurm node of the pod, then deletes the pod once drained
+// so it is recreated with the same ordinal. The Slurm node is set DOWN by the
+// controller before its reboot request is cleared, so it stays out of use until
+// the new pod runs.
+func (r *NodeSetReconciler) rebootPod(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pod *corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	reason := fmt.Sprintf("Pod (%s) is pending reboot", klog.KObj(pod))
+	if err := r.slurmControl.MakeNodeDrain(ctx, nodeset, pod, reason); err != nil {
+		return err
+	}
+	isDrained, err := r.slurmControl.IsNodeDrained(ctx, nodeset, pod)
+	if err != nil {
+		return err
+	}
+	if !isDrained {
+		logger.V(2).Info("NodeSet Pod is draining, pending reboot",
+			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
+		durationStore.Push(key, nodeRebootRequeue)
+		return nil
+	}
+
+	reason = fmt.Sprintf("Pod (%s) is rebooted", klog.KObj(pod))
+	if err := r.slurmControl.MakeNodeDown(ctx, nodeset, pod, reason); err != nil {
+		return err
+	}
+	if err := r.slurmControl.CancelNodeReboot(ctx, nodeset, pod); err != nil {
+		return err
+	}
+	if err := r.podControl.DeleteNodeSetPod(ctx, nodeset, pod); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	logger.Info("Rebooted NodeSet Pod, a reboot was requested on its Slurm node",
+		"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
+	r.eventRecorder.Eventf(nodeset, corev1.EventTypeNormal, RebootReason,
+		"Pod %s was recreated, a reboot was requested on its Slurm node", pod.Name)
+
+	return nil
+}
+
+// makePodSlurmPowerDown marks the pod as powered down in Slurm, returning the
+// updated pod.
+func (r *NodeSetReconciler) makePodSlurmPowerDown(ctx context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+	toUpdate := pod.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	toUpdate.Annotations[slinkyv1alpha1.AnnotationPodSlurmPowerDown] = time.Now().Format(time.RFC3339)
+	if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
+		return nil, err
+	}
+	return toUpdate, nil
+}
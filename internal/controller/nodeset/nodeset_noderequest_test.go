@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func TestNodeSetReconciler_syncNodeRequests(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name         string
+		powerSave    bool
+		powerDown    bool
+		nodeState    []v0041.V0041NodeState
+		nodeReason   string
+		wantDeleted  bool
+		wantRequeue  bool
+		wantReplicas int32
+		wantNode     []v0041.V0041NodeState
+	}{
+		{
+			name:         "No request",
+			nodeState:    []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+			wantReplicas: 2,
+			wantNode:     []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+		},
+		{
+			name:         "Reboot pending drain",
+			nodeState:    []v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED, v0041.V0041NodeStateREBOOTREQUESTED},
+			wantRequeue:  true,
+			wantReplicas: 2,
+			wantNode: []v0041.V0041NodeState{
+				v0041.V0041NodeStateALLOCATED, v0041.V0041NodeStateDRAIN, v0041.V0041NodeStateREBOOTREQUESTED,
+			},
+		},
+		{
+			name:         "Reboot ASAP drained",
+			nodeState:    []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN, v0041.V0041NodeStateREBOOTREQUESTED},
+			nodeReason:   "Reboot ASAP",
+			wantDeleted:  true,
+			wantReplicas: 2,
+			wantNode: []v0041.V0041NodeState{
+				v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDOWN, v0041.V0041NodeStateDRAIN,
+			},
+		},
+		{
+			name:         "Power down",
+			nodeState:    []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERDOWN},
+			wantReplicas: 1,
+			wantNode:     []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERDOWN},
+		},
+		{
+			name:         "Power down already scaled-in",
+			powerDown:    true,
+			nodeState:    []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERINGDOWN},
+			wantReplicas: 2,
+			wantNode:     []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERINGDOWN},
+		},
+		{
+			name:         "Power down with power saving",
+			powerSave:    true,
+			nodeState:    []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERDOWN},
+			wantReplicas: 2,
+			wantNode:     []v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERDOWN},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 2)
+			if tt.powerSave {
+				nodeset.Spec.PowerSave = &slinkyv1alpha1.NodeSetPowerSave{Enabled: true}
+			}
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+			if tt.powerDown {
+				pod.Annotations[slinkyv1alpha1.AnnotationPodSlurmPowerDown] = "2025-01-01T00:00:00Z"
+			}
+			pods := []*corev1.Pod{pod}
+
+			slurmNode := newNodeSetPodSlurmNode(pod)
+			slurmNode.State = ptr.To(tt.nodeState)
+			slurmNode.Reason = ptr.To(tt.nodeReason)
+			nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*slurmNode}}
+			slurmClient := newFakeClientList(interceptor.Funcs{}, nodeList)
+			c := fake.NewClientBuilder().WithObjects(nodeset.DeepCopy(), pod.DeepCopy()).Build()
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+			// The status computed earlier in the sync is not stored yet.
+			apimeta.SetStatusCondition(&nodeset.Status.Conditions, metav1.Condition{
+				Type:   slinkyv1alpha1.NodeSetSlurmUnreachable,
+				Status: metav1.ConditionFalse,
+				Reason: "Reachable",
+			})
+
+			if err := r.syncNodeRequests(context.TODO(), nodeset, pods); err != nil {
+				t.Fatalf("syncNodeRequests() error = %v", err)
+			}
+			if requeue := durationStore.Pop(utils.KeyFunc(nodeset)) > 0; requeue != tt.wantRequeue {
+				t.Errorf("syncNodeRequests() requeue = %v, want %v", requeue, tt.wantRequeue)
+			}
+
+			gotNodeSet := &slinkyv1alpha1.NodeSet{}
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(nodeset), gotNodeSet); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got := ptr.Deref(gotNodeSet.Spec.Replicas, 0); got != tt.wantReplicas {
+				t.Errorf("syncNodeRequests() replicas = %v, want %v", got, tt.wantReplicas)
+			}
+			if got := ptr.Deref(nodeset.Spec.Replicas, 0); got != tt.wantReplicas {
+				t.Errorf("syncNodeRequests() synced replicas = %v, want %v", got, tt.wantReplicas)
+			}
+			if apimeta.FindStatusCondition(nodeset.Status.Conditions, slinkyv1alpha1.NodeSetSlurmUnreachable) == nil {
+				t.Errorf("syncNodeRequests() Status.Conditions = %v, want %s kept",
+					nodeset.Status.Conditions, slinkyv1alpha1.NodeSetSlurmUnreachable)
+			}
+
+			gotNode := &slurmtypes.V0041Node{}
+			if err := slurmClient.Get(context.TODO(), slurmNode.GetKey(), gotNode); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			gotState := slices.Clone(ptr.Deref(gotNode.State, nil))
+			slices.Sort(gotState)
+			wantState := slices.Clone(tt.wantNode)
+			slices.Sort(wantState)
+			if !slices.Equal(gotState, wantState) {
+				t.Errorf("syncNodeRequests() node state = %v, want %v", gotState, wantState)
+			}
+
+			gotPod := &corev1.Pod{}
+			err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), gotPod)
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if deleted := apierrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Fatalf("syncNodeRequests() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantDeleted {
+				return
+			}
+			wantPowerDown := tt.powerDown || tt.wantReplicas < 2
+			if got := utils.IsPodSlurmPowerDown(gotPod); got != wantPowerDown {
+				t.Errorf("syncNodeRequests() pod power down = %v, want %v", got, wantPowerDown)
+			}
+			if got := utils.IsPodSlurmPowerDown(pods[0]); got != wantPowerDown {
+				t.Errorf("syncNodeRequests() synced pod power down = %v, want %v", got, wantPowerDown)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_syncNodeRequests_powerDown(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 2)
+	pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+
+	slurmNode := newNodeSetPodSlurmNode(pod)
+	slurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERDOWN})
+	nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*slurmNode}}
+	var nodeUpdates []any
+	slurmClient := newFakeClientList(interceptor.Funcs{
+		Update: func(_ context.Context, _ object.Object, req any, _ ...slurmclient.UpdateOption) error {
+			nodeUpdates = append(nodeUpdates, req)
+			return nil
+		},
+	}, nodeList)
+	c := fake.NewClientBuilder().WithObjects(nodeset.DeepCopy(), pod.DeepCopy()).Build()
+	r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+	// The pod is condemned, then synced again while it drains.
+	pods := []*corev1.Pod{pod}
+	for range 2 {
+		if err := r.syncNodeRequests(context.TODO(), nodeset, pods); err != nil {
+			t.Fatalf("syncNodeRequests() error = %v", err)
+		}
+	}
+	if got := ptr.Deref(nodeset.Spec.Replicas, 0); got != 1 {
+		t.Fatalf("syncNodeRequests() replicas = %v, want %v", got, 1)
+	}
+	if len(nodeUpdates) != 0 {
+		t.Errorf("syncNodeRequests() Slurm node updates = %v, want none before the pod is deleted", nodeUpdates)
+	}
+
+	// Deleting the pod deletes its Slurm node, dropping the power down request.
+	// The NodeSet is then scaled-out again and recreates the same ordinal.
+	if err := c.Delete(context.TODO(), pod); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := slurmClient.Delete(context.TODO(), slurmNode); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	toUpdate := nodeset.DeepCopy()
+	toUpdate.Spec.Replicas = ptr.To[int32](2)
+	if err := c.Patch(context.TODO(), toUpdate, client.MergeFrom(nodeset)); err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	nodeset = toUpdate
+	newPod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+	if err := c.Create(context.TODO(), newPod.DeepCopy()); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	newSlurmNode := newNodeSetPodSlurmNode(newPod)
+	newSlurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE})
+	if err := slurmClient.Create(context.TODO(), newSlurmNode, nil); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := r.syncNodeRequests(context.TODO(), nodeset, []*corev1.Pod{newPod}); err != nil {
+		t.Fatalf("syncNodeRequests() error = %v", err)
+	}
+	gotNodeSet := &slinkyv1alpha1.NodeSet{}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(nodeset), gotNodeSet); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := ptr.Deref(gotNodeSet.Spec.Replicas, 0); got != 2 {
+		t.Errorf("syncNodeRequests() replicas = %v, want %v", got, 2)
+	}
+	gotPod := &corev1.Pod{}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(newPod), gotPod); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if utils.IsPodSlurmPowerDown(gotPod) {
+		t.Errorf("syncNodeRequests() pod power down = %v, want %v", true, false)
+	}
+	if len(nodeUpdates) != 0 {
+		t.Errorf("syncNodeRequests() Slurm node updates = %v, want none", nodeUpdates)
+	}
+}
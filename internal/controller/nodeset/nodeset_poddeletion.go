@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/slurmcontrol"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+const (
+	// podDeletionRequeue is how often a deleted pod is checked for its Slurm
+	// node to drain.
+	podDeletionRequeue = 30 * time.Second
+)
+
+// syncPodDeletion drains the Slurm nodes of the deleted pods that carry the
+// drain finalizer. A pod is released once its node is drained, or its drain
+// timeout passed.
+func (r *NodeSetReconciler) syncPodDeletion(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+	now := time.Now()
+
+	syncPodDeletionFn := func(i int) error {
+		pod := pods[i]
+		if !utils.IsTerminating(pod) || !controllerutil.ContainsFinalizer(pod, slinkyv1alpha1.FinalizerPodDrain) {
+			return nil
+		}
+
+		// Once the drain finalizer is disabled, or the containers are gone,
+		// there is nothing left to wait for.
+		if !nodesetutils.HasDrainFinalizer(nodeset) || utils.IsFailed(pod) || utils.IsSucceeded(pod) {
+			return r.removePodDrainFinalizer(ctx, pod)
+		}
+
+		if err := r.makePodCordonAndDrain(ctx, nodeset, pod); err != nil {
+			return err
+		}
+		isDrained, err := r.slurmControl.IsNodeDrained(ctx, nodeset, pod)
+		if err != nil {
+			return err
+		}
+		if isDrained {
+			return r.removePodDrainFinalizer(ctx, pod)
+		}
+
+		deadline := getPodDeletionDeadline(nodeset, pod)
+		if !now.Before(deadline) {
+			logger.Info("NodeSet Pod did not drain within the drain timeout, releasing it",
+				"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod), "deadline", deadline)
+			r.eventRecorder.Eventf(nodeset, corev1.EventTypeWarning, DrainTimeoutReason,
+				"Pod %s was deleted and did not drain by %s, releasing it", pod.Name, deadline.Format(time.RFC3339))
+			return r.removePodDrainFinalizer(ctx, pod)
+		}
+
+		logger.V(2).Info("NodeSet Pod is draining, pending its deletion",
+			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
+		durationStore.Push(key, min(podDeletionRequeue, deadline.Sub(now)))
+		return nil
+	}
+	if _, err := utils.SlowStartBatch(len(pods), utils.SlowStartInitialBatchSize, syncPodDeletionFn); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getPodDeletionDeadline returns when the drain finalizer releases the deleted
+// pod. The deletion timestamp of a pod is set to the end of its termination
+// grace period, which the drain timeout replaces.
+func getPodDeletionDeadline(nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) time.Time {
+	deadline := pod.DeletionTimestamp.Time
+	if nodeset.Spec.PodDeletion == nil || nodeset.Spec.PodDeletion.DrainTimeout == nil {
+		return deadline
+	}
+	gracePeriod := time.Duration(ptr.Deref(pod.DeletionGracePeriodSeconds, 0)) * time.Second
+	return deadline.Add(-gracePeriod).Add(nodeset.Spec.PodDeletion.DrainTimeout.Duration)
+}
+
+// removePodDrainFinalizer releases the pod for removal.
+func (r *NodeSetReconciler) removePodDrainFinalizer(ctx context.Context, pod *corev1.Pod) error {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(pod, slinkyv1alpha1.FinalizerPodDrain) {
+		return nil
+	}
+
+	toUpdate := pod.DeepCopy()
+	logger.Info("Remove drain finalizer from Pod", "Pod", klog.KObj(toUpdate))
+	controllerutil.RemoveFinalizer(toUpdate, slinkyv1alpha1.FinalizerPodDrain)
+	if err := r.Patch(ctx, toUpdate, client.MergeFrom(pod)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	return nil
+}
+
+// releaseOrphanedPods removes the drain finalizer from the pods of a NodeSet
+// that was deleted, as nothing is left to drain their Slurm nodes.
+func (r *NodeSetReconciler) releaseOrphanedPods(ctx context.Context, nodesetKey types.NamespacedName) error {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(nodesetKey.Namespace)); err != nil {
+		return err
+	}
+
+	for _, pod := range utils.ReferenceList(podList.Items) {
+		controllerRef := metav1.GetControllerOf(pod)
+		if controllerRef == nil || controllerRef.Kind != slinkyv1alpha1.NodeSetKind || controllerRef.Name != nodesetKey.Name {
+			continue
+		}
+		if err := r.removePodDrainFinalizer(ctx, pod); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deletedPodQueue holds the pods deleted outside of the controller, by NodeSet
+// key, until the reconcile of their NodeSet handles their Slurm nodes.
+type deletedPodQueue struct {
+	mu   sync.Mutex
+	pods map[string]map[types.UID]*corev1.Pod
+}
+
+func newDeletedPodQueue() *deletedPodQueue {
+	return &deletedPodQueue{
+		pods: make(map[string]map[types.UID]*corev1.Pod),
+	}
+}
+
+// Add queues the deleted pods of the NodeSet.
+func (q *deletedPodQueue) Add(key string, pods ...*corev1.Pod) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pods[key] == nil {
+		q.pods[key] = make(map[types.UID]*corev1.Pod)
+	}
+	for _, pod := range pods {
+		q.pods[key][pod.UID] = pod
+	}
+}
+
+// Pop removes and returns the deleted pods of the NodeSet.
+func (q *deletedPodQueue) Pop(key string) []*corev1.Pod {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pods := make([]*corev1.Pod, 0, len(q.pods[key]))
+	for _, pod := range q.pods[key] {
+		pods = append(pods, pod)
+	}
+	delete(q.pods, key)
+	return pods
+}
+
+// syncDeletedPods handles the Slurm nodes of the pods deleted outside of the
+// controller. The pods that could not be handled are queued again, and the
+// error requeues the NodeSet.
+func (r *NodeSetReconciler) syncDeletedPods(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+) error {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	if r.deletedPodQueue == nil {
+		return nil
+	}
+	if isSafeMode(nodeset) {
+		logger.V(2).Info("Slurm cluster is unreachable, deferring the Slurm nodes of deleted pods",
+			"nodeset", klog.KObj(nodeset))
+		return nil
+	}
+
+	pods := r.deletedPodQueue.Pop(key)
+	for i, pod := range pods {
+		if err := r.handleDeletedPod(ctx, nodeset, pod); err != nil {
+			r.deletedPodQueue.Add(key, pods[i:]...)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleDeletedPod sets the Slurm node of the deleted pod DOWN when it still
+// runs jobs, so slurmctld requeues or ends them right away instead of after
+// SlurmdTimeout. The controller only deletes pods once their Slurm node
+// drained, so such a pod was deleted outside of it (e.g. `kubectl delete`,
+// eviction, node loss).
+func (r *NodeSetReconciler) handleDeletedPod(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pod *corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+
+	jobIDs, err := r.slurmControl.GetNodeJobs(ctx, nodeset, pod)
+	if err != nil {
+		if errors.Is(err, slurmcontrol.ErrNoClient) {
+			return nil
+		}
+		return err
+	}
+	if len(jobIDs) == 0 {
+		return nil
+	}
+
+	if nodeset.Spec.PodDeletion != nil && nodeset.Spec.PodDeletion.RequeueJobs {
+		if err := r.slurmControl.MakeJobsRequeueable(ctx, nodeset, jobIDs); err != nil {
+			return err
+		}
+	}
+	reason := fmt.Sprintf("Pod (%s) was deleted while running jobs", klog.KObj(pod))
+	if err := r.slurmControl.MakeNodeDown(ctx, nodeset, pod, reason); err != nil {
+		return err
+	}
+
+	logger.Info("NodeSet Pod was deleted while running jobs, set its Slurm node DOWN",
+		"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod), "jobs", jobIDs)
+	r.eventRecorder.Eventf(nodeset, corev1.EventTypeWarning, PodDeletedWithJobsReason,
+		"Pod %s was deleted while running jobs %v, set its Slurm node DOWN", pod.Name, jobIDs)
+
+	return nil
+}
@@ -0,0 +1,378 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func TestNodeSetReconciler_syncPodDeletion(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	now := time.Now()
+	tests := []struct {
+		name           string
+		drainFinalizer bool
+		deletedAgo     time.Duration
+		podPhase       corev1.PodPhase
+		nodeState      []v0041.V0041NodeState
+		wantReleased   bool
+		wantRequeue    bool
+		wantNodeDrain  bool
+	}{
+		{
+			name:           "Not deleted",
+			drainFinalizer: true,
+			podPhase:       corev1.PodRunning,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED},
+			wantReleased:   false,
+			wantNodeDrain:  false,
+		},
+		{
+			name:           "Draining",
+			drainFinalizer: true,
+			deletedAgo:     time.Minute,
+			podPhase:       corev1.PodRunning,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED},
+			wantReleased:   false,
+			wantRequeue:    true,
+			wantNodeDrain:  true,
+		},
+		{
+			name:           "Drained",
+			drainFinalizer: true,
+			deletedAgo:     time.Minute,
+			podPhase:       corev1.PodRunning,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+			wantReleased:   true,
+			wantNodeDrain:  true,
+		},
+		{
+			name:           "Drain timeout",
+			drainFinalizer: true,
+			deletedAgo:     2 * time.Hour,
+			podPhase:       corev1.PodRunning,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED},
+			wantReleased:   true,
+			wantNodeDrain:  true,
+		},
+		{
+			name:           "Pod failed",
+			drainFinalizer: true,
+			deletedAgo:     time.Minute,
+			podPhase:       corev1.PodFailed,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED},
+			wantReleased:   true,
+			wantNodeDrain:  false,
+		},
+		{
+			name:           "Drain finalizer disabled",
+			drainFinalizer: false,
+			deletedAgo:     time.Minute,
+			podPhase:       corev1.PodRunning,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED},
+			wantReleased:   true,
+			wantNodeDrain:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 1)
+			nodeset.Spec.PodDeletion = &slinkyv1alpha1.NodeSetPodDeletion{
+				DrainFinalizer: true,
+				DrainTimeout:   &metav1.Duration{Duration: time.Hour},
+			}
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+			pod.Status.Phase = tt.podPhase
+			if tt.deletedAgo > 0 {
+				pod.DeletionTimestamp = ptr.To(metav1.NewTime(now.Add(-tt.deletedAgo)))
+				pod.DeletionGracePeriodSeconds = ptr.To[int64](0)
+			}
+			// The pod was created while the drain finalizer was enabled.
+			nodeset.Spec.PodDeletion.DrainFinalizer = tt.drainFinalizer
+
+			slurmNode := newNodeSetPodSlurmNode(pod)
+			slurmNode.State = ptr.To(tt.nodeState)
+			nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*slurmNode}}
+			slurmClient := newFakeClientList(interceptor.Funcs{}, nodeList)
+			c := fake.NewClientBuilder().WithObjects(nodeset.DeepCopy(), pod.DeepCopy()).Build()
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+			if err := r.syncPodDeletion(context.TODO(), nodeset, []*corev1.Pod{pod}); err != nil {
+				t.Fatalf("syncPodDeletion() error = %v", err)
+			}
+			if requeue := durationStore.Pop(utils.KeyFunc(nodeset)) > 0; requeue != tt.wantRequeue {
+				t.Errorf("syncPodDeletion() requeue = %v, want %v", requeue, tt.wantRequeue)
+			}
+
+			gotPod := &corev1.Pod{}
+			err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), gotPod)
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Fatalf("Get() error = %v", err)
+			}
+			released := apierrors.IsNotFound(err) || !controllerutil.ContainsFinalizer(gotPod, slinkyv1alpha1.FinalizerPodDrain)
+			if released != tt.wantReleased {
+				t.Errorf("syncPodDeletion() released = %v, want %v", released, tt.wantReleased)
+			}
+
+			gotNode := &slurmtypes.V0041Node{}
+			if err := slurmClient.Get(context.TODO(), object.ObjectKey(slurmNode.GetKey()), gotNode); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if isDrain := slices.Contains(ptr.Deref(gotNode.State, nil), v0041.V0041NodeStateDRAIN); isDrain != tt.wantNodeDrain {
+				t.Errorf("syncPodDeletion() node state = %v, want DRAIN %v", ptr.Deref(gotNode.State, nil), tt.wantNodeDrain)
+			}
+		})
+	}
+}
+
+func Test_getPodDeletionDeadline(t *testing.T) {
+	now := time.Now()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			DeletionTimestamp:          ptr.To(metav1.NewTime(now)),
+			DeletionGracePeriodSeconds: ptr.To[int64](30),
+		},
+	}
+	tests := []struct {
+		name        string
+		podDeletion *slinkyv1alpha1.NodeSetPodDeletion
+		want        time.Time
+	}{
+		{
+			name: "Termination grace period",
+			podDeletion: &slinkyv1alpha1.NodeSetPodDeletion{
+				DrainFinalizer: true,
+			},
+			want: now,
+		},
+		{
+			name: "Drain timeout",
+			podDeletion: &slinkyv1alpha1.NodeSetPodDeletion{
+				DrainFinalizer: true,
+				DrainTimeout:   &metav1.Duration{Duration: 10 * time.Minute},
+			},
+			want: now.Add(-30 * time.Second).Add(10 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", "slurm", 1)
+			nodeset.Spec.PodDeletion = tt.podDeletion
+			if got := getPodDeletionDeadline(nodeset, pod); !got.Equal(tt.want) {
+				t.Errorf("getPodDeletionDeadline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_releaseOrphanedPods(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	nodeset := newNodeSet("foo", "slurm", 2)
+	nodeset.Spec.PodDeletion = &slinkyv1alpha1.NodeSetPodDeletion{DrainFinalizer: true}
+	other := newNodeSet("bar", "slurm", 1)
+	other.UID = "bar"
+	other.Spec.PodDeletion = nodeset.Spec.PodDeletion
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+		nodesetutils.NewNodeSetPod(other, 0, ""),
+	}
+	builder := fake.NewClientBuilder()
+	for _, pod := range pods {
+		builder.WithObjects(pod.DeepCopy())
+	}
+	c := builder.Build()
+	r := newNodeSetController(c, resources.NewClusters())
+
+	if err := r.releaseOrphanedPods(context.TODO(), types.NamespacedName{Namespace: nodeset.Namespace, Name: nodeset.Name}); err != nil {
+		t.Fatalf("releaseOrphanedPods() error = %v", err)
+	}
+
+	want := map[string]bool{"foo-0": false, "foo-1": false, "bar-0": true}
+	for _, pod := range pods {
+		gotPod := &corev1.Pod{}
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), gotPod); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got := controllerutil.ContainsFinalizer(gotPod, slinkyv1alpha1.FinalizerPodDrain); got != want[pod.Name] {
+			t.Errorf("releaseOrphanedPods() pod %s has drain finalizer = %v, want %v", pod.Name, got, want[pod.Name])
+		}
+	}
+}
+
+func TestNodeSetReconciler_syncDeletedPods(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name        string
+		podDeletion *slinkyv1alpha1.NodeSetPodDeletion
+		runningJob  bool
+		updateErr   bool
+		wantDown    bool
+		wantRequeue bool
+		wantErr     bool
+	}{
+		{
+			name:       "Drained",
+			runningJob: false,
+			wantDown:   false,
+		},
+		{
+			name:       "Running jobs",
+			runningJob: true,
+			wantDown:   true,
+		},
+		{
+			name:        "Running jobs, requeue",
+			podDeletion: &slinkyv1alpha1.NodeSetPodDeletion{RequeueJobs: true},
+			runningJob:  true,
+			wantDown:    true,
+			wantRequeue: true,
+		},
+		{
+			name:        "Running jobs, drain finalizer",
+			podDeletion: &slinkyv1alpha1.NodeSetPodDeletion{DrainFinalizer: true},
+			runningJob:  true,
+			wantDown:    true,
+		},
+		{
+			name:       "Running jobs, update failed",
+			runningJob: true,
+			updateErr:  true,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 1)
+			nodeset.Spec.PodDeletion = tt.podDeletion
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+			pod.UID = "uid-0"
+			pod.ResourceVersion = "1"
+			deletedPod := pod.DeepCopy()
+			deletedPod.ResourceVersion = "2"
+			deletedPod.DeletionTimestamp = ptr.To(metav1.Now())
+			releasedPod := deletedPod.DeepCopy()
+			releasedPod.ResourceVersion = "3"
+			releasedPod.Finalizers = nil
+
+			slurmNode := newNodeSetPodSlurmNode(pod)
+			jobState := v0041.V0041JobInfoJobStateCOMPLETED
+			if tt.runningJob {
+				slurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED})
+				jobState = v0041.V0041JobInfoJobStateRUNNING
+			}
+			nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*slurmNode}}
+			jobList := &slurmtypes.V0041JobInfoList{
+				Items: []slurmtypes.V0041JobInfo{
+					{
+						V0041JobInfo: v0041.V0041JobInfo{
+							JobId:    ptr.To[int32](1),
+							JobState: ptr.To([]v0041.V0041JobInfoJobState{jobState}),
+							Nodes:    ptr.To(nodesetutils.GetNodeName(pod)),
+						},
+					},
+				},
+			}
+			interceptorFuncs := interceptor.Funcs{}
+			if tt.updateErr {
+				interceptorFuncs.Update = func(ctx context.Context, obj object.Object, req any, opts ...slurmclient.UpdateOption) error {
+					return errors.New(http.StatusText(http.StatusBadGateway))
+				}
+			}
+			slurmClient := newFakeClientList(interceptorFuncs, nodeList, jobList)
+			c := fake.NewClientBuilder().WithObjects(nodeset).Build()
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+			eventRecorder := record.NewFakeRecorder(10)
+			r.eventRecorder = eventRecorder
+			h := &podEventHandler{
+				Reader:          c,
+				expectations:    r.expectations,
+				deletedPodQueue: r.deletedPodQueue,
+			}
+
+			// A graceful deletion is seen as an update, then as a delete once
+			// the finalizers are removed.
+			q := newQueue()
+			h.Update(context.TODO(), event.UpdateEvent{ObjectOld: pod, ObjectNew: deletedPod}, q)
+			if err := r.syncDeletedPods(context.TODO(), nodeset); err != nil && !tt.wantErr {
+				t.Fatalf("syncDeletedPods() error = %v", err)
+			}
+
+			gotNode := &slurmtypes.V0041Node{}
+			if err := slurmClient.Get(context.TODO(), slurmNode.GetKey(), gotNode); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if isDown := slices.Contains(ptr.Deref(gotNode.State, nil), v0041.V0041NodeStateDOWN); isDown && len(deletedPod.Finalizers) > 0 {
+				t.Errorf("syncDeletedPods() node state = %v before the drain finalizer was removed", ptr.Deref(gotNode.State, nil))
+			}
+
+			h.Delete(context.TODO(), event.DeleteEvent{Object: releasedPod}, q)
+			// The event handler does not call slurmrestd itself.
+			if err := slurmClient.Get(context.TODO(), slurmNode.GetKey(), gotNode); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if isDown := slices.Contains(ptr.Deref(gotNode.State, nil), v0041.V0041NodeStateDOWN); isDown && !tt.wantDown {
+				t.Errorf("Delete() node state = %v, want not DOWN", ptr.Deref(gotNode.State, nil))
+			}
+			err := r.syncDeletedPods(context.TODO(), nodeset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("syncDeletedPods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if pending := len(r.deletedPodQueue.Pop(utils.KeyFunc(nodeset))) > 0; pending != tt.wantErr {
+				t.Errorf("syncDeletedPods() pending = %v, want %v", pending, tt.wantErr)
+			}
+			if err := slurmClient.Get(context.TODO(), slurmNode.GetKey(), gotNode); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if isDown := slices.Contains(ptr.Deref(gotNode.State, nil), v0041.V0041NodeStateDOWN); isDown != tt.wantDown {
+				t.Errorf("syncDeletedPods() node state = %v, want DOWN %v", ptr.Deref(gotNode.State, nil), tt.wantDown)
+			}
+			gotJob := &slurmtypes.V0041JobInfo{}
+			if err := slurmClient.Get(context.TODO(), jobList.Items[0].GetKey(), gotJob); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if requeue := ptr.Deref(gotJob.Requeue, false); requeue != tt.wantRequeue {
+				t.Errorf("syncDeletedPods() job requeue = %v, want %v", requeue, tt.wantRequeue)
+			}
+			wantEvents := 0
+			if tt.wantDown {
+				wantEvents = 1
+			}
+			if got := len(eventRecorder.Events); got != wantEvents {
+				t.Errorf("syncDeletedPods() events = %v, want %v", got, wantEvents)
+			}
+		})
+	}
+}
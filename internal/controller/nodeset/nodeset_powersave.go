@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+// syncPowerSave will reconcile NodeSet pods with the Slurm nodes that power
+// saving has resumed. Pods are created for resumed nodes and deleted for
+// suspended nodes, once their Slurm node is drained like on scale-in.
+func (r *NodeSetReconciler) syncPowerSave(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+	hash string,
+) error {
+	logger := log.FromContext(ctx)
+
+	replicaCount := int(ptr.Deref(nodeset.Spec.Replicas, 0))
+	resumed := nodesetutils.GetResumedOrdinals(nodeset)
+
+	existing := set.New[int]()
+	podsToKeep := make([]*corev1.Pod, 0, len(pods))
+	podsToDelete := make([]*corev1.Pod, 0)
+	for _, pod := range pods {
+		ordinal := nodesetutils.GetOrdinal(pod)
+		if resumed.Has(ordinal) && ordinal < replicaCount {
+			existing.Insert(ordinal)
+			podsToKeep = append(podsToKeep, pod)
+		} else {
+			podsToDelete = append(podsToDelete, pod)
+		}
+	}
+
+	podsToCreate := make([]*corev1.Pod, 0)
+	for _, ordinal := range resumed.Difference(existing).SortedList() {
+		if ordinal >= replicaCount || len(podsToCreate) >= burstReplicas {
+			break
+		}
+		podsToCreate = append(podsToCreate, nodesetutils.NewNodeSetPod(nodeset, ordinal, hash))
+	}
+
+	if len(podsToCreate) > 0 {
+		logger.V(2).Info("Resuming NodeSet pods", "nodeset", klog.KObj(nodeset),
+			"creating", len(podsToCreate))
+		return r.createNodeSetPods(ctx, nodeset, podsToCreate)
+	}
+	if len(podsToDelete) > 0 {
+		logger.V(2).Info("Suspending NodeSet pods", "nodeset", klog.KObj(nodeset),
+			"deleting", len(podsToDelete))
+		return r.doPodPowerDown(ctx, nodeset, podsToDelete)
+	}
+	logger.V(2).Info("Processing NodeSet pods", "nodeset", klog.KObj(nodeset),
+		"resumed", len(podsToKeep))
+	return r.doPodProcessing(ctx, nodeset, podsToKeep, hash)
+}
+
+// doPodPowerDown deletes the NodeSet pods of suspended Slurm nodes. Their Slurm
+// nodes are drained first, and pods are only deleted once drained.
+func (r *NodeSetReconciler) doPodPowerDown(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	podsToDelete []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	if isSafeMode(nodeset) {
+		logger.Info("Slurm cluster is unreachable, skipping NodeSet pod deletion",
+			"nodeset", klog.KObj(nodeset), "pods", len(podsToDelete))
+		return nil
+	}
+
+	numDelete := utils.Clamp(len(podsToDelete), 0, burstReplicas)
+	podsToDelete = podsToDelete[:numDelete]
+
+	if err := r.syncDrainTimeouts(ctx, nodeset, podsToDelete); err != nil {
+		return err
+	}
+
+	if err := r.expectations.ExpectDeletions(logger, key, getPodKeys(podsToDelete)); err != nil {
+		return err
+	}
+	_, err := utils.SlowStartBatch(numDelete, utils.SlowStartInitialBatchSize, func(index int) error {
+		pod := podsToDelete[index]
+		podKey := kubecontroller.PodKey(pod)
+		if err := r.processCondemned(ctx, nodeset, podsToDelete, index); err != nil {
+			// Decrement the expected number of deletes because the informer won't observe this deletion
+			r.expectations.DeletionObserved(logger, key, podKey)
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		if isDrained, err := r.slurmControl.IsNodeDrained(ctx, nodeset, pod); !isDrained || err != nil {
+			// Decrement expectations and requeue reconcile because the Slurm node is not drained yet.
+			r.expectations.DeletionObserved(logger, key, podKey)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return err
+}
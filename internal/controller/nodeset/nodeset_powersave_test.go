@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func TestNodeSetReconciler_syncPowerSave(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name        string
+		replicas    int32
+		resumed     set.Set[int]
+		ordinals    []int
+		drained     bool
+		want        []string
+		wantCordons []string
+	}{
+		{
+			name:     "Resume creates pods",
+			replicas: 4,
+			resumed:  set.New(0, 2),
+			ordinals: []int{0},
+			want:     []string{"foo-0", "foo-2"},
+		},
+		{
+			name:     "Suspend deletes drained pods",
+			replicas: 4,
+			resumed:  set.New(0),
+			ordinals: []int{0, 1},
+			drained:  true,
+			want:     []string{"foo-0"},
+		},
+		{
+			name:        "Suspend drains pods first",
+			replicas:    4,
+			resumed:     set.New(0),
+			ordinals:    []int{0, 1},
+			want:        []string{"foo-0", "foo-1"},
+			wantCordons: []string{"foo-1"},
+		},
+		{
+			name:     "Resume is bounded by replicas",
+			replicas: 2,
+			resumed:  set.New(1, 3),
+			ordinals: []int{},
+			want:     []string{"foo-1"},
+		},
+		{
+			name:     "Nothing resumed",
+			replicas: 2,
+			resumed:  set.New[int](),
+			ordinals: []int{0, 1},
+			drained:  true,
+			want:     []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, tt.replicas)
+			nodeset.Spec.PowerSave = &slinkyv1alpha1.NodeSetPowerSave{Enabled: true}
+			nodesetutils.SetResumedOrdinals(nodeset, tt.resumed)
+
+			pods := make([]*corev1.Pod, 0, len(tt.ordinals))
+			objs := make([]client.Object, 0, len(tt.ordinals))
+			nodeList := &slurmtypes.V0041NodeList{}
+			for _, ordinal := range tt.ordinals {
+				pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, ordinal, ""))
+				pods = append(pods, pod)
+				objs = append(objs, pod)
+				slurmNode := newNodeSetPodSlurmNode(pod)
+				if tt.drained {
+					slurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN})
+				}
+				nodeList.Items = append(nodeList.Items, *slurmNode)
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			slurmClient := newFakeClientList(interceptor.Funcs{}, nodeList)
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+			if err := r.syncPowerSave(context.TODO(), nodeset, pods, ""); err != nil {
+				t.Fatalf("NodeSetReconciler.syncPowerSave() error = %v", err)
+			}
+
+			podList := &corev1.PodList{}
+			if err := c.List(context.TODO(), podList); err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			got := make([]string, 0, len(podList.Items))
+			var gotCordons []string
+			for _, pod := range podList.Items {
+				got = append(got, pod.Name)
+				if utils.IsPodCordon(&pod) {
+					gotCordons = append(gotCordons, pod.Name)
+				}
+			}
+			slices.Sort(got)
+			slices.Sort(gotCordons)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NodeSetReconciler.syncPowerSave() pods = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(gotCordons, tt.wantCordons) {
+				t.Errorf("NodeSetReconciler.syncPowerSave() cordons = %v, want %v", gotCordons, tt.wantCordons)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/slurmcontrol"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+const (
+	// slurmNodeReadyRequeue is how often a running pod is checked for its Slurm
+	// node to become ready.
+	slurmNodeReadyRequeue = 30 * time.Second
+)
+
+// syncPodConditions reflects the state of the Slurm nodes onto the conditions
+// of the NodeSet pods, including their Slurm node readiness gate.
+func (r *NodeSetReconciler) syncPodConditions(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	nodeConditions, err := r.slurmControl.GetNodeConditions(ctx, nodeset, pods)
+	if err != nil {
+		if errors.Is(err, slurmcontrol.ErrNoClient) {
+			// The readiness of the Slurm nodes is unknown, keep the conditions.
+			return nil
+		}
+		return err
+	}
+
+	syncPodConditionsFn := func(i int) error {
+		pod := pods[i]
+		if utils.IsTerminating(pod) {
+			return nil
+		}
+
+		toUpdate := pod.DeepCopy()
+		isChanged := false
+		for _, condition := range nodeConditions[nodesetutils.GetNodeName(pod)] {
+			if podutil.UpdatePodCondition(&toUpdate.Status, &condition) {
+				isChanged = true
+			}
+		}
+
+		// The Slurm node may register after the pod containers are ready,
+		// before the pod info is set on the Slurm node to map its events back.
+		_, readyCondition := podutil.GetPodCondition(&toUpdate.Status, slinkyv1alpha1.PodConditionSlurmNodeReady)
+		if utils.IsRunningAndContainersReady(pod) && readyCondition != nil && readyCondition.Status != corev1.ConditionTrue {
+			logger.V(2).Info("NodeSet Pod is running, pending its Slurm node to be ready",
+				"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod), "message", readyCondition.Message)
+			durationStore.Push(key, slurmNodeReadyRequeue)
+		}
+
+		if !isChanged {
+			return nil
+		}
+		if err := r.Status().Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		return nil
+	}
+	if _, err := utils.SlowStartBatch(len(pods), utils.SlowStartInitialBatchSize, syncPodConditionsFn); err != nil {
+		return err
+	}
+
+	return nil
+}
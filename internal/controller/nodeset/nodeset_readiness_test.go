@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func TestNodeSetReconciler_syncPodConditions(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name        string
+		noClient    bool
+		nodeState   []v0041.V0041NodeState
+		wantStatus  corev1.ConditionStatus
+		wantRequeue bool
+	}{
+		{
+			name:        "Slurm node ready",
+			nodeState:   []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+			wantStatus:  corev1.ConditionTrue,
+			wantRequeue: false,
+		},
+		{
+			name:        "Slurm node not ready",
+			nodeState:   []v0041.V0041NodeState{v0041.V0041NodeStateDOWN, v0041.V0041NodeStateINVALIDREG},
+			wantStatus:  corev1.ConditionFalse,
+			wantRequeue: true,
+		},
+		{
+			name:        "Slurm node not registered",
+			wantStatus:  corev1.ConditionFalse,
+			wantRequeue: true,
+		},
+		{
+			name:        "No client",
+			noClient:    true,
+			wantStatus:  "",
+			wantRequeue: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 1)
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+
+			nodeList := &slurmtypes.V0041NodeList{}
+			if tt.nodeState != nil {
+				slurmNode := newNodeSetPodSlurmNode(pod)
+				slurmNode.State = ptr.To(tt.nodeState)
+				nodeList.Items = append(nodeList.Items, *slurmNode)
+			}
+			clusters := newSlurmClusters(clusterName, newFakeClientList(interceptor.Funcs{}, nodeList))
+			if tt.noClient {
+				clusters = resources.NewClusters()
+			}
+			c := fake.NewClientBuilder().
+				WithObjects(pod.DeepCopy()).
+				WithStatusSubresource(&corev1.Pod{}).
+				Build()
+			r := newNodeSetController(c, clusters)
+
+			if err := r.syncPodConditions(context.TODO(), nodeset, []*corev1.Pod{pod}); err != nil {
+				t.Fatalf("syncPodConditions() error = %v", err)
+			}
+			if requeue := durationStore.Pop(utils.KeyFunc(nodeset)) > 0; requeue != tt.wantRequeue {
+				t.Errorf("syncPodConditions() requeue = %v, want %v", requeue, tt.wantRequeue)
+			}
+
+			gotPod := &corev1.Pod{}
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), gotPod); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			var gotStatus corev1.ConditionStatus
+			if _, condition := podutil.GetPodCondition(&gotPod.Status, slinkyv1alpha1.PodConditionSlurmNodeReady); condition != nil {
+				gotStatus = condition.Status
+			}
+			if gotStatus != tt.wantStatus {
+				t.Errorf("syncPodConditions() %s = %q, want %q",
+					slinkyv1alpha1.PodConditionSlurmNodeReady, gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+const (
+	// defaultRemediationGracePeriod is how long a Slurm node must stay
+	// unhealthy before its pod is recreated, unless set on the NodeSet.
+	defaultRemediationGracePeriod = 5 * time.Minute
+	// defaultMaxRemediations is the number of pods that may be recreated
+	// within the window, unless set on the NodeSet.
+	defaultMaxRemediations = 1
+	// defaultRemediationWindow is the period over which remediations are
+	// counted, unless set on the NodeSet.
+	defaultRemediationWindow = 10 * time.Minute
+	// maxRemediationRecords is the minimum number of remediations kept in the
+	// NodeSet status.
+	maxRemediationRecords = 10
+)
+
+// syncRemediation recreates the pods whose Slurm node stayed DOWN, FAIL, or
+// NOT_RESPONDING for the grace period. The Slurm node is set DOWN by the
+// controller first, so it is resumed once the new pod runs. Each remediation is
+// recorded as an event and in the NodeSet status.
+func (r *NodeSetReconciler) syncRemediation(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	if nodeset.Spec.Remediation == nil {
+		return nil
+	}
+	if isSafeMode(nodeset) {
+		logger.Info("Slurm cluster is unreachable, skipping NodeSet pod remediation",
+			"nodeset", klog.KObj(nodeset))
+		return nil
+	}
+
+	unhealthyNodes, err := r.slurmControl.GetUnhealthyNodes(ctx, nodeset, pods)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	gracePeriod := getRemediationGracePeriod(nodeset)
+	var podsToRemediate []*corev1.Pod
+	for _, pod := range pods {
+		if utils.IsTerminating(pod) || utils.IsPodCordon(pod) || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		unhealthyTime, _ := utils.GetTimeFromAnnotations(pod.Annotations, slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime)
+		if _, ok := unhealthyNodes[nodesetutils.GetNodeName(pod)]; !ok {
+			if err := r.setPodUnhealthyTime(ctx, pod, time.Time{}); err != nil {
+				return err
+			}
+			continue
+		}
+		if unhealthyTime.IsZero() {
+			unhealthyTime = now
+			if err := r.setPodUnhealthyTime(ctx, pod, unhealthyTime); err != nil {
+				return err
+			}
+		}
+		if hasRemediation(nodeset, pod, unhealthyTime) {
+			continue
+		}
+		if deadline := unhealthyTime.Add(gracePeriod); now.Before(deadline) {
+			durationStore.Push(key, deadline.Sub(now))
+			continue
+		}
+		podsToRemediate = append(podsToRemediate, pod)
+	}
+	if len(podsToRemediate) == 0 {
+		return nil
+	}
+
+	// Remediate the pods that have been unhealthy the longest first.
+	slices.SortStableFunc(podsToRemediate, func(a, b *corev1.Pod) int {
+		aTime, _ := utils.GetTimeFromAnnotations(a.Annotations, slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime)
+		bTime, _ := utils.GetTimeFromAnnotations(b.Annotations, slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime)
+		return aTime.Compare(bTime)
+	})
+	remaining, _ := getRemediationBudget(nodeset, now)
+	numRemediate := utils.Clamp(remaining, 0, len(podsToRemediate))
+
+	for _, pod := range podsToRemediate[:numRemediate] {
+		slurmNode := unhealthyNodes[nodesetutils.GetNodeName(pod)]
+		reason := fmt.Sprintf("Pod (%s) is remediated", klog.KObj(pod))
+		if err := r.slurmControl.MakeNodeDown(ctx, nodeset, pod, reason); err != nil {
+			return err
+		}
+		if err := r.podControl.DeleteNodeSetPod(ctx, nodeset, pod); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+
+		logger.Info("Remediated NodeSet Pod, its Slurm node was unhealthy", "nodeset", klog.KObj(nodeset),
+			"pod", klog.KObj(pod), "state", slurmNode.State, "reason", slurmNode.Reason)
+		r.eventRecorder.Eventf(nodeset, corev1.EventTypeWarning, RemediationReason,
+			"Pod %s was recreated, its Slurm node was %s for %v: %s",
+			pod.Name, slurmNode.State, gracePeriod, slurmNode.Reason)
+		recordRemediation(nodeset, slinkyv1alpha1.NodeSetPodRemediation{
+			Pod:    pod.Name,
+			State:  slurmNode.State,
+			Reason: slurmNode.Reason,
+			Time:   metav1.NewTime(now),
+		})
+	}
+
+	if numRemediate < len(podsToRemediate) {
+		_, wait := getRemediationBudget(nodeset, now)
+		logger.Info("NodeSet remediation is rate limited, delaying pod remediation",
+			"nodeset", klog.KObj(nodeset), "pods", len(podsToRemediate)-numRemediate, "wait", wait)
+		durationStore.Push(key, wait)
+	}
+
+	return nil
+}
+
+// setPodUnhealthyTime sets when the Slurm node of the pod was first seen
+// unhealthy, or removes it when zero.
+func (r *NodeSetReconciler) setPodUnhealthyTime(ctx context.Context, pod *corev1.Pod, unhealthyTime time.Time) error {
+	_, ok := pod.Annotations[slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime]
+	if unhealthyTime.IsZero() && !ok {
+		return nil
+	}
+
+	toUpdate := pod.DeepCopy()
+	if unhealthyTime.IsZero() {
+		delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime)
+	} else {
+		if toUpdate.Annotations == nil {
+			toUpdate.Annotations = make(map[string]string)
+		}
+		toUpdate.Annotations[slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime] = unhealthyTime.Format(time.RFC3339)
+	}
+	if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	return nil
+}
+
+// getRemediationGracePeriod returns the remediation grace period, defaulting to
+// defaultRemediationGracePeriod.
+func getRemediationGracePeriod(nodeset *slinkyv1alpha1.NodeSet) time.Duration {
+	if nodeset.Spec.Remediation == nil || nodeset.Spec.Remediation.GracePeriod == nil {
+		return defaultRemediationGracePeriod
+	}
+	return nodeset.Spec.Remediation.GracePeriod.Duration
+}
+
+// getRemediationBudget returns how many pods may still be remediated within
+// the window, and when the next one may be otherwise.
+func getRemediationBudget(nodeset *slinkyv1alpha1.NodeSet, now time.Time) (int, time.Duration) {
+	maxRemediations := defaultMaxRemediations
+	window := defaultRemediationWindow
+	if remediation := nodeset.Spec.Remediation; remediation != nil {
+		maxRemediations = int(ptr.Deref(remediation.MaxRemediations, defaultMaxRemediations))
+		if remediation.Window != nil {
+			window = remediation.Window.Duration
+		}
+	}
+
+	var recent []time.Time
+	if nodeset.Status.Remediation != nil {
+		for _, remediation := range nodeset.Status.Remediation.Remediations {
+			if now.Sub(remediation.Time.Time) < window {
+				recent = append(recent, remediation.Time.Time)
+			}
+		}
+	}
+	if len(recent) < maxRemediations {
+		return maxRemediations - len(recent), 0
+	}
+
+	// The oldest remediation within the window leaves it first.
+	slices.SortFunc(recent, time.Time.Compare)
+	return 0, recent[len(recent)-maxRemediations].Add(window).Sub(now)
+}
+
+// hasRemediation returns true when the pod was already remediated since its
+// Slurm node was seen unhealthy.
+func hasRemediation(nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, unhealthyTime time.Time) bool {
+	if nodeset.Status.Remediation == nil {
+		return false
+	}
+	return slices.ContainsFunc(nodeset.Status.Remediation.Remediations, func(remediation slinkyv1alpha1.NodeSetPodRemediation) bool {
+		return remediation.Pod == pod.Name && !remediation.Time.Time.Before(unhealthyTime)
+	})
+}
+
+// recordRemediation adds the remediation to the NodeSet status, keeping the
+// latest ones, and at least those counted by the rate limit.
+func recordRemediation(nodeset *slinkyv1alpha1.NodeSet, remediation slinkyv1alpha1.NodeSetPodRemediation) {
+	if nodeset.Status.Remediation == nil {
+		nodeset.Status.Remediation = &slinkyv1alpha1.NodeSetRemediationStatus{}
+	}
+	maxRecords := maxRemediationRecords
+	if nodeset.Spec.Remediation != nil {
+		maxRecords = max(maxRecords, int(ptr.Deref(nodeset.Spec.Remediation.MaxRemediations, 0)))
+	}
+	remediations := append(nodeset.Status.Remediation.Remediations, remediation)
+	if len(remediations) > maxRecords {
+		remediations = remediations[len(remediations)-maxRecords:]
+	}
+	nodeset.Status.Remediation.Remediations = remediations
+}
@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func TestNodeSetReconciler_syncRemediation(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	now := time.Now()
+	tests := []struct {
+		name              string
+		unhealthyAgo      time.Duration
+		nodeState         []v0041.V0041NodeState
+		nodeReason        string
+		nodeReasonUser    string
+		remediations      []slinkyv1alpha1.NodeSetPodRemediation
+		wantUnhealthyTime bool
+		wantDeleted       bool
+		wantRequeue       bool
+	}{
+		{
+			name:              "Healthy",
+			unhealthyAgo:      time.Hour,
+			nodeState:         []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+			wantUnhealthyTime: false,
+			wantDeleted:       false,
+			wantRequeue:       false,
+		},
+		{
+			name:              "Newly unhealthy",
+			nodeState:         []v0041.V0041NodeState{v0041.V0041NodeStateDOWN, v0041.V0041NodeStateNOTRESPONDING},
+			nodeReason:        "Not responding",
+			nodeReasonUser:    "slurm",
+			wantUnhealthyTime: true,
+			wantDeleted:       false,
+			wantRequeue:       true,
+		},
+		{
+			name:           "Unhealthy past grace period",
+			unhealthyAgo:   time.Hour,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateDOWN, v0041.V0041NodeStateNOTRESPONDING},
+			nodeReason:     "Not responding",
+			nodeReasonUser: "slurm",
+			wantDeleted:    true,
+			wantRequeue:    false,
+		},
+		{
+			name:              "Drained by admin",
+			unhealthyAgo:      time.Hour,
+			nodeState:         []v0041.V0041NodeState{v0041.V0041NodeStateDOWN},
+			nodeReason:        "bad PSU",
+			nodeReasonUser:    "alice",
+			wantUnhealthyTime: false,
+			wantDeleted:       false,
+			wantRequeue:       false,
+		},
+		{
+			name:           "Rate limited",
+			unhealthyAgo:   time.Hour,
+			nodeState:      []v0041.V0041NodeState{v0041.V0041NodeStateDOWN, v0041.V0041NodeStateNOTRESPONDING},
+			nodeReason:     "Not responding",
+			nodeReasonUser: "slurm",
+			remediations: []slinkyv1alpha1.NodeSetPodRemediation{
+				{Pod: "foo-1", State: "DOWN", Time: metav1.NewTime(now.Add(-time.Minute))},
+			},
+			wantUnhealthyTime: true,
+			wantDeleted:       false,
+			wantRequeue:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 2)
+			nodeset.Spec.Remediation = &slinkyv1alpha1.NodeSetRemediation{
+				GracePeriod: &metav1.Duration{Duration: 5 * time.Minute},
+			}
+			if tt.remediations != nil {
+				nodeset.Status.Remediation = &slinkyv1alpha1.NodeSetRemediationStatus{
+					Remediations: tt.remediations,
+				}
+			}
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+			if tt.unhealthyAgo > 0 {
+				pod.Annotations[slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime] = now.Add(-tt.unhealthyAgo).Format(time.RFC3339)
+			}
+
+			slurmNode := newNodeSetPodSlurmNode(pod)
+			slurmNode.State = ptr.To(tt.nodeState)
+			slurmNode.Reason = ptr.To(tt.nodeReason)
+			slurmNode.ReasonSetByUser = ptr.To(tt.nodeReasonUser)
+			nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*slurmNode}}
+			slurmClient := newFakeClientList(interceptor.Funcs{}, nodeList)
+			c := fake.NewClientBuilder().WithObjects(pod.DeepCopy()).Build()
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+			if err := r.syncRemediation(context.TODO(), nodeset, []*corev1.Pod{pod}); err != nil {
+				t.Fatalf("syncRemediation() error = %v", err)
+			}
+			if requeue := durationStore.Pop(utils.KeyFunc(nodeset)) > 0; requeue != tt.wantRequeue {
+				t.Errorf("syncRemediation() requeue = %v, want %v", requeue, tt.wantRequeue)
+			}
+
+			gotPod := &corev1.Pod{}
+			err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), gotPod)
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if deleted := apierrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Fatalf("syncRemediation() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantDeleted {
+				gotNode := &slurmtypes.V0041Node{}
+				if err := slurmClient.Get(context.TODO(), slurmNode.GetKey(), gotNode); err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				if !slices.Contains(ptr.Deref(gotNode.State, nil), v0041.V0041NodeStateDOWN) ||
+					!strings.Contains(ptr.Deref(gotNode.Reason, ""), "is remediated") {
+					t.Errorf("syncRemediation() node state = %v, reason = %q, want DOWN by the controller",
+						ptr.Deref(gotNode.State, nil), ptr.Deref(gotNode.Reason, ""))
+				}
+				if nodeset.Status.Remediation == nil || len(nodeset.Status.Remediation.Remediations) != 1 {
+					t.Fatalf("syncRemediation() status = %v, want one remediation", nodeset.Status.Remediation)
+				}
+				got := nodeset.Status.Remediation.Remediations[0]
+				if got.Pod != pod.Name || got.State != "DOWN+NOT_RESPONDING" || got.Reason != tt.nodeReason {
+					t.Errorf("syncRemediation() remediation = %v", got)
+				}
+				return
+			}
+			if _, ok := gotPod.Annotations[slinkyv1alpha1.AnnotationPodSlurmUnhealthyTime]; ok != tt.wantUnhealthyTime {
+				t.Errorf("syncRemediation() has unhealthy time = %v, want %v", ok, tt.wantUnhealthyTime)
+			}
+		})
+	}
+}
+
+func Test_getRemediationBudget(t *testing.T) {
+	now := time.Now()
+	remediations := []slinkyv1alpha1.NodeSetPodRemediation{
+		{Pod: "foo-0", Time: metav1.NewTime(now.Add(-time.Hour))},
+		{Pod: "foo-1", Time: metav1.NewTime(now.Add(-8 * time.Minute))},
+		{Pod: "foo-2", Time: metav1.NewTime(now.Add(-2 * time.Minute))},
+	}
+	tests := []struct {
+		name          string
+		remediation   *slinkyv1alpha1.NodeSetRemediation
+		remediations  []slinkyv1alpha1.NodeSetPodRemediation
+		wantRemaining int
+		wantWait      time.Duration
+	}{
+		{
+			name:          "No remediations",
+			remediation:   &slinkyv1alpha1.NodeSetRemediation{},
+			wantRemaining: 1,
+		},
+		{
+			name:          "Defaults",
+			remediation:   &slinkyv1alpha1.NodeSetRemediation{},
+			remediations:  remediations,
+			wantRemaining: 0,
+			wantWait:      8 * time.Minute,
+		},
+		{
+			name: "Budget left",
+			remediation: &slinkyv1alpha1.NodeSetRemediation{
+				MaxRemediations: ptr.To[int32](3),
+			},
+			remediations:  remediations,
+			wantRemaining: 1,
+		},
+		{
+			name: "Window",
+			remediation: &slinkyv1alpha1.NodeSetRemediation{
+				MaxRemediations: ptr.To[int32](2),
+				Window:          &metav1.Duration{Duration: 30 * time.Minute},
+			},
+			remediations:  remediations,
+			wantRemaining: 0,
+			wantWait:      22 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", "slurm", 3)
+			nodeset.Spec.Remediation = tt.remediation
+			nodeset.Status.Remediation = &slinkyv1alpha1.NodeSetRemediationStatus{Remediations: tt.remediations}
+			gotRemaining, gotWait := getRemediationBudget(nodeset, now)
+			if gotRemaining != tt.wantRemaining {
+				t.Errorf("getRemediationBudget() remaining = %v, want %v", gotRemaining, tt.wantRemaining)
+			}
+			if gotWait != tt.wantWait {
+				t.Errorf("getRemediationBudget() wait = %v, want %v", gotWait, tt.wantWait)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+// syncSafeMode pings the Slurm cluster of the NodeSet and records the result
+// in the SlurmUnreachable condition. While the cluster is unreachable, Slurm
+// nodes cannot be drained, so pod deletion for scale-in and rolling updates is
+// paused and the NodeSet is requeued with backoff.
+func (r *NodeSetReconciler) syncSafeMode(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+) {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	err := r.slurmControl.PingController(ctx, nodeset)
+	switch {
+	case err == nil:
+		safeModeBackoff.Reset(key)
+		setCondition(nodeset, slinkyv1alpha1.NodeSetSlurmUnreachable, metav1.ConditionFalse,
+			SlurmReachableReason, fmt.Sprintf("Slurm cluster %s is reachable", nodeset.ClusterKey()))
+	case isIgnoreSlurmUnreachable(nodeset):
+		safeModeBackoff.Reset(key)
+		setCondition(nodeset, slinkyv1alpha1.NodeSetSlurmUnreachable, metav1.ConditionTrue,
+			SafeModeIgnoredReason, fmt.Sprintf("Slurm cluster %s is unreachable, pods are deleted without draining: %v",
+				nodeset.ClusterKey(), err))
+	default:
+		logger.Info("Slurm cluster is unreachable, pausing scale-in and rolling updates",
+			"nodeset", klog.KObj(nodeset), "cluster", nodeset.ClusterKey(), "error", err)
+		setCondition(nodeset, slinkyv1alpha1.NodeSetSlurmUnreachable, metav1.ConditionTrue,
+			SafeModeReason, fmt.Sprintf("Scale-in and rolling updates are paused until Slurm cluster %s is reachable: %v",
+				nodeset.ClusterKey(), err))
+		safeModeBackoff.Next(key, time.Now())
+		durationStore.Push(key, safeModeBackoff.Get(key))
+	}
+}
+
+// isSafeMode returns true when pod deletion must be paused because the Slurm
+// cluster of the NodeSet is unreachable.
+func isSafeMode(nodeset *slinkyv1alpha1.NodeSet) bool {
+	condition := apimeta.FindStatusCondition(nodeset.Status.Conditions, slinkyv1alpha1.NodeSetSlurmUnreachable)
+	return condition != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.Reason == SafeModeReason
+}
+
+// isIgnoreSlurmUnreachable returns true when the NodeSet opted out of safe mode.
+func isIgnoreSlurmUnreachable(nodeset *slinkyv1alpha1.NodeSet) bool {
+	return nodeset.GetAnnotations()[slinkyv1alpha1.AnnotationIgnoreSlurmUnreachable] == "true"
+}
+
+// setCondition updates a condition on the NodeSet status, only bumping the
+// transition time when the condition status changes.
+func setCondition(
+	nodeset *slinkyv1alpha1.NodeSet,
+	conditionType string,
+	conditionStatus metav1.ConditionStatus,
+	reason, message string,
+) {
+	apimeta.SetStatusCondition(&nodeset.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: nodeset.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurminterceptor "github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func TestNodeSetReconciler_syncSafeMode(t *testing.T) {
+	const clusterName = "slurm"
+	pingList := &slurmtypes.V0041ControllerPingList{
+		Items: []slurmtypes.V0041ControllerPing{
+			{
+				V0041ControllerPing: v0041.V0041ControllerPing{
+					Hostname: ptr.To("slurmctld-0"),
+					Pinged:   ptr.To(slurmtypes.V0041ControllerPingPingedUP),
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		slurmClusters *resources.Clusters
+		annotations   map[string]string
+		wantStatus    metav1.ConditionStatus
+		wantReason    string
+		wantSafeMode  bool
+		wantRequeue   bool
+	}{
+		{
+			name:          "Reachable",
+			slurmClusters: newSlurmClusters(clusterName, newFakeClientList(slurminterceptor.Funcs{}, pingList)),
+			wantStatus:    metav1.ConditionFalse,
+			wantReason:    SlurmReachableReason,
+		},
+		{
+			name:          "No client",
+			slurmClusters: resources.NewClusters(),
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    SafeModeReason,
+			wantSafeMode:  true,
+			wantRequeue:   true,
+		},
+		{
+			name:          "Controller down",
+			slurmClusters: newSlurmClusters(clusterName, newFakeClientList(slurminterceptor.Funcs{})),
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    SafeModeReason,
+			wantSafeMode:  true,
+			wantRequeue:   true,
+		},
+		{
+			name:          "Opted out",
+			slurmClusters: resources.NewClusters(),
+			annotations: map[string]string{
+				slinkyv1alpha1.AnnotationIgnoreSlurmUnreachable: "true",
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: SafeModeIgnoredReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 1)
+			nodeset.Annotations = tt.annotations
+			key := utils.KeyFunc(nodeset)
+			defer durationStore.Pop(key)
+
+			r := newNodeSetController(fake.NewFakeClient(), tt.slurmClusters)
+			r.syncSafeMode(context.TODO(), nodeset)
+
+			condition := apimeta.FindStatusCondition(nodeset.Status.Conditions, slinkyv1alpha1.NodeSetSlurmUnreachable)
+			if condition == nil {
+				t.Fatalf("SlurmUnreachable condition not set")
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("SlurmUnreachable = %v/%v, want %v/%v",
+					condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if got := isSafeMode(nodeset); got != tt.wantSafeMode {
+				t.Errorf("isSafeMode() = %v, want %v", got, tt.wantSafeMode)
+			}
+			if got := durationStore.Pop(key) > 0; got != tt.wantRequeue {
+				t.Errorf("requeue = %v, want %v", got, tt.wantRequeue)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_doPodScaleIn_safeMode(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name        string
+		safeMode    bool
+		wantDeleted bool
+	}{
+		{
+			name:        "Safe mode keeps pods",
+			safeMode:    true,
+			wantDeleted: false,
+		},
+		{
+			name:        "Opted out deletes pods",
+			safeMode:    false,
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 0)
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+			c := fake.NewClientBuilder().WithObjects(nodeset, pod).Build()
+			r := newNodeSetController(c, resources.NewClusters())
+			if !tt.safeMode {
+				nodeset.Annotations = map[string]string{
+					slinkyv1alpha1.AnnotationIgnoreSlurmUnreachable: "true",
+				}
+			}
+			r.syncSafeMode(context.TODO(), nodeset)
+			defer durationStore.Pop(utils.KeyFunc(nodeset))
+
+			if err := r.doPodScaleIn(context.TODO(), nodeset, []*corev1.Pod{pod}, nil); err != nil {
+				t.Fatalf("doPodScaleIn() error = %v", err)
+			}
+			err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+			if got := apierrors.IsNotFound(err); got != tt.wantDeleted {
+				t.Errorf("pod deleted = %v, want %v", got, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+// maxDrainTimeouts is the number of drain timeouts kept in the NodeSet status.
+const maxDrainTimeouts = 10
+
+// scaleInReplicas returns the replicas to reconcile the pods toward. A lower
+// replica count only condemns pods once it held for the stabilization window,
+// and a started scale-in is not reverted before the minimum drain duration.
+// The pending and the committed scale-in are recorded in the NodeSet status.
+func (r *NodeSetReconciler) scaleInReplicas(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) int {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+
+	desired := ptr.Deref(nodeset.Spec.Replicas, 0)
+	replicas, status, wait := calculateScaleIn(nodeset.Spec.ScaleIn, nodeset.Status.ScaleIn,
+		int32(len(pods)), desired, time.Now())
+	if wait > 0 {
+		scalingDurationStore.Push(key, wait)
+	}
+	if replicas != desired {
+		logger.V(2).Info("Holding NodeSet replicas for scale-in policy", "nodeset", klog.KObj(nodeset),
+			"replicas", desired, "holding", replicas, "wait", wait)
+	}
+	nodeset.Status.ScaleIn = status
+	return int(replicas)
+}
+
+// calculateScaleIn returns the replicas to reconcile toward from the current
+// pods and the desired replicas, the new scale-in status, and how long until
+// the decision may change.
+func calculateScaleIn(
+	scaleIn *slinkyv1alpha1.NodeSetScaleIn,
+	status *slinkyv1alpha1.NodeSetScaleInStatus,
+	current, desired int32,
+	now time.Time,
+) (int32, *slinkyv1alpha1.NodeSetScaleInStatus, time.Duration) {
+	if scaleIn == nil {
+		return desired, nil, 0
+	}
+	window := durationOrZero(scaleIn.StabilizationWindow)
+	minDrain := durationOrZero(scaleIn.MinDrainDuration)
+
+	newStatus := &slinkyv1alpha1.NodeSetScaleInStatus{}
+	if status != nil {
+		newStatus = status.DeepCopy()
+	}
+
+	// A committed scale-in ignores higher replicas until the minimum drain
+	// duration passed, then it only finishes down to the desired replicas.
+	var wait time.Duration
+	baseline := current
+	if newStatus.StartTime != nil {
+		replicas := ptr.Deref(newStatus.Replicas, 0)
+		if elapsed := now.Sub(newStatus.StartTime.Time); elapsed < minDrain {
+			wait = minDrain - elapsed
+		} else {
+			replicas = max(replicas, desired)
+		}
+		if wait == 0 && current <= replicas {
+			newStatus.Replicas = nil
+			newStatus.StartTime = nil
+		} else {
+			newStatus.Replicas = ptr.To(replicas)
+			baseline = replicas
+		}
+	}
+
+	if desired >= baseline {
+		newStatus.PendingReplicas = nil
+		newStatus.PendingSince = nil
+		if newStatus.StartTime != nil {
+			return baseline, newStatus, wait
+		}
+		if len(newStatus.DrainTimeouts) > 0 {
+			return desired, newStatus, 0
+		}
+		return desired, nil, 0
+	}
+
+	// The lower replica count must hold for the window. The highest replica
+	// count seen within the window wins.
+	if newStatus.PendingSince == nil || ptr.Deref(newStatus.PendingReplicas, 0) >= baseline {
+		newStatus.PendingReplicas = ptr.To(desired)
+		newStatus.PendingSince = ptr.To(metav1.NewTime(now))
+	} else {
+		newStatus.PendingReplicas = ptr.To(max(ptr.Deref(newStatus.PendingReplicas, 0), desired))
+	}
+	if elapsed := now.Sub(newStatus.PendingSince.Time); elapsed < window {
+		remaining := window - elapsed
+		if wait > 0 {
+			remaining = min(remaining, wait)
+		}
+		return baseline, newStatus, remaining
+	}
+
+	replicas := ptr.Deref(newStatus.PendingReplicas, 0)
+	newStatus.PendingReplicas = nil
+	newStatus.PendingSince = nil
+	newStatus.Replicas = ptr.To(replicas)
+	newStatus.StartTime = ptr.To(metav1.NewTime(now))
+	return replicas, newStatus, minDrain
+}
+
+// durationOrZero returns the duration, or zero when unset.
+func durationOrZero(d *metav1.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.Duration
+}
+
+// splitScaleInPods returns the pods to condemn and the pods to keep, ordered by
+// the scale-in policy from the state of their Slurm nodes. Without a policy,
+// or when the Slurm nodes cannot be read, pods are ordered as active pods.
+func (r *NodeSetReconciler) splitScaleInPods(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+	numDelete int,
+) (podsToDelete, podsToKeep []*corev1.Pod) {
+	logger := log.FromContext(ctx)
+
+	if nodeset.Spec.ScaleIn == nil || nodeset.Spec.ScaleIn.Policy == "" {
+		return nodesetutils.SplitActivePods(pods, numDelete)
+	}
+	policy := nodeset.Spec.ScaleIn.Policy
+
+	nodes, err := r.slurmControl.GetNodeScaleInInfo(ctx, nodeset, pods)
+	if err != nil {
+		logger.Error(err, "unable to get Slurm nodes for scale-in policy, falling back to default order",
+			"nodeset", klog.KObj(nodeset), "policy", policy)
+		return nodesetutils.SplitActivePods(pods, numDelete)
+	}
+
+	return nodesetutils.SplitScaleInPods(pods, numDelete, policy, nodes)
+}
+
+// syncNodeWeights sets the weight of the Slurm nodes from the scale-in order of
+// their pods, when managed. Slurm allocates the nodes with the lowest weight
+// first, so the pods condemned first get the highest weight. Only the weights
+// that differ from the Slurm nodes are updated. The weight a Slurm node had
+// before is recorded on its pod, and restored once no longer managed.
+func (r *NodeSetReconciler) syncNodeWeights(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) error {
+	nodeWeights, err := r.slurmControl.GetNodeWeights(ctx, nodeset, pods)
+	if err != nil {
+		return err
+	}
+
+	if nodeset.Spec.ScaleIn == nil || !nodeset.Spec.ScaleIn.ManageNodeWeight {
+		return r.restoreNodeWeights(ctx, nodeset, pods, nodeWeights)
+	}
+
+	for i, pod := range pods {
+		weight, ok := nodeWeights[nodesetutils.GetNodeName(pod)]
+		if _, recorded := pod.Annotations[slinkyv1alpha1.AnnotationPodSlurmWeight]; !ok || recorded {
+			continue
+		}
+		toUpdate := pod.DeepCopy()
+		if toUpdate.Annotations == nil {
+			toUpdate.Annotations = make(map[string]string)
+		}
+		toUpdate.Annotations[slinkyv1alpha1.AnnotationPodSlurmWeight] = strconv.Itoa(int(weight))
+		if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		pods[i] = toUpdate
+	}
+
+	// Sort a copy, the caller's pods keep their order.
+	ordered, _ := r.splitScaleInPods(ctx, nodeset, slices.Clone(pods), len(pods))
+	weights := calculateNodeWeights(ordered)
+	maps.DeleteFunc(weights, func(nodeName string, weight int32) bool {
+		nodeWeight, ok := nodeWeights[nodeName]
+		return !ok || nodeWeight == weight
+	})
+	if len(weights) == 0 {
+		return nil
+	}
+	return r.slurmControl.SetNodeWeights(ctx, nodeset, weights)
+}
+
+// restoreNodeWeights sets the Slurm nodes back to the weight recorded on their
+// pods, then removes the record. Pods whose Slurm node is unknown keep theirs,
+// so the weight can still be restored once the Slurm cluster is reachable.
+func (r *NodeSetReconciler) restoreNodeWeights(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+	nodeWeights map[string]int32,
+) error {
+	restored := make([]int, 0, len(pods))
+	weights := make(map[string]int32)
+	for i, pod := range pods {
+		value, ok := pod.Annotations[slinkyv1alpha1.AnnotationPodSlurmWeight]
+		if !ok {
+			continue
+		}
+		nodeName := nodesetutils.GetNodeName(pod)
+		nodeWeight, ok := nodeWeights[nodeName]
+		if !ok {
+			continue
+		}
+		if weight, err := strconv.ParseInt(value, 10, 32); err == nil && int32(weight) != nodeWeight {
+			weights[nodeName] = int32(weight)
+		}
+		restored = append(restored, i)
+	}
+	if len(weights) > 0 {
+		if err := r.slurmControl.SetNodeWeights(ctx, nodeset, weights); err != nil {
+			return err
+		}
+	}
+
+	for _, i := range restored {
+		pod := pods[i]
+		toUpdate := pod.DeepCopy()
+		delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodSlurmWeight)
+		if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		pods[i] = toUpdate
+	}
+
+	return nil
+}
+
+// calculateNodeWeights returns the weight of each Slurm node by its name, from
+// the pods in scale-in order. The last pod to be condemned gets a weight of 1.
+func calculateNodeWeights(pods []*corev1.Pod) map[string]int32 {
+	weights := make(map[string]int32, len(pods))
+	for i, pod := range pods {
+		weights[nodesetutils.GetNodeName(pod)] = int32(len(pods) - i)
+	}
+	return weights
+}
+
+// syncDrainTimeouts applies the drain timeout action to the condemned pods
+// whose Slurm node did not drain within the drain timeout. Each timeout is
+// recorded as an event and in the NodeSet status, once per cordon.
+func (r *NodeSetReconciler) syncDrainTimeouts(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	condemned []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+
+	now := time.Now()
+	for _, pod := range condemned {
+		deadline, ok := getDrainDeadline(nodeset, pod)
+		if !ok || now.Before(deadline) || utils.IsTerminating(pod) {
+			continue
+		}
+		if hasDrainTimeout(nodeset, pod, deadline) {
+			continue
+		}
+		isDrained, err := r.slurmControl.IsNodeDrained(ctx, nodeset, pod)
+		if err != nil {
+			return err
+		}
+		if isDrained {
+			continue
+		}
+
+		jobIDs, err := r.slurmControl.GetNodeJobs(ctx, nodeset, pod)
+		if err != nil {
+			return err
+		}
+		action := getDrainTimeoutAction(nodeset)
+		switch action {
+		case slinkyv1alpha1.RequeueJobsDrainTimeoutActionType:
+			// Setting the Slurm node DOWN only requeues the jobs that may be
+			// requeued, the others would end with NODE_FAIL.
+			if err := r.slurmControl.MakeJobsRequeueable(ctx, nodeset, jobIDs); err != nil {
+				return err
+			}
+			reason := fmt.Sprintf("Pod (%s) did not drain within the drain timeout", klog.KObj(pod))
+			if err := r.slurmControl.MakeNodeDown(ctx, nodeset, pod, reason); err != nil {
+				return err
+			}
+		case slinkyv1alpha1.CancelJobsDrainTimeoutActionType:
+			if err := r.slurmControl.CancelJobs(ctx, nodeset, jobIDs); err != nil {
+				return err
+			}
+		}
+
+		logger.Info("NodeSet Pod did not drain within the drain timeout", "nodeset", klog.KObj(nodeset),
+			"pod", klog.KObj(pod), "action", action, "jobs", jobIDs)
+		r.eventRecorder.Eventf(nodeset, corev1.EventTypeWarning, DrainTimeoutReason,
+			"Pod %s did not drain within %v, applied %s to jobs %v",
+			pod.Name, nodeset.Spec.ScaleIn.DrainTimeout.Duration, action, jobIDs)
+		recordDrainTimeout(nodeset, slinkyv1alpha1.NodeSetDrainTimeout{
+			Pod:    pod.Name,
+			Action: action,
+			Jobs:   jobIDs,
+			Time:   metav1.NewTime(now),
+		})
+	}
+
+	return nil
+}
+
+// getDrainDeadline returns when the drain of the cordoned pod times out.
+func getDrainDeadline(nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) (time.Time, bool) {
+	scaleIn := nodeset.Spec.ScaleIn
+	if scaleIn == nil || scaleIn.DrainTimeout == nil || !utils.IsPodCordon(pod) {
+		return time.Time{}, false
+	}
+	cordonTime, err := utils.GetTimeFromAnnotations(pod.Annotations, slinkyv1alpha1.AnnotationPodCordonTime)
+	if err != nil || cordonTime.IsZero() {
+		return time.Time{}, false
+	}
+	return cordonTime.Add(scaleIn.DrainTimeout.Duration), true
+}
+
+// getDrainTimeoutAction returns the drain timeout action, defaulting to Wait.
+func getDrainTimeoutAction(nodeset *slinkyv1alpha1.NodeSet) slinkyv1alpha1.DrainTimeoutActionType {
+	if nodeset.Spec.ScaleIn == nil || nodeset.Spec.ScaleIn.DrainTimeoutAction == "" {
+		return slinkyv1alpha1.WaitDrainTimeoutActionType
+	}
+	return nodeset.Spec.ScaleIn.DrainTimeoutAction
+}
+
+// isDrainForced returns true when the condemned pod may be deleted before its
+// Slurm node drained.
+func isDrainForced(nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, now time.Time) bool {
+	if getDrainTimeoutAction(nodeset) != slinkyv1alpha1.ForceDeleteDrainTimeoutActionType {
+		return false
+	}
+	deadline, ok := getDrainDeadline(nodeset, pod)
+	return ok && !now.Before(deadline)
+}
+
+// hasDrainTimeout returns true when the drain timeout of the pod since the
+// deadline was already recorded.
+func hasDrainTimeout(nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, deadline time.Time) bool {
+	if nodeset.Status.ScaleIn == nil {
+		return false
+	}
+	return slices.ContainsFunc(nodeset.Status.ScaleIn.DrainTimeouts, func(drainTimeout slinkyv1alpha1.NodeSetDrainTimeout) bool {
+		return drainTimeout.Pod == pod.Name && !drainTimeout.Time.Time.Before(deadline)
+	})
+}
+
+// recordDrainTimeout adds the drain timeout to the NodeSet status, keeping the
+// latest ones.
+func recordDrainTimeout(nodeset *slinkyv1alpha1.NodeSet, drainTimeout slinkyv1alpha1.NodeSetDrainTimeout) {
+	if nodeset.Status.ScaleIn == nil {
+		nodeset.Status.ScaleIn = &slinkyv1alpha1.NodeSetScaleInStatus{}
+	}
+	drainTimeouts := append(nodeset.Status.ScaleIn.DrainTimeouts, drainTimeout)
+	if len(drainTimeouts) > maxDrainTimeouts {
+		drainTimeouts = drainTimeouts[len(drainTimeouts)-maxDrainTimeouts:]
+	}
+	nodeset.Status.ScaleIn.DrainTimeouts = drainTimeouts
+}
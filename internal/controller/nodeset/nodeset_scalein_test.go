@@ -0,0 +1,653 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeset
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+	"github.com/SlinkyProject/slurm-operator/internal/utils"
+)
+
+func Test_calculateScaleIn(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) *metav1.Time {
+		return ptr.To(metav1.NewTime(now.Add(-d)))
+	}
+	scaleIn := &slinkyv1alpha1.NodeSetScaleIn{
+		StabilizationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+		MinDrainDuration:    &metav1.Duration{Duration: 2 * time.Minute},
+	}
+	tests := []struct {
+		name       string
+		scaleIn    *slinkyv1alpha1.NodeSetScaleIn
+		status     *slinkyv1alpha1.NodeSetScaleInStatus
+		current    int32
+		desired    int32
+		want       int32
+		wantStatus *slinkyv1alpha1.NodeSetScaleInStatus
+		wantWait   time.Duration
+	}{
+		{
+			name:    "No policy",
+			current: 5,
+			desired: 2,
+			want:    2,
+		},
+		{
+			name:    "No policy clears status",
+			status:  &slinkyv1alpha1.NodeSetScaleInStatus{PendingReplicas: ptr.To[int32](2), PendingSince: ago(0)},
+			current: 5,
+			desired: 2,
+			want:    2,
+		},
+		{
+			name:    "Scale out",
+			scaleIn: scaleIn,
+			current: 2,
+			desired: 5,
+			want:    5,
+		},
+		{
+			name:    "Scale-in starts the window",
+			scaleIn: scaleIn,
+			current: 5,
+			desired: 2,
+			want:    5,
+			wantStatus: &slinkyv1alpha1.NodeSetScaleInStatus{
+				PendingReplicas: ptr.To[int32](2),
+				PendingSince:    ago(0),
+			},
+			wantWait: 5 * time.Minute,
+		},
+		{
+			name:    "Scale-in within the window keeps the highest replicas",
+			scaleIn: scaleIn,
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				PendingReplicas: ptr.To[int32](2),
+				PendingSince:    ago(time.Minute),
+			},
+			current: 5,
+			desired: 3,
+			want:    5,
+			wantStatus: &slinkyv1alpha1.NodeSetScaleInStatus{
+				PendingReplicas: ptr.To[int32](3),
+				PendingSince:    ago(time.Minute),
+			},
+			wantWait: 4 * time.Minute,
+		},
+		{
+			name:    "Replicas restored within the window",
+			scaleIn: scaleIn,
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				PendingReplicas: ptr.To[int32](2),
+				PendingSince:    ago(time.Minute),
+			},
+			current: 5,
+			desired: 5,
+			want:    5,
+		},
+		{
+			name:    "Scale-in held for the window is committed",
+			scaleIn: scaleIn,
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				PendingReplicas: ptr.To[int32](3),
+				PendingSince:    ago(5 * time.Minute),
+			},
+			current: 5,
+			desired: 2,
+			want:    3,
+			wantStatus: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](3),
+				StartTime: ago(0),
+			},
+			wantWait: 2 * time.Minute,
+		},
+		{
+			name:    "Committed scale-in ignores scale out",
+			scaleIn: scaleIn,
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](3),
+				StartTime: ago(time.Minute),
+			},
+			current: 5,
+			desired: 5,
+			want:    3,
+			wantStatus: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](3),
+				StartTime: ago(time.Minute),
+			},
+			wantWait: time.Minute,
+		},
+		{
+			name:    "Committed scale-in finishes after the minimum drain",
+			scaleIn: scaleIn,
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](3),
+				StartTime: ago(3 * time.Minute),
+			},
+			current: 5,
+			desired: 4,
+			want:    4,
+			wantStatus: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](4),
+				StartTime: ago(3 * time.Minute),
+			},
+		},
+		{
+			name:    "Committed scale-in done",
+			scaleIn: scaleIn,
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](3),
+				StartTime: ago(3 * time.Minute),
+			},
+			current: 3,
+			desired: 5,
+			want:    5,
+		},
+		{
+			name:    "Committed scale-in waits the window to go lower",
+			scaleIn: scaleIn,
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](3),
+				StartTime: ago(time.Minute),
+			},
+			current: 5,
+			desired: 1,
+			want:    3,
+			wantStatus: &slinkyv1alpha1.NodeSetScaleInStatus{
+				PendingReplicas: ptr.To[int32](1),
+				PendingSince:    ago(0),
+				Replicas:        ptr.To[int32](3),
+				StartTime:       ago(time.Minute),
+			},
+			wantWait: time.Minute,
+		},
+		{
+			name:    "Empty policy scales in at once",
+			scaleIn: &slinkyv1alpha1.NodeSetScaleIn{},
+			current: 5,
+			desired: 2,
+			want:    2,
+			wantStatus: &slinkyv1alpha1.NodeSetScaleInStatus{
+				Replicas:  ptr.To[int32](2),
+				StartTime: ago(0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotStatus, gotWait := calculateScaleIn(tt.scaleIn, tt.status, tt.current, tt.desired, now)
+			if got != tt.want {
+				t.Errorf("calculateScaleIn() = %v, want %v", got, tt.want)
+			}
+			if !apiequality.Semantic.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("calculateScaleIn() status = %+v, want %+v", gotStatus, tt.wantStatus)
+			}
+			if gotWait != tt.wantWait {
+				t.Errorf("calculateScaleIn() wait = %v, want %v", gotWait, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_scaleInReplicas(t *testing.T) {
+	nodeset := newNodeSet("foo", "slurm", 1)
+	nodeset.Spec.ScaleIn = &slinkyv1alpha1.NodeSetScaleIn{
+		StabilizationWindow: &metav1.Duration{Duration: time.Minute},
+	}
+	key := utils.KeyFunc(nodeset)
+	defer scalingDurationStore.Pop(key)
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 2, ""),
+	}
+
+	r := &NodeSetReconciler{}
+	if got := r.scaleInReplicas(context.TODO(), nodeset, pods); got != 3 {
+		t.Errorf("scaleInReplicas() = %v, want 3", got)
+	}
+	status := nodeset.Status.ScaleIn
+	if status == nil || ptr.Deref(status.PendingReplicas, 0) != 1 || status.PendingSince == nil {
+		t.Errorf("scaleInReplicas() status = %+v, want pending 1 replica", status)
+	}
+	if wait := scalingDurationStore.Pop(key); wait <= 0 || wait > time.Minute {
+		t.Errorf("scaleInReplicas() requeue = %v, want within %v", wait, time.Minute)
+	}
+}
+
+func TestNodeSetReconciler_syncDrainTimeouts(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	now := time.Now()
+	tests := []struct {
+		name          string
+		action        slinkyv1alpha1.DrainTimeoutActionType
+		cordonTime    time.Time
+		status        *slinkyv1alpha1.NodeSetScaleInStatus
+		wantTimeout   bool
+		wantNodeState v0041.V0041NodeState
+		wantJobs      []int32
+		wantRequeue   []int32
+		wantForced    bool
+	}{
+		{
+			name:       "Within drain timeout",
+			action:     slinkyv1alpha1.CancelJobsDrainTimeoutActionType,
+			cordonTime: now.Add(-time.Minute),
+			wantJobs:   []int32{1, 2},
+		},
+		{
+			name:        "Wait",
+			cordonTime:  now.Add(-2 * time.Hour),
+			wantTimeout: true,
+			wantJobs:    []int32{1, 2},
+		},
+		{
+			name:          "Requeue jobs",
+			action:        slinkyv1alpha1.RequeueJobsDrainTimeoutActionType,
+			cordonTime:    now.Add(-2 * time.Hour),
+			wantTimeout:   true,
+			wantNodeState: v0041.V0041NodeStateDOWN,
+			wantJobs:      []int32{1, 2},
+			wantRequeue:   []int32{1},
+		},
+		{
+			name:        "Cancel jobs",
+			action:      slinkyv1alpha1.CancelJobsDrainTimeoutActionType,
+			cordonTime:  now.Add(-2 * time.Hour),
+			wantTimeout: true,
+			wantJobs:    []int32{2},
+		},
+		{
+			name:        "Force delete",
+			action:      slinkyv1alpha1.ForceDeleteDrainTimeoutActionType,
+			cordonTime:  now.Add(-2 * time.Hour),
+			wantTimeout: true,
+			wantJobs:    []int32{1, 2},
+			wantForced:  true,
+		},
+		{
+			name:       "Already recorded",
+			action:     slinkyv1alpha1.CancelJobsDrainTimeoutActionType,
+			cordonTime: now.Add(-2 * time.Hour),
+			status: &slinkyv1alpha1.NodeSetScaleInStatus{
+				DrainTimeouts: []slinkyv1alpha1.NodeSetDrainTimeout{
+					{
+						Pod:    "foo-0",
+						Action: slinkyv1alpha1.CancelJobsDrainTimeoutActionType,
+						Jobs:   []int32{1},
+						Time:   metav1.NewTime(now.Add(-time.Minute)),
+					},
+				},
+			},
+			wantTimeout: true,
+			wantJobs:    []int32{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 0)
+			nodeset.Spec.ScaleIn = &slinkyv1alpha1.NodeSetScaleIn{
+				DrainTimeout:       &metav1.Duration{Duration: time.Hour},
+				DrainTimeoutAction: tt.action,
+			}
+			nodeset.Status.ScaleIn = tt.status
+			pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+			pod.Annotations[slinkyv1alpha1.AnnotationPodCordon] = "true"
+			pod.Annotations[slinkyv1alpha1.AnnotationPodCordonTime] = tt.cordonTime.Format(time.RFC3339)
+
+			slurmNode := newNodeSetPodSlurmNode(pod)
+			slurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED, v0041.V0041NodeStateDRAIN})
+			nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*slurmNode}}
+			jobList := &slurmtypes.V0041JobInfoList{
+				Items: []slurmtypes.V0041JobInfo{
+					{
+						V0041JobInfo: v0041.V0041JobInfo{
+							JobId:    ptr.To[int32](1),
+							JobState: ptr.To([]v0041.V0041JobInfoJobState{v0041.V0041JobInfoJobStateRUNNING}),
+							Nodes:    ptr.To(nodesetutils.GetNodeName(pod)),
+						},
+					},
+					{
+						V0041JobInfo: v0041.V0041JobInfo{
+							JobId:    ptr.To[int32](2),
+							JobState: ptr.To([]v0041.V0041JobInfoJobState{v0041.V0041JobInfoJobStateRUNNING}),
+							Nodes:    ptr.To("foo-1"),
+						},
+					},
+				},
+			}
+			slurmClient := newFakeClientList(interceptor.Funcs{}, nodeList, jobList)
+			c := fake.NewClientBuilder().WithObjects(nodeset.DeepCopy(), pod.DeepCopy()).Build()
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+			if err := r.syncDrainTimeouts(context.TODO(), nodeset, []*corev1.Pod{pod}); err != nil {
+				t.Fatalf("syncDrainTimeouts() error = %v", err)
+			}
+
+			var drainTimeouts []slinkyv1alpha1.NodeSetDrainTimeout
+			if nodeset.Status.ScaleIn != nil {
+				drainTimeouts = nodeset.Status.ScaleIn.DrainTimeouts
+			}
+			if hasTimeout := len(drainTimeouts) > 0; hasTimeout != tt.wantTimeout {
+				t.Fatalf("syncDrainTimeouts() status = %+v, want timeout %v", drainTimeouts, tt.wantTimeout)
+			}
+			if tt.wantTimeout && tt.status == nil {
+				got := drainTimeouts[0]
+				if got.Pod != pod.Name || got.Action != getDrainTimeoutAction(nodeset) ||
+					!slices.Equal(got.Jobs, []int32{1}) {
+					t.Errorf("syncDrainTimeouts() status = %+v", got)
+				}
+			}
+			if tt.status != nil && len(drainTimeouts) != len(tt.status.DrainTimeouts) {
+				t.Errorf("syncDrainTimeouts() recorded again, status = %+v", drainTimeouts)
+			}
+
+			gotNode := &slurmtypes.V0041Node{}
+			if err := slurmClient.Get(context.TODO(), object.ObjectKey(nodesetutils.GetNodeName(pod)), gotNode); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if hasDown := gotNode.GetStateAsSet().Has(v0041.V0041NodeStateDOWN); hasDown != (tt.wantNodeState == v0041.V0041NodeStateDOWN) {
+				t.Errorf("syncDrainTimeouts() node state = %v, want %v", gotNode.GetStateAsSet().UnsortedList(), tt.wantNodeState)
+			}
+
+			gotJobs := &slurmtypes.V0041JobInfoList{}
+			if err := slurmClient.List(context.TODO(), gotJobs); err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			jobIDs := []int32{}
+			requeueIDs := []int32{}
+			for _, job := range gotJobs.Items {
+				jobIDs = append(jobIDs, ptr.Deref(job.JobId, 0))
+				if ptr.Deref(job.Requeue, false) {
+					requeueIDs = append(requeueIDs, ptr.Deref(job.JobId, 0))
+				}
+			}
+			slices.Sort(jobIDs)
+			if !slices.Equal(jobIDs, tt.wantJobs) {
+				t.Errorf("syncDrainTimeouts() jobs = %v, want %v", jobIDs, tt.wantJobs)
+			}
+			slices.Sort(requeueIDs)
+			if !slices.Equal(requeueIDs, tt.wantRequeue) && len(requeueIDs)+len(tt.wantRequeue) > 0 {
+				t.Errorf("syncDrainTimeouts() requeueable jobs = %v, want %v", requeueIDs, tt.wantRequeue)
+			}
+
+			if forced := isDrainForced(nodeset, pod, now); forced != tt.wantForced {
+				t.Errorf("isDrainForced() = %v, want %v", forced, tt.wantForced)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_splitScaleInPods(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name       string
+		policy     slinkyv1alpha1.ScaleInPolicyType
+		noClient   bool
+		wantDelete []string
+	}{
+		{
+			name:       "No policy",
+			wantDelete: []string{"foo-2"},
+		},
+		{
+			name:       "PreferIdle",
+			policy:     slinkyv1alpha1.PreferIdleScaleInPolicyType,
+			wantDelete: []string{"foo-0"},
+		},
+		{
+			name:       "LeastAllocatedCPUs",
+			policy:     slinkyv1alpha1.LeastAllocatedCPUsScaleInPolicyType,
+			wantDelete: []string{"foo-0"},
+		},
+		{
+			name:       "No Slurm client",
+			policy:     slinkyv1alpha1.PreferIdleScaleInPolicyType,
+			noClient:   true,
+			wantDelete: []string{"foo-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 2)
+			nodeset.Spec.ScaleIn = &slinkyv1alpha1.NodeSetScaleIn{
+				Policy: tt.policy,
+			}
+			pods := make([]*corev1.Pod, 0, 3)
+			nodeList := &slurmtypes.V0041NodeList{}
+			for i := range 3 {
+				pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, i, ""))
+				pods = append(pods, pod)
+				slurmNode := newNodeSetPodSlurmNode(pod)
+				if i > 0 {
+					slurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED})
+					slurmNode.AllocCpus = ptr.To[int32](4)
+				}
+				nodeList.Items = append(nodeList.Items, *slurmNode)
+			}
+			slurmClusters := newSlurmClusters(clusterName, newFakeClientList(interceptor.Funcs{}, nodeList))
+			if tt.noClient {
+				slurmClusters = resources.NewClusters()
+			}
+			c := fake.NewClientBuilder().WithObjects(nodeset.DeepCopy()).Build()
+			r := newNodeSetController(c, slurmClusters)
+
+			podsToDelete, podsToKeep := r.splitScaleInPods(context.TODO(), nodeset, pods, 1)
+			gotDelete := make([]string, 0, len(podsToDelete))
+			for _, pod := range podsToDelete {
+				gotDelete = append(gotDelete, pod.Name)
+			}
+			if !slices.Equal(gotDelete, tt.wantDelete) {
+				t.Errorf("splitScaleInPods() delete = %v, want %v", gotDelete, tt.wantDelete)
+			}
+			if len(podsToKeep) != 2 {
+				t.Errorf("splitScaleInPods() keep = %d pods, want 2", len(podsToKeep))
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_syncNodeWeights(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	tests := []struct {
+		name        string
+		scaleIn     *slinkyv1alpha1.NodeSetScaleIn
+		wantWeights map[string]int32
+		wantRecord  bool
+	}{
+		{
+			name:        "Not managed",
+			scaleIn:     &slinkyv1alpha1.NodeSetScaleIn{Policy: slinkyv1alpha1.PreferIdleScaleInPolicyType},
+			wantWeights: map[string]int32{"foo-0": 5, "foo-1": 5, "foo-2": 5},
+		},
+		{
+			name:        "Default order",
+			scaleIn:     &slinkyv1alpha1.NodeSetScaleIn{ManageNodeWeight: true},
+			wantWeights: map[string]int32{"foo-0": 1, "foo-1": 2, "foo-2": 3},
+			wantRecord:  true,
+		},
+		{
+			name: "PreferIdle",
+			scaleIn: &slinkyv1alpha1.NodeSetScaleIn{
+				ManageNodeWeight: true,
+				Policy:           slinkyv1alpha1.PreferIdleScaleInPolicyType,
+			},
+			wantWeights: map[string]int32{"foo-0": 3, "foo-1": 1, "foo-2": 2},
+			wantRecord:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 3)
+			nodeset.Spec.ScaleIn = tt.scaleIn
+			pods := make([]*corev1.Pod, 0, 3)
+			objs := make([]client.Object, 0, 3)
+			nodeList := &slurmtypes.V0041NodeList{}
+			for i := range 3 {
+				pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, i, ""))
+				pods = append(pods, pod)
+				objs = append(objs, pod.DeepCopy())
+				slurmNode := newNodeSetPodSlurmNode(pod)
+				slurmNode.Weight = ptr.To[int32](5)
+				if i > 0 {
+					slurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED})
+				}
+				nodeList.Items = append(nodeList.Items, *slurmNode)
+			}
+			slurmClient := newFakeClientList(interceptor.Funcs{}, nodeList)
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+			if err := r.syncNodeWeights(context.TODO(), nodeset, pods); err != nil {
+				t.Fatalf("syncNodeWeights() error = %v", err)
+			}
+			for i, pod := range pods {
+				if pod.Name != nodesetutils.GetPodName(nodeset, i) {
+					t.Fatalf("syncNodeWeights() reordered pods, got %s at %d", pod.Name, i)
+				}
+				gotPod := &corev1.Pod{}
+				if err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), gotPod); err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				if value, ok := gotPod.Annotations[slinkyv1alpha1.AnnotationPodSlurmWeight]; ok != tt.wantRecord || (ok && value != "5") {
+					t.Errorf("syncNodeWeights() pod %s weight annotation = %q, want recorded %v", pod.Name, value, tt.wantRecord)
+				}
+			}
+
+			if got := getSlurmNodeWeights(t, slurmClient); !apiequality.Semantic.DeepEqual(got, tt.wantWeights) {
+				t.Errorf("syncNodeWeights() weights = %v, want %v", got, tt.wantWeights)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_syncNodeWeights_toggle(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 3)
+	nodeset.Spec.ScaleIn = &slinkyv1alpha1.NodeSetScaleIn{ManageNodeWeight: true}
+	pods := make([]*corev1.Pod, 0, 3)
+	objs := make([]client.Object, 0, 3)
+	nodeList := &slurmtypes.V0041NodeList{}
+	for i := range 3 {
+		pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, i, ""))
+		pods = append(pods, pod)
+		objs = append(objs, pod.DeepCopy())
+		slurmNode := newNodeSetPodSlurmNode(pod)
+		slurmNode.Weight = ptr.To[int32](5)
+		nodeList.Items = append(nodeList.Items, *slurmNode)
+	}
+	fakeClient := newFakeClientList(interceptor.Funcs{}, nodeList)
+	var nodeUpdates int
+	slurmClient := interceptor.NewClient(fakeClient, interceptor.Funcs{
+		Update: func(ctx context.Context, obj object.Object, req any, opts ...slurmclient.UpdateOption) error {
+			nodeUpdates++
+			return fakeClient.Update(ctx, obj, req, opts...)
+		},
+	})
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+	r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+	syncNodeWeights := func(wantWeights map[string]int32, wantUpdates int) {
+		t.Helper()
+		if err := r.syncNodeWeights(context.TODO(), nodeset, pods); err != nil {
+			t.Fatalf("syncNodeWeights() error = %v", err)
+		}
+		if got := getSlurmNodeWeights(t, fakeClient); !apiequality.Semantic.DeepEqual(got, wantWeights) {
+			t.Errorf("syncNodeWeights() weights = %v, want %v", got, wantWeights)
+		}
+		if nodeUpdates != wantUpdates {
+			t.Errorf("syncNodeWeights() Slurm node updates = %v, want %v", nodeUpdates, wantUpdates)
+		}
+	}
+
+	// Managed weights are only updated when they differ from the Slurm nodes.
+	managed := map[string]int32{"foo-0": 1, "foo-1": 2, "foo-2": 3}
+	syncNodeWeights(managed, 3)
+	syncNodeWeights(managed, 3)
+
+	// Once disabled, the weights the Slurm nodes had are restored, once.
+	nodeset.Spec.ScaleIn.ManageNodeWeight = false
+	restored := map[string]int32{"foo-0": 5, "foo-1": 5, "foo-2": 5}
+	syncNodeWeights(restored, 6)
+	syncNodeWeights(restored, 6)
+	for _, pod := range pods {
+		gotPod := &corev1.Pod{}
+		if err := c.Get(context.TODO(), client.ObjectKeyFromObject(pod), gotPod); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if _, ok := gotPod.Annotations[slinkyv1alpha1.AnnotationPodSlurmWeight]; ok {
+			t.Errorf("syncNodeWeights() pod %s kept the weight annotation", pod.Name)
+		}
+	}
+}
+
+func getSlurmNodeWeights(t *testing.T, slurmClient slurmclient.Client) map[string]int32 {
+	t.Helper()
+	nodeList := &slurmtypes.V0041NodeList{}
+	if err := slurmClient.List(context.TODO(), nodeList); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	weights := make(map[string]int32, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		weights[ptr.Deref(node.Name, "")] = ptr.Deref(node.Weight, 0)
+	}
+	return weights
+}
+
+func Test_calculateNodeWeights(t *testing.T) {
+	nodeset := newNodeSet("foo", "slurm", 3)
+	tests := []struct {
+		name string
+		pods []*corev1.Pod
+		want map[string]int32
+	}{
+		{
+			name: "Empty",
+			pods: nil,
+			want: map[string]int32{},
+		},
+		{
+			name: "Scale-in order",
+			pods: []*corev1.Pod{
+				nodesetutils.NewNodeSetPod(nodeset, 2, ""),
+				nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+				nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+			},
+			want: map[string]int32{"foo-2": 3, "foo-0": 2, "foo-1": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateNodeWeights(tt.pods); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("calculateNodeWeights() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
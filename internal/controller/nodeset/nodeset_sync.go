@@ -45,7 +45,10 @@ func (r *NodeSetReconciler) Sync(ctx context.Context, req reconcile.Request) err
 		if apierrors.IsNotFound(err) {
 			logger.V(3).Info("NodeSet has been deleted.", "request", req)
 			r.expectations.DeleteExpectations(logger, req.NamespacedName.String())
-			return nil
+			if r.deletedPodQueue != nil {
+				_ = r.deletedPodQueue.Pop(req.NamespacedName.String())
+			}
+			return r.releaseOrphanedPods(ctx, req.NamespacedName)
 		}
 		return err
 	}
@@ -61,6 +64,16 @@ func (r *NodeSetReconciler) Sync(ctx context.Context, req reconcile.Request) err
 		return nil
 	}
 
+	granted, err := slinkyv1alpha1.IsClusterReferenceGranted(ctx, r.Client, nodeset)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		r.eventRecorder.Eventf(nodeset, corev1.EventTypeWarning, ClusterReferenceNotGrantedReason,
+			"Cluster(%s) is in another namespace and no ClusterReferenceGrant allows the reference.", nodeset.ClusterKey())
+		return nil
+	}
+
 	if err := r.adoptOrphanRevisions(ctx, nodeset); err != nil {
 		return err
 	}
@@ -81,10 +94,27 @@ func (r *NodeSetReconciler) Sync(ctx context.Context, req reconcile.Request) err
 		return err
 	}
 
+	if err := r.syncDeletedPods(ctx, nodeset); err != nil {
+		return r.syncStatus(ctx, nodeset, nodesetPods, currentRevision, updateRevision, collisionCount, hash, err)
+	}
+
+	if err := r.syncPodDeletion(ctx, nodeset, nodesetPods); err != nil {
+		return r.syncStatus(ctx, nodeset, nodesetPods, currentRevision, updateRevision, collisionCount, hash, err)
+	}
+
 	if !r.expectations.SatisfiedExpectations(logger, key) || nodeset.DeletionTimestamp != nil {
 		return r.syncStatus(ctx, nodeset, nodesetPods, currentRevision, updateRevision, collisionCount, hash)
 	}
 
+	r.syncSafeMode(ctx, nodeset)
+	// The Slurm nodes are fetched once, then read from the cache by each step.
+	if !isSafeMode(nodeset) {
+		if err := r.slurmControl.RefreshNodes(ctx, nodeset); err != nil {
+			return r.syncStatus(ctx, nodeset, nodesetPods, currentRevision, updateRevision, collisionCount, hash, err)
+		}
+	}
+	r.syncAutoscaling(ctx, nodeset, nodesetPods)
+
 	if err := r.sync(ctx, nodeset, nodesetPods, hash); err != nil {
 		return r.syncStatus(ctx, nodeset, nodesetPods, currentRevision, updateRevision, collisionCount, hash, err)
 	}
@@ -227,6 +257,18 @@ func (r *NodeSetReconciler) sync(
 		return err
 	}
 
+	if err := r.syncPodConditions(ctx, nodeset, pods); err != nil {
+		return err
+	}
+
+	if err := r.syncRemediation(ctx, nodeset, pods); err != nil {
+		return err
+	}
+
+	if err := r.syncNodeRequests(ctx, nodeset, pods); err != nil {
+		return err
+	}
+
 	if err := r.syncNodeSet(ctx, nodeset, pods, hash); err != nil {
 		return err
 	}
@@ -244,6 +286,10 @@ func (r *NodeSetReconciler) syncSlurm(
 	if err != nil {
 		return err
 	}
+	nodeDrainReasons, err := r.slurmControl.GetNodeDrainReasons(ctx, nodeset, pods)
+	if err != nil {
+		return err
+	}
 
 	syncSlurmFn := func(i int) error {
 		pod := pods[i]
@@ -256,20 +302,43 @@ func (r *NodeSetReconciler) syncSlurm(
 		} else {
 			toUpdate.Annotations[slinkyv1alpha1.AnnotationPodDeadline] = deadline.Format(time.RFC3339)
 		}
+		// Mirror the drains of the Slurm node that were not made by the controller.
+		if drainReason, ok := nodeDrainReasons[slurmNodeName]; ok {
+			toUpdate.Annotations[slinkyv1alpha1.AnnotationPodSlurmDrain] = drainReason
+		} else {
+			delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodSlurmDrain)
+		}
 		if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
 			return err
 		}
 
+		maintenanceReason, err := r.getNodeMaintenanceReason(ctx, pod)
+		if err != nil {
+			return err
+		}
+
 		if utils.IsPodCordon(pod) {
 			reason := fmt.Sprintf("Pod (%s) is cordoned", klog.KObj(pod))
 			if err := r.slurmControl.MakeNodeDrain(ctx, nodeset, pod, reason); err != nil {
 				return err
 			}
+		} else if maintenanceReason != "" {
+			if err := r.slurmControl.MakeNodeDrain(ctx, nodeset, pod, maintenanceReason); err != nil {
+				return err
+			}
 		} else {
 			reason := fmt.Sprintf("Pod (%s) is uncordoned", klog.KObj(pod))
 			if err := r.slurmControl.MakeNodeUndrain(ctx, nodeset, pod, reason); err != nil {
 				return err
 			}
+			// A replacement pod may register with the Slurm node of a deleted
+			// pod, which was set DOWN.
+			if utils.IsRunningAndContainersReady(pod) && !utils.IsTerminating(pod) {
+				reason := fmt.Sprintf("Pod (%s) is running", klog.KObj(pod))
+				if err := r.slurmControl.MakeNodeResume(ctx, nodeset, pod, reason); err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	}
@@ -277,6 +346,10 @@ func (r *NodeSetReconciler) syncSlurm(
 		return err
 	}
 
+	if err := r.syncNodeWeights(ctx, nodeset, pods); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -293,9 +366,13 @@ func (r *NodeSetReconciler) syncNodeSet(
 ) error {
 	logger := log.FromContext(ctx)
 
+	if nodesetutils.IsPowerSave(nodeset) {
+		return r.syncPowerSave(ctx, nodeset, pods, hash)
+	}
+
 	// Handle replica scaling by comparing the known pods to the target number of replicas.
 	// Create or delete pods as needed to reach the target number.
-	replicaCount := int(ptr.Deref(nodeset.Spec.Replicas, 0))
+	replicaCount := r.scaleInReplicas(ctx, nodeset, pods)
 	diff := len(pods) - replicaCount
 	if diff < 0 {
 		diff = -diff
@@ -305,7 +382,7 @@ func (r *NodeSetReconciler) syncNodeSet(
 	} else if diff > 0 {
 		logger.V(2).Info("Too many NodeSet pods", "nodeset", klog.KObj(nodeset),
 			"need", replicaCount, "deleting", diff)
-		podsToDelete, podsToKeep := nodesetutils.SplitActivePods(pods, diff)
+		podsToDelete, podsToKeep := r.splitScaleInPods(ctx, nodeset, pods, diff)
 		return r.doPodScaleIn(ctx, nodeset, podsToDelete, podsToKeep)
 	} else {
 		logger.V(2).Info("Processing NodeSet pods", "nodeset", klog.KObj(nodeset),
@@ -323,9 +400,6 @@ func (r *NodeSetReconciler) doPodScaleOut(
 	numCreate int,
 	hash string,
 ) error {
-	logger := log.FromContext(ctx)
-	key := utils.KeyFunc(nodeset)
-
 	uncordonFn := func(i int) error {
 		pod := pods[i]
 		return r.makePodUncordonAndUndrain(ctx, nodeset, pod)
@@ -352,6 +426,19 @@ func (r *NodeSetReconciler) doPodScaleOut(
 		podsToCreate[i] = pod
 	}
 
+	return r.createNodeSetPods(ctx, nodeset, podsToCreate)
+}
+
+// createNodeSetPods creates the NodeSet pods in slow-start batches.
+func (r *NodeSetReconciler) createNodeSetPods(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	podsToCreate []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+	key := utils.KeyFunc(nodeset)
+	numCreate := len(podsToCreate)
+
 	// TODO: Track UIDs of creates just like deletes. The problem currently
 	// is we'd need to wait on the result of a create to record the pod's
 	// UID, which would require locking *across* the create, which will turn
@@ -408,6 +495,12 @@ func (r *NodeSetReconciler) doPodScaleIn(
 	logger := log.FromContext(ctx)
 	key := utils.KeyFunc(nodeset)
 
+	if isSafeMode(nodeset) {
+		logger.Info("Slurm cluster is unreachable, skipping NodeSet pod deletion",
+			"nodeset", klog.KObj(nodeset), "pods", len(podsToDelete))
+		return nil
+	}
+
 	uncordonFn := func(i int) error {
 		pod := podsToKeep[i]
 		return r.makePodUncordonAndUndrain(ctx, nodeset, pod)
@@ -433,6 +526,10 @@ func (r *NodeSetReconciler) doPodScaleIn(
 
 	numDelete := utils.Clamp(len(podsToDelete), 0, burstReplicas)
 
+	if err := r.syncDrainTimeouts(ctx, nodeset, podsToDelete[:numDelete]); err != nil {
+		return err
+	}
+
 	// Snapshot the UIDs (namespace/name) of the pods we're expecting to see
 	// deleted, so we know to record their expectations exactly once either
 	// when we see it as an update of the deletion timestamp, or as a delete.
@@ -498,7 +595,7 @@ func (r *NodeSetReconciler) processCondemned(
 	if err != nil {
 		return err
 	}
-	if utils.IsRunningAndReady(pod) && !isDrained {
+	if utils.IsRunningAndReady(pod) && !isDrained && !isDrainForced(nodeset, pod, time.Now()) {
 		logger.V(2).Info("NodeSet Pod is draining, pending termination for scale-in",
 			"nodeSet", klog.KObj(nodeset), "pod", klog.KObj(pod))
 		// Decrement expectations and requeue reconcile because the Slurm node is not drained yet.
@@ -603,7 +700,7 @@ func (r *NodeSetReconciler) makePodCordon(
 ) error {
 	logger := log.FromContext(ctx)
 
-	if utils.IsPodCordon(pod) {
+	if utils.IsPodCordon(pod) && pod.Annotations[slinkyv1alpha1.AnnotationPodCordonTime] != "" {
 		return nil
 	}
 
@@ -613,6 +710,7 @@ func (r *NodeSetReconciler) makePodCordon(
 		toUpdate.Annotations = make(map[string]string)
 	}
 	toUpdate.Annotations[slinkyv1alpha1.AnnotationPodCordon] = "true"
+	toUpdate.Annotations[slinkyv1alpha1.AnnotationPodCordonTime] = time.Now().Format(time.RFC3339)
 	if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
 		return err
 	}
@@ -626,10 +724,22 @@ func (r *NodeSetReconciler) makePodUncordonAndUndrain(
 	nodeset *slinkyv1alpha1.NodeSet,
 	pod *corev1.Pod,
 ) error {
+	// A deleted pod keeps draining until it is gone.
+	if utils.IsTerminating(pod) {
+		return nil
+	}
+
 	if err := r.makePodUncordon(ctx, pod); err != nil {
 		return err
 	}
 
+	// The Slurm node stays drained while its Kubernetes node is under maintenance.
+	if maintenanceReason, err := r.getNodeMaintenanceReason(ctx, pod); err != nil {
+		return err
+	} else if maintenanceReason != "" {
+		return r.slurmControl.MakeNodeDrain(ctx, nodeset, pod, maintenanceReason)
+	}
+
 	reason := fmt.Sprintf("Pod (%s) has been uncordoned", klog.KObj(pod))
 	if err := r.slurmControl.MakeNodeUndrain(ctx, nodeset, pod, reason); err != nil {
 		return err
@@ -649,6 +759,7 @@ func (r *NodeSetReconciler) makePodUncordon(ctx context.Context, pod *corev1.Pod
 	toUpdate := pod.DeepCopy()
 	logger.Info("Uncordon Pod", "Pod", klog.KObj(toUpdate))
 	delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodCordon)
+	delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodCordonTime)
 	if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
 		return err
 	}
@@ -21,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/slurmcontrol"
 	"github.com/SlinkyProject/slurm-operator/internal/utils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/historycontrol"
 )
@@ -54,7 +55,9 @@ func (r *NodeSetReconciler) syncSlurmStatus(
 ) error {
 	syncSlurmStatusFn := func(i int) error {
 		pod := pods[i]
-		if !utils.IsHealthy(pod) {
+		// The pod is not Ready before its Slurm node is, which is mapped back
+		// to the pod by its pod info.
+		if !utils.IsRunningAndContainersReady(pod) || utils.IsTerminating(pod) {
 			return nil
 		}
 		return r.slurmControl.UpdateNodeWithPodInfo(ctx, nodeset, pod)
@@ -83,9 +86,18 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 	}
 
 	replicaStatus := r.calculateReplicaStatus(nodeset, pods, currentRevision, updateRevision)
-	slurmNodeStatus, err := r.slurmControl.CalculateNodeStatus(ctx, nodeset, pods)
-	if err != nil {
-		return err
+	slurmNodeStatus, slurmErr := r.slurmControl.CalculateNodeStatus(ctx, nodeset, pods)
+	if slurmErr != nil {
+		// Keep the last known Slurm counts, so the rest of the status and its
+		// conditions are still updated while Slurm is unreachable.
+		slurmNodeStatus = slurmcontrol.SlurmNodeStatus{
+			Total:     nodeset.Status.Replicas,
+			Usable:    nodeset.Status.UsableReplicas,
+			Idle:      nodeset.Status.SlurmIdle,
+			Allocated: nodeset.Status.SlurmAllocated,
+			Down:      nodeset.Status.SlurmDown,
+			Drain:     nodeset.Status.SlurmDrain,
+		}
 	}
 
 	newStatus := &slinkyv1alpha1.NodeSetStatus{
@@ -94,10 +106,14 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 		ReadyReplicas:       replicaStatus.Ready,
 		AvailableReplicas:   replicaStatus.Available,
 		UnavailableReplicas: replicaStatus.Unavailable,
+		UsableReplicas:      min(slurmNodeStatus.Usable, replicaStatus.Ready),
 		SlurmIdle:           slurmNodeStatus.Idle,
 		SlurmAllocated:      slurmNodeStatus.Allocated + slurmNodeStatus.Mixed,
 		SlurmDown:           slurmNodeStatus.Down,
 		SlurmDrain:          slurmNodeStatus.Drain,
+		Autoscaling:         nodeset.Status.Autoscaling,
+		ScaleIn:             nodeset.Status.ScaleIn,
+		Remediation:         nodeset.Status.Remediation,
 		ObservedGeneration:  nodeset.Generation,
 		NodeSetHash:         hash,
 		CollisionCount:      &collisionCount,
@@ -108,7 +124,7 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 
 	if apiequality.Semantic.DeepEqual(nodeset.Status, newStatus) {
 		logger.V(2).Info("NodeSet Status has not changed, skipping status update", "nodeset", klog.KObj(nodeset), "status", nodeset.Status)
-		return nil
+		return slurmErr
 	}
 
 	if err := r.updateNodeSetStatus(ctx, nodeset, newStatus); err != nil {
@@ -124,7 +140,7 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 		durationStore.Push(key, 10*time.Second)
 	}
 
-	return nil
+	return slurmErr
 }
 
 type replicaStatus struct {
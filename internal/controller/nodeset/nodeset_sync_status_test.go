@@ -311,6 +311,7 @@ func TestNodeSetReconciler_syncNodeSetStatus(t *testing.T) {
 					ReadyReplicas:     2,
 					AvailableReplicas: 2,
 					UpdatedReplicas:   2,
+					UsableReplicas:    2,
 					SlurmIdle:         2,
 					NodeSetHash:       "12345",
 					CollisionCount:    ptr.To[int32](0),
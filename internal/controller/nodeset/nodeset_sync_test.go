@@ -57,6 +57,8 @@ func newNodeSetController(client client.Client, slurmClusters *resources.Cluster
 		podControl:     podcontrol.NewPodControl(client, eventRecorder),
 		slurmControl:   slurmcontrol.NewSlurmControl(slurmClusters),
 		expectations:   kubecontroller.NewUIDTrackingControllerExpectations(kubecontroller.NewControllerExpectations()),
+
+		deletedPodQueue: newDeletedPodQueue(),
 	}
 	return r
 }
@@ -116,11 +118,16 @@ func makePodCreated(pod *corev1.Pod) *corev1.Pod {
 
 func makePodHealthy(pod *corev1.Pod) *corev1.Pod {
 	pod.Status.Phase = corev1.PodRunning
-	podCond := corev1.PodCondition{
-		Type:   corev1.PodReady,
-		Status: corev1.ConditionTrue,
-	}
-	pod.Status.Conditions = append(pod.Status.Conditions, podCond)
+	pod.Status.Conditions = append(pod.Status.Conditions,
+		corev1.PodCondition{
+			Type:   corev1.ContainersReady,
+			Status: corev1.ConditionTrue,
+		},
+		corev1.PodCondition{
+			Type:   corev1.PodReady,
+			Status: corev1.ConditionTrue,
+		},
+	)
 	return pod
 }
 
@@ -425,6 +432,86 @@ func TestNodeSetReconciler_sync(t *testing.T) {
 	}
 }
 
+func TestNodeSetReconciler_syncSlurm(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 2)
+	// The Slurm nodes of both pods were set DOWN when their previous pods were deleted.
+	replacedPod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+	terminatingPod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 1, ""))
+	terminatingPod.DeletionTimestamp = ptr.To(metav1.Now())
+	terminatingPod.Finalizers = []string{"test"}
+	pods := []*corev1.Pod{replacedPod, terminatingPod}
+	nodeList := &slurmtypes.V0041NodeList{}
+	for _, pod := range pods {
+		slurmNode := newNodeSetPodSlurmNode(pod)
+		slurmNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateDOWN})
+		slurmNode.Reason = ptr.To("slurm-operator: Pod (default/" + pod.Name + ") was deleted while running jobs")
+		nodeList.Items = append(nodeList.Items, *slurmNode)
+	}
+	slurmClient := newFakeClientList(sinterceptor.Funcs{}, nodeList)
+	c := fake.NewClientBuilder().WithObjects(replacedPod.DeepCopy(), terminatingPod.DeepCopy()).Build()
+	r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+	if err := r.syncSlurm(context.TODO(), nodeset, pods); err != nil {
+		t.Fatalf("syncSlurm() error = %v", err)
+	}
+
+	wantDown := map[string]bool{replacedPod.Name: false, terminatingPod.Name: true}
+	for name, want := range wantDown {
+		node := &slurmtypes.V0041Node{}
+		if err := slurmClient.Get(context.TODO(), slurmobject.ObjectKey(name), node); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if isDown := slices.Contains(ptr.Deref(node.State, nil), v0041.V0041NodeStateDOWN); isDown != want {
+			t.Errorf("syncSlurm() node %s state = %v, want DOWN %v", name, ptr.Deref(node.State, nil), want)
+		}
+	}
+}
+
+func TestNodeSetReconciler_syncSlurm_slurmDrain(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	const clusterName = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 2)
+	drainedPod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 0, ""))
+	// The Slurm node of this pod was resumed by an admin.
+	resumedPod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, 1, ""))
+	resumedPod.Annotations[slinkyv1alpha1.AnnotationPodSlurmDrain] = "bad DIMM"
+	pods := []*corev1.Pod{drainedPod, resumedPod}
+	drainedNode := newNodeSetPodSlurmNode(drainedPod)
+	drainedNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN})
+	drainedNode.Reason = ptr.To("bad DIMM")
+	resumedNode := newNodeSetPodSlurmNode(resumedPod)
+	resumedNode.State = ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE})
+	nodeList := &slurmtypes.V0041NodeList{Items: []slurmtypes.V0041Node{*drainedNode, *resumedNode}}
+	slurmClient := newFakeClientList(sinterceptor.Funcs{}, nodeList)
+	c := fake.NewClientBuilder().WithObjects(drainedPod.DeepCopy(), resumedPod.DeepCopy()).Build()
+	r := newNodeSetController(c, newSlurmClusters(clusterName, slurmClient))
+
+	if err := r.syncSlurm(context.TODO(), nodeset, pods); err != nil {
+		t.Fatalf("syncSlurm() error = %v", err)
+	}
+
+	want := map[string]string{drainedPod.Name: "bad DIMM", resumedPod.Name: ""}
+	for name, wantReason := range want {
+		pod := &corev1.Pod{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: nodeset.Namespace, Name: name}, pod); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got := pod.Annotations[slinkyv1alpha1.AnnotationPodSlurmDrain]; got != wantReason {
+			t.Errorf("syncSlurm() pod %s slurm drain = %q, want %q", name, got, wantReason)
+		}
+	}
+
+	node := &slurmtypes.V0041Node{}
+	if err := slurmClient.Get(context.TODO(), slurmobject.ObjectKey(drainedPod.Name), node); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if ptr.Deref(node.Reason, "") != "bad DIMM" {
+		t.Errorf("syncSlurm() node reason = %q, want %q", ptr.Deref(node.Reason, ""), "bad DIMM")
+	}
+}
+
 func TestNodeSetReconciler_syncNodeSet(t *testing.T) {
 	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
 	type fields struct {
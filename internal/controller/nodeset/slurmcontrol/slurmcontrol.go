@@ -5,32 +5,32 @@ package slurmcontrol
 
 import (
 	"context"
-	"math"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/puttsk/hostlist"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
 	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
-	slurmobject "github.com/SlinkyProject/slurm-client/pkg/object"
-	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
 	"github.com/SlinkyProject/slurm-operator/internal/resources"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/podinfo"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/timestore"
 )
 
 type SlurmControlInterface interface {
+	// RefreshNodes refreshes the cached slurm nodes, which the other node getters read.
+	RefreshNodes(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error
 	// UpdateNodeWithPodInfo handles updating the Node with its pod info
 	UpdateNodeWithPodInfo(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) error
 	// MakeNodeDrain handles adding the DRAIN state to the slurm node.
@@ -45,8 +45,45 @@ type SlurmControlInterface interface {
 	CalculateNodeStatus(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (SlurmNodeStatus, error)
 	// GetNodeDeadlines returns a map of node to its deadline time.Time calculated from running jobs.
 	GetNodeDeadlines(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (*timestore.TimeStore, error)
+	// PingController checks that the Slurm cluster can be reached and a slurmctld is up.
+	PingController(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error
+	// CalculatePendingNodes returns the number of nodes requested by pending jobs in the partition, or all partitions if empty.
+	CalculatePendingNodes(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, partition string) (int32, error)
+	// GetNodeJobs returns the IDs of the jobs running on the slurm node.
+	GetNodeJobs(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) ([]int32, error)
+	// MakeNodeDown handles setting the slurm node DOWN, which requeues or ends its jobs.
+	MakeNodeDown(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) error
+	// CancelJobs handles cancelling the slurm jobs.
+	CancelJobs(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, jobIDs []int32) error
+	// MakeJobsRequeueable handles allowing the slurm jobs to be requeued when their nodes fail.
+	MakeJobsRequeueable(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, jobIDs []int32) error
+	// MakeNodeResume handles resuming the slurm node when it was set DOWN by slurm-operator.
+	MakeNodeResume(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) error
+	// GetNodeScaleInInfo returns a map of slurm node name to its state used to rank pods for scale-in.
+	GetNodeScaleInInfo(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]nodesetutils.SlurmNodeInfo, error)
+	// SetNodeWeights handles setting the weight of the slurm nodes, by slurm node name.
+	SetNodeWeights(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, weights map[string]int32) error
+	// GetNodeWeights returns a map of slurm node name to its weight.
+	GetNodeWeights(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]int32, error)
+	// GetNodeDrainReasons returns a map of slurm node name to the reason of its DRAIN, when not set by slurm-operator.
+	GetNodeDrainReasons(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]string, error)
+	// GetNodeConditions returns a map of slurm node name to the pod conditions reflecting its state.
+	GetNodeConditions(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string][]corev1.PodCondition, error)
+	// GetUnhealthyNodes returns a map of slurm node name to its state, when DOWN, FAIL, or NOT_RESPONDING for a reason not set by an admin.
+	GetUnhealthyNodes(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]UnhealthyNode, error)
+	// GetNodeRequests returns a map of slurm node name to the reboot or power down requested on it.
+	GetNodeRequests(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]NodeRequest, error)
+	// CancelNodeReboot handles removing the REBOOT_REQUESTED state from the slurm node.
+	CancelNodeReboot(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) error
 }
 
+var (
+	// ErrNoClient is returned when there is no Slurm client for the cluster of the NodeSet.
+	ErrNoClient = errors.New("no Slurm client for cluster")
+	// ErrControllerDown is returned when no slurmctld responded to a ping.
+	ErrControllerDown = errors.New("no slurmctld responded to ping")
+)
+
 // realSlurmControl is the default implementation of SlurmControlInterface.
 type realSlurmControl struct {
 	slurmClusters *resources.Clusters
@@ -56,15 +93,15 @@ type realSlurmControl struct {
 func (r *realSlurmControl) GetNodeNames(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) ([]string, error) {
 	logger := log.FromContext(ctx)
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do GetNodeNames()",
 			"nodeset", klog.KObj(nodeset))
 		return nil, nil
 	}
 
-	nodeList := &slurmtypes.V0041NodeList{}
-	if err := slurmClient.List(ctx, nodeList); err != nil {
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
 		return nil, err
 	}
 
@@ -75,12 +112,11 @@ func (r *realSlurmControl) GetNodeNames(ctx context.Context, nodeset *slinkyv1al
 	}
 
 	nodeNames := []string{}
-	for _, node := range nodeList.Items {
-		nodeName := ptr.Deref(node.Name, "")
-		if !podNodeNameSet.Has(nodeName) {
+	for _, node := range nodeList {
+		if !podNodeNameSet.Has(node.Name) {
 			continue
 		}
-		nodeNames = append(nodeNames, nodeName)
+		nodeNames = append(nodeNames, node.Name)
 	}
 
 	return nodeNames, nil
@@ -90,16 +126,15 @@ func (r *realSlurmControl) GetNodeNames(ctx context.Context, nodeset *slinkyv1al
 func (r *realSlurmControl) UpdateNodeWithPodInfo(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) error {
 	logger := log.FromContext(ctx)
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do UpdateNodeWithPodInfo()",
 			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
 		return nil
 	}
 
-	slurmNode := &slurmtypes.V0041Node{}
-	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
-	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
 		if tolerateError(err) {
 			return nil
 		}
@@ -111,20 +146,20 @@ func (r *realSlurmControl) UpdateNodeWithPodInfo(ctx context.Context, nodeset *s
 		PodName:   pod.GetName(),
 	}
 	podInfoOld := &podinfo.PodInfo{}
-	_ = podinfo.ParseIntoPodInfo(slurmNode.Comment, podInfoOld)
+	_ = podinfo.ParseIntoPodInfo(ptr.To(slurmNode.Comment), podInfoOld)
 
 	if podInfoOld.Equal(podInfo) {
 		logger.V(3).Info("Node already contains podInfo, skipping update request",
-			"node", slurmNode.GetKey(), "podInfo", podInfo)
+			"node", slurmNode.Name, "podInfo", podInfo)
 		return nil
 	}
 
 	logger.Info("Update Slurm Node with Kubernetes Pod info",
 		"Node", slurmNode.Name, "podInfo", podInfo)
-	req := v0041.V0041UpdateNodeMsg{
+	update := slurmapi.NodeUpdate{
 		Comment: ptr.To(podInfo.ToString()),
 	}
-	if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+	if err := slurmAPI.UpdateNode(ctx, slurmNode, update); err != nil {
 		if tolerateError(err) {
 			return nil
 		}
@@ -140,29 +175,34 @@ const nodeReasonPrefix = "slurm-operator:"
 func (r *realSlurmControl) MakeNodeDrain(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) error {
 	logger := log.FromContext(ctx)
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do MakeNodeDrain()",
 			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
 		return nil
 	}
 
-	slurmNode := &slurmtypes.V0041Node{}
-	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
-	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
 		if tolerateError(err) {
 			return nil
 		}
 		return err
 	}
 
+	if isDrainedByOther(slurmNode) {
+		logger.V(1).Info("Node was drained but not by slurm-operator, skipping drain request",
+			"node", slurmNode.Name, "nodeReason", slurmNode.Reason)
+		return nil
+	}
+
 	logger.V(1).Info("make slurm node drain",
 		"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
-	req := v0041.V0041UpdateNodeMsg{
-		State:  ptr.To([]v0041.V0041UpdateNodeMsgState{v0041.V0041UpdateNodeMsgStateDRAIN}),
+	update := slurmapi.NodeUpdate{
+		State:  []slurmapi.NodeState{slurmapi.NodeStateDrain},
 		Reason: ptr.To(nodeReasonPrefix + " " + reason),
 	}
-	if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+	if err := slurmAPI.UpdateNode(ctx, slurmNode, update); err != nil {
 		if tolerateError(err) {
 			return nil
 		}
@@ -176,41 +216,43 @@ func (r *realSlurmControl) MakeNodeDrain(ctx context.Context, nodeset *slinkyv1a
 func (r *realSlurmControl) MakeNodeUndrain(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) error {
 	logger := log.FromContext(ctx)
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do MakeNodeUndrain()",
 			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
 		return nil
 	}
 
-	slurmNode := &slurmtypes.V0041Node{}
-	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
-	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
 		if tolerateError(err) {
 			return nil
 		}
 		return err
 	}
 
-	nodeReason := ptr.Deref(slurmNode.Reason, "")
-	if !slurmNode.GetStateAsSet().Has(v0041.V0041NodeStateDRAIN) ||
-		slurmNode.GetStateAsSet().Has(v0041.V0041NodeStateUNDRAIN) {
+	if !slurmNode.State.Has(slurmapi.NodeStateDrain) ||
+		slurmNode.State.Has(slurmapi.NodeStateUndrain) {
 		logger.V(1).Info("Node is already undrained, skipping undrain request",
-			"node", slurmNode.GetKey(), "nodeState", slurmNode.State)
+			"node", slurmNode.Name, "nodeState", slurmNode.State.UnsortedList())
+		return nil
+	} else if slurmNode.State.Has(slurmapi.NodeStateRebootRequest) {
+		logger.V(1).Info("Node has a pending reboot, skipping undrain request",
+			"node", slurmNode.Name, "nodeState", slurmNode.State.UnsortedList())
 		return nil
-	} else if nodeReason != "" && !strings.Contains(nodeReason, nodeReasonPrefix) {
+	} else if isDrainedByOther(slurmNode) {
 		logger.Info("Node was drained but not by slurm-operator, skipping undrain request",
-			"node", slurmNode.GetKey(), "nodeReason", nodeReason)
+			"node", slurmNode.Name, "nodeReason", slurmNode.Reason)
 		return nil
 	}
 
 	logger.V(1).Info("make slurm node undrain",
 		"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
-	req := v0041.V0041UpdateNodeMsg{
-		State:  ptr.To([]v0041.V0041UpdateNodeMsgState{v0041.V0041UpdateNodeMsgStateUNDRAIN}),
+	update := slurmapi.NodeUpdate{
+		State:  []slurmapi.NodeState{slurmapi.NodeStateUndrain},
 		Reason: ptr.To(nodeReasonPrefix + " " + reason),
 	}
-	if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+	if err := slurmAPI.UpdateNode(ctx, slurmNode, update); err != nil {
 		if tolerateError(err) {
 			return nil
 		}
@@ -220,27 +262,33 @@ func (r *realSlurmControl) MakeNodeUndrain(ctx context.Context, nodeset *slinkyv
 	return nil
 }
 
+// isDrainedByOther returns true when the slurm node was drained, with a reason,
+// by someone other than slurm-operator.
+func isDrainedByOther(slurmNode *slurmapi.Node) bool {
+	return slurmNode.State.Has(slurmapi.NodeStateDrain) &&
+		slurmNode.Reason != "" && !strings.Contains(slurmNode.Reason, nodeReasonPrefix)
+}
+
 // IsNodeDrain implements SlurmControlInterface.
 func (r *realSlurmControl) IsNodeDrain(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) (bool, error) {
 	logger := log.FromContext(ctx)
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do IsNodeDrain()",
 			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
 		return true, nil
 	}
 
-	slurmNode := &slurmtypes.V0041Node{}
-	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
-	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
 		if tolerateError(err) {
 			return true, nil
 		}
 		return false, err
 	}
 
-	isDrain := slurmNode.GetStateAsSet().Has(v0041.V0041NodeStateDRAIN)
+	isDrain := slurmNode.State.Has(slurmapi.NodeStateDrain)
 	return isDrain, nil
 }
 
@@ -248,16 +296,15 @@ func (r *realSlurmControl) IsNodeDrain(ctx context.Context, nodeset *slinkyv1alp
 func (r *realSlurmControl) IsNodeDrained(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) (bool, error) {
 	logger := log.FromContext(ctx)
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do IsNodeDrained()",
 			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
 		return true, nil
 	}
 
-	slurmNode := &slurmtypes.V0041Node{}
-	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
-	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
 		if tolerateError(err) {
 			return true, nil
 		}
@@ -265,8 +312,8 @@ func (r *realSlurmControl) IsNodeDrained(ctx context.Context, nodeset *slinkyv1a
 	}
 
 	// DRAINED = IDLE+DRAIN || DOWN+DRAIN
-	baseState := slurmNode.GetStateAsSet().HasAny(v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDOWN)
-	flagState := slurmNode.GetStateAsSet().Has(v0041.V0041NodeStateDRAIN)
+	baseState := slurmNode.State.HasAny(slurmapi.NodeStateIdle, slurmapi.NodeStateDown)
+	flagState := slurmNode.State.Has(slurmapi.NodeStateDrain)
 	isDrained := baseState && flagState
 
 	return isDrained, nil
@@ -274,6 +321,8 @@ func (r *realSlurmControl) IsNodeDrained(ctx context.Context, nodeset *slinkyv1a
 
 type SlurmNodeStatus struct {
 	Total int32
+	// Usable is the number of nodes that can run jobs.
+	Usable int32
 
 	// Base State
 	Allocated int32
@@ -295,21 +344,39 @@ type SlurmNodeStatus struct {
 	Undrain       int32
 }
 
+// RefreshNodes implements SlurmControlInterface.
+func (r *realSlurmControl) RefreshNodes(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for nodeset, cannot do RefreshNodes()",
+			"nodeset", klog.KObj(nodeset))
+		return nil
+	}
+
+	opts := &slurmclient.ListOptions{RefreshCache: true}
+	if _, err := slurmAPI.ListNodes(ctx, opts); err != nil && !tolerateError(err) {
+		return err
+	}
+
+	return nil
+}
+
 // CalculateNodeStatus implements SlurmControlInterface.
 func (r *realSlurmControl) CalculateNodeStatus(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (SlurmNodeStatus, error) {
 	logger := log.FromContext(ctx)
 	status := SlurmNodeStatus{}
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do CalculateNodeStatus()",
 			"nodeset", klog.KObj(nodeset))
 		return status, nil
 	}
 
-	nodeList := &slurmtypes.V0041NodeList{}
-	opts := &slurmclient.ListOptions{RefreshCache: true}
-	if err := slurmClient.List(ctx, nodeList, opts); err != nil {
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
 		if tolerateError(err) {
 			return status, nil
 		}
@@ -322,52 +389,54 @@ func (r *realSlurmControl) CalculateNodeStatus(ctx context.Context, nodeset *sli
 		podNodeNameSet.Insert(podNodeName)
 	}
 
-	for _, node := range nodeList.Items {
-		nodeName := ptr.Deref(node.Name, "")
-		if !podNodeNameSet.Has(nodeName) {
+	for _, node := range nodeList {
+		if !podNodeNameSet.Has(node.Name) {
 			continue
 		}
 		status.Total++
 		// Slurm Node Base States
 		switch {
-		case node.GetStateAsSet().Has(v0041.V0041NodeStateALLOCATED):
+		case node.State.Has(slurmapi.NodeStateAllocated):
 			status.Allocated++
-		case node.GetStateAsSet().Has(v0041.V0041NodeStateDOWN):
+		case node.State.Has(slurmapi.NodeStateDown):
 			status.Down++
-		case node.GetStateAsSet().Has(v0041.V0041NodeStateERROR):
+		case node.State.Has(slurmapi.NodeStateError):
 			status.Error++
-		case node.GetStateAsSet().Has(v0041.V0041NodeStateFUTURE):
+		case node.State.Has(slurmapi.NodeStateFuture):
 			status.Future++
-		case node.GetStateAsSet().Has(v0041.V0041NodeStateIDLE):
+		case node.State.Has(slurmapi.NodeStateIdle):
 			status.Idle++
-		case node.GetStateAsSet().Has(v0041.V0041NodeStateMIXED):
+		case node.State.Has(slurmapi.NodeStateMixed):
 			status.Mixed++
-		case node.GetStateAsSet().Has(v0041.V0041NodeStateUNKNOWN):
+		case node.State.Has(slurmapi.NodeStateUnknown):
 			status.Unknown++
 		}
+		if isNodeUsable(node) {
+			status.Usable++
+		}
 		// Slurm Node Flag State
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateCOMPLETING) {
+		if node.State.Has(slurmapi.NodeStateCompleting) {
 			status.Completing++
 		}
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateDRAIN) {
+		if node.State.Has(slurmapi.NodeStateDrain) {
 			status.Drain++
 		}
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateFAIL) {
+		if node.State.Has(slurmapi.NodeStateFail) {
 			status.Fail++
 		}
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateINVALID) {
+		if node.State.Has(slurmapi.NodeStateInvalid) {
 			status.Invalid++
 		}
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateINVALIDREG) {
+		if node.State.Has(slurmapi.NodeStateInvalidReg) {
 			status.InvalidReg++
 		}
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateMAINTENANCE) {
+		if node.State.Has(slurmapi.NodeStateMaintenance) {
 			status.Maintenance++
 		}
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateNOTRESPONDING) {
+		if node.State.Has(slurmapi.NodeStateNotResponding) {
 			status.NotResponding++
 		}
-		if node.GetStateAsSet().Has(v0041.V0041NodeStateUNDRAIN) {
+		if node.State.Has(slurmapi.NodeStateUndrain) {
 			status.Undrain++
 		}
 	}
@@ -375,15 +444,21 @@ func (r *realSlurmControl) CalculateNodeStatus(ctx context.Context, nodeset *sli
 	return status, nil
 }
 
-const infiniteDuration = time.Duration(math.MaxInt64)
+// isNodeUsable returns true when the Slurm node can run jobs. It is IDLE,
+// MIXED, or ALLOCATED, and not DRAIN, FAIL, MAINTENANCE, or NOT_RESPONDING.
+func isNodeUsable(node slurmapi.Node) bool {
+	return node.State.HasAny(slurmapi.NodeStateIdle, slurmapi.NodeStateMixed, slurmapi.NodeStateAllocated) &&
+		!node.State.HasAny(slurmapi.NodeStateDrain, slurmapi.NodeStateFail,
+			slurmapi.NodeStateMaintenance, slurmapi.NodeStateNotResponding)
+}
 
 // GetNodeDeadlines implements SlurmControlInterface.
 func (r *realSlurmControl) GetNodeDeadlines(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (*timestore.TimeStore, error) {
 	logger := log.FromContext(ctx)
 	ts := timestore.NewTimeStore(timestore.Greater)
 
-	slurmClient := r.lookupClient(nodeset)
-	if slurmClient == nil {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
 		logger.V(2).Info("no client for nodeset, cannot do GetNodeDeadlines()",
 			"nodeset", klog.KObj(nodeset))
 		return ts, nil
@@ -395,50 +470,641 @@ func (r *realSlurmControl) GetNodeDeadlines(ctx context.Context, nodeset *slinky
 		slurmNodeNamesSet.Insert(slurmNodeName)
 	}
 
-	jobList := &slurmtypes.V0041JobInfoList{}
-	if err := slurmClient.List(ctx, jobList); err != nil {
+	jobList, err := slurmAPI.ListJobs(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	for _, job := range jobList.Items {
-		if !job.GetStateAsSet().Has(v0041.V0041JobInfoJobStateRUNNING) {
+	for _, job := range jobList {
+		if !job.IsRunning {
 			continue
 		}
-		slurmNodeNames, err := hostlist.Expand(ptr.Deref(job.Nodes, ""))
+		slurmNodeNames, err := hostlist.Expand(job.Nodes)
 		if err != nil {
 			logger.Error(err, "failed to expand job node hostlist",
-				"job", ptr.Deref(job.JobId, 0))
+				"job", job.ID)
 			return nil, err
 		}
 		if !slurmNodeNamesSet.HasAny(slurmNodeNames...) {
 			continue
 		}
 
-		// Get startTime, when the job was launched on the compute node.
-		startTime_NoVal := ptr.Deref(job.StartTime, v0041.V0041Uint64NoValStruct{})
-		startTime := time.Unix(ptr.Deref(startTime_NoVal.Number, 0), 0)
-		// Get the timeLimit, the wall time of the job.
-		timeLimit_NoVal := ptr.Deref(job.TimeLimit, v0041.V0041Uint32NoValStruct{})
-		timeLimit := time.Duration(ptr.Deref(timeLimit_NoVal.Number, 0)) * time.Minute
-		if ptr.Deref(timeLimit_NoVal.Infinite, false) {
-			timeLimit = infiniteDuration
-		}
-
 		// Push time/duration into the fancy map for each node allocated to the job.
 		for _, slurmNodeName := range slurmNodeNames {
-			ts.Push(slurmNodeName, startTime.Add(timeLimit))
+			ts.Push(slurmNodeName, job.StartTime.Add(job.TimeLimit))
 		}
 	}
 
 	return ts, nil
 }
 
-func (r *realSlurmControl) lookupClient(nodeset *slinkyv1alpha1.NodeSet) slurmclient.Client {
-	clusterName := types.NamespacedName{
-		Namespace: nodeset.GetNamespace(),
-		Name:      nodeset.Spec.ClusterName,
+// PingController implements SlurmControlInterface.
+func (r *realSlurmControl) PingController(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return ErrNoClient
+	}
+
+	pingList, err := slurmAPI.PingControllers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, ping := range pingList {
+		if ping.IsUp {
+			return nil
+		}
+	}
+
+	return ErrControllerDown
+}
+
+// CalculatePendingNodes implements SlurmControlInterface.
+func (r *realSlurmControl) CalculatePendingNodes(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, partition string) (int32, error) {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return 0, ErrNoClient
 	}
-	return r.slurmClusters.Get(clusterName)
+
+	jobList, err := slurmAPI.ListJobs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending int32
+	for _, job := range jobList {
+		if !job.IsPending {
+			continue
+		}
+		if partition != "" && !slices.Contains(job.Partitions, partition) {
+			continue
+		}
+		// A pending job needs at least one node.
+		pending += max(job.NodeCount, 1)
+	}
+
+	return pending, nil
+}
+
+// GetNodeJobs implements SlurmControlInterface.
+func (r *realSlurmControl) GetNodeJobs(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) ([]int32, error) {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return nil, ErrNoClient
+	}
+
+	jobList, err := slurmAPI.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	slurmNodeName := nodesetutils.GetNodeName(pod)
+	jobIDs := []int32{}
+	for _, job := range jobList {
+		if !job.IsRunning {
+			continue
+		}
+		slurmNodeNames, err := hostlist.Expand(job.Nodes)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(slurmNodeNames, slurmNodeName) {
+			jobIDs = append(jobIDs, job.ID)
+		}
+	}
+	slices.Sort(jobIDs)
+
+	return jobIDs, nil
+}
+
+// GetNodeScaleInInfo implements SlurmControlInterface.
+func (r *realSlurmControl) GetNodeScaleInInfo(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]nodesetutils.SlurmNodeInfo, error) {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return nil, ErrNoClient
+	}
+
+	nodeDeadlines, err := r.GetNodeDeadlines(ctx, nodeset, pods)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	podNodeNameSet := set.New[string]()
+	for _, pod := range pods {
+		podNodeName := nodesetutils.GetNodeName(pod)
+		podNodeNameSet.Insert(podNodeName)
+	}
+
+	nodes := make(map[string]nodesetutils.SlurmNodeInfo, len(pods))
+	for _, node := range nodeList {
+		if !podNodeNameSet.Has(node.Name) {
+			continue
+		}
+		nodes[node.Name] = nodesetutils.SlurmNodeInfo{
+			IsAllocated: node.State.HasAny(slurmapi.NodeStateAllocated, slurmapi.NodeStateMixed),
+			AllocCPUs:   node.AllocCPUs,
+			Deadline:    nodeDeadlines.Peek(node.Name),
+		}
+	}
+
+	return nodes, nil
+}
+
+// SetNodeWeights implements SlurmControlInterface.
+func (r *realSlurmControl) SetNodeWeights(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, weights map[string]int32) error {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for nodeset, cannot do SetNodeWeights()",
+			"nodeset", klog.KObj(nodeset))
+		return nil
+	}
+
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
+		if tolerateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	for i := range nodeList {
+		slurmNode := &nodeList[i]
+		weight, ok := weights[slurmNode.Name]
+		if !ok || slurmNode.Weight == weight {
+			continue
+		}
+		logger.V(1).Info("set slurm node weight",
+			"nodeset", klog.KObj(nodeset), "node", slurmNode.Name, "weight", weight)
+		update := slurmapi.NodeUpdate{
+			Weight: ptr.To(weight),
+		}
+		if err := slurmAPI.UpdateNode(ctx, slurmNode, update); err != nil {
+			if tolerateError(err) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetNodeWeights implements SlurmControlInterface.
+func (r *realSlurmControl) GetNodeWeights(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]int32, error) {
+	logger := log.FromContext(ctx)
+	weights := make(map[string]int32)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for nodeset, cannot do GetNodeWeights()",
+			"nodeset", klog.KObj(nodeset))
+		return weights, nil
+	}
+
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	podNodeNameSet := set.New[string]()
+	for _, pod := range pods {
+		podNodeName := nodesetutils.GetNodeName(pod)
+		podNodeNameSet.Insert(podNodeName)
+	}
+
+	for _, node := range nodeList {
+		if !podNodeNameSet.Has(node.Name) {
+			continue
+		}
+		weights[node.Name] = node.Weight
+	}
+
+	return weights, nil
+}
+
+// MakeNodeDown implements SlurmControlInterface.
+func (r *realSlurmControl) MakeNodeDown(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) error {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return ErrNoClient
+	}
+
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
+		if tolerateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	logger.V(1).Info("make slurm node down",
+		"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
+	update := slurmapi.NodeUpdate{
+		State:  []slurmapi.NodeState{slurmapi.NodeStateDown},
+		Reason: ptr.To(nodeReasonPrefix + " " + reason),
+	}
+	if err := slurmAPI.UpdateNode(ctx, slurmNode, update); err != nil {
+		if tolerateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CancelJobs implements SlurmControlInterface.
+func (r *realSlurmControl) CancelJobs(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, jobIDs []int32) error {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return ErrNoClient
+	}
+
+	for _, id := range jobIDs {
+		logger.V(1).Info("cancel slurm job",
+			"nodeset", klog.KObj(nodeset), "job", id)
+		if err := slurmAPI.CancelJob(ctx, id); err != nil {
+			if tolerateError(err) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MakeJobsRequeueable implements SlurmControlInterface.
+func (r *realSlurmControl) MakeJobsRequeueable(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, jobIDs []int32) error {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return ErrNoClient
+	}
+
+	for _, id := range jobIDs {
+		logger.V(1).Info("make slurm job requeueable",
+			"nodeset", klog.KObj(nodeset), "job", id)
+		update := slurmapi.JobUpdate{
+			Requeue: ptr.To(true),
+		}
+		if err := slurmAPI.UpdateJob(ctx, id, update); err != nil {
+			if tolerateError(err) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MakeNodeResume implements SlurmControlInterface.
+func (r *realSlurmControl) MakeNodeResume(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) error {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for nodeset, cannot do MakeNodeResume()",
+			"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
+		return nil
+	}
+
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
+		if tolerateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !slurmNode.State.Has(slurmapi.NodeStateDown) || !strings.Contains(slurmNode.Reason, nodeReasonPrefix) {
+		return nil
+	}
+
+	logger.V(1).Info("make slurm node resume",
+		"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
+	update := slurmapi.NodeUpdate{
+		State:  []slurmapi.NodeState{slurmapi.NodeStateResume},
+		Reason: ptr.To(nodeReasonPrefix + " " + reason),
+	}
+	if err := slurmAPI.UpdateNode(ctx, slurmNode, update); err != nil {
+		if tolerateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetNodeDrainReasons implements SlurmControlInterface.
+func (r *realSlurmControl) GetNodeDrainReasons(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]string, error) {
+	logger := log.FromContext(ctx)
+	reasons := make(map[string]string)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for nodeset, cannot do GetNodeDrainReasons()",
+			"nodeset", klog.KObj(nodeset))
+		return reasons, nil
+	}
+
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	podNodeNameSet := set.New[string]()
+	for _, pod := range pods {
+		podNodeName := nodesetutils.GetNodeName(pod)
+		podNodeNameSet.Insert(podNodeName)
+	}
+
+	for _, node := range nodeList {
+		if !podNodeNameSet.Has(node.Name) || !isDrainedByOther(&node) {
+			continue
+		}
+		reasons[node.Name] = node.Reason
+	}
+
+	return reasons, nil
+}
+
+// GetNodeConditions implements SlurmControlInterface.
+func (r *realSlurmControl) GetNodeConditions(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string][]corev1.PodCondition, error) {
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return nil, ErrNoClient
+	}
+
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
+		// Without the nodes, every pod would be reported as not registered.
+		return nil, err
+	}
+
+	nodes := make(map[string]*slurmapi.Node, len(nodeList))
+	for i := range nodeList {
+		nodes[nodeList[i].Name] = &nodeList[i]
+	}
+
+	conditions := make(map[string][]corev1.PodCondition, len(pods))
+	for _, pod := range pods {
+		podNodeName := nodesetutils.GetNodeName(pod)
+		conditions[podNodeName] = nodeConditions(podNodeName, nodes[podNodeName])
+	}
+
+	return conditions, nil
+}
+
+// nodeConditions returns the pod conditions reflecting the Slurm node, which is
+// nil when not registered.
+func nodeConditions(nodeName string, node *slurmapi.Node) []corev1.PodCondition {
+	if node == nil {
+		message := fmt.Sprintf("Slurm node (%s) is not registered", nodeName)
+		return []corev1.PodCondition{
+			{
+				Type:    slinkyv1alpha1.PodConditionSlurmNodeReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  "NotRegistered",
+				Message: message,
+			},
+			{
+				Type:    slinkyv1alpha1.PodConditionSlurmNodeState,
+				Status:  corev1.ConditionFalse,
+				Reason:  "NotRegistered",
+				Message: message,
+			},
+			{
+				Type:   slinkyv1alpha1.PodConditionSlurmNodeReason,
+				Status: corev1.ConditionFalse,
+			},
+		}
+	}
+
+	state := nodeStateString(node)
+	ready := corev1.PodCondition{
+		Type:    slinkyv1alpha1.PodConditionSlurmNodeReady,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Ready",
+		Message: fmt.Sprintf("Slurm node (%s) is %s", nodeName, state),
+	}
+	if !isNodeReady(node) {
+		ready.Status = corev1.ConditionFalse
+		ready.Reason = "NotReady"
+	}
+	reason := corev1.PodCondition{
+		Type:    slinkyv1alpha1.PodConditionSlurmNodeReason,
+		Status:  corev1.ConditionTrue,
+		Message: node.Reason,
+	}
+	if node.Reason == "" {
+		reason.Status = corev1.ConditionFalse
+	}
+	return []corev1.PodCondition{
+		ready,
+		{
+			Type:    slinkyv1alpha1.PodConditionSlurmNodeState,
+			Status:  corev1.ConditionTrue,
+			Reason:  "Registered",
+			Message: state,
+		},
+		reason,
+	}
+}
+
+// nodeStateString returns the states of the Slurm node joined by "+", e.g.
+// `DRAIN+IDLE`.
+func nodeStateString(node *slurmapi.Node) string {
+	states := make([]string, 0, node.State.Len())
+	for _, state := range node.State.SortedList() {
+		states = append(states, string(state))
+	}
+	return strings.Join(states, "+")
+}
+
+// isNodeReady returns true when the Slurm node is registered and responding. It
+// is IDLE, MIXED, or ALLOCATED, and not INVALID, INVALID_REG, or NOT_RESPONDING.
+// Unlike isNodeUsable, a DRAIN node is ready.
+func isNodeReady(node *slurmapi.Node) bool {
+	return node.State.HasAny(slurmapi.NodeStateIdle, slurmapi.NodeStateMixed, slurmapi.NodeStateAllocated) &&
+		!node.State.HasAny(slurmapi.NodeStateInvalid, slurmapi.NodeStateInvalidReg, slurmapi.NodeStateNotResponding)
+}
+
+// UnhealthyNode is the state of a Slurm node that is DOWN, FAIL, or
+// NOT_RESPONDING.
+type UnhealthyNode struct {
+	// State is the state of the node, e.g. `DOWN+NOT_RESPONDING`.
+	State string
+	// Reason is the reason of the node.
+	Reason string
+}
+
+// GetUnhealthyNodes implements SlurmControlInterface.
+func (r *realSlurmControl) GetUnhealthyNodes(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]UnhealthyNode, error) {
+	logger := log.FromContext(ctx)
+	nodes := make(map[string]UnhealthyNode)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for nodeset, cannot do GetUnhealthyNodes()",
+			"nodeset", klog.KObj(nodeset))
+		return nodes, nil
+	}
+
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	podNodeNameSet := set.New[string]()
+	for _, pod := range pods {
+		podNodeName := nodesetutils.GetNodeName(pod)
+		podNodeNameSet.Insert(podNodeName)
+	}
+
+	slurmUser := getSlurmUser(nodeset)
+	for _, node := range nodeList {
+		if !podNodeNameSet.Has(node.Name) || !isNodeUnhealthy(&node, slurmUser) {
+			continue
+		}
+		nodes[node.Name] = UnhealthyNode{
+			State:  nodeStateString(&node),
+			Reason: node.Reason,
+		}
+	}
+
+	return nodes, nil
+}
+
+// defaultSlurmUser is the `SlurmUser` of the Slurm cluster, unless set on the
+// NodeSet.
+const defaultSlurmUser = "slurm"
+
+// getSlurmUser returns the user slurmctld sets the reasons of the nodes it sets
+// DOWN or FAIL as (e.g. `Not responding`, `Kill task failed`).
+func getSlurmUser(nodeset *slinkyv1alpha1.NodeSet) string {
+	if nodeset.Spec.Remediation == nil || nodeset.Spec.Remediation.SlurmUser == "" {
+		return defaultSlurmUser
+	}
+	return nodeset.Spec.Remediation.SlurmUser
+}
+
+// isNodeUnhealthy returns true when the Slurm node is DOWN, FAIL, or
+// NOT_RESPONDING, and its reason was set by slurm-operator or slurmctld, or is
+// empty. A reason set by someone else (e.g. an admin, usually as root) is left
+// alone.
+func isNodeUnhealthy(slurmNode *slurmapi.Node, slurmUser string) bool {
+	if !slurmNode.State.HasAny(slurmapi.NodeStateDown, slurmapi.NodeStateFail, slurmapi.NodeStateNotResponding) {
+		return false
+	}
+	return slurmNode.Reason == "" ||
+		slurmNode.ReasonSetByUser == slurmUser ||
+		strings.Contains(slurmNode.Reason, nodeReasonPrefix)
+}
+
+// NodeRequest is an action requested on a Slurm node, e.g. by an admin with
+// `scontrol`.
+type NodeRequest string
+
+const (
+	// NodeRequestReboot is requested by `scontrol reboot`, the node is
+	// REBOOT_REQUESTED.
+	NodeRequestReboot NodeRequest = "Reboot"
+	// NodeRequestPowerDown is requested by `scontrol power down`, the node is
+	// POWER_DOWN, POWERING_DOWN, or POWERED_DOWN.
+	NodeRequestPowerDown NodeRequest = "PowerDown"
+)
+
+// GetNodeRequests implements SlurmControlInterface.
+func (r *realSlurmControl) GetNodeRequests(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (map[string]NodeRequest, error) {
+	logger := log.FromContext(ctx)
+	requests := make(map[string]NodeRequest)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		logger.V(2).Info("no client for nodeset, cannot do GetNodeRequests()",
+			"nodeset", klog.KObj(nodeset))
+		return requests, nil
+	}
+
+	nodeList, err := slurmAPI.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	podNodeNameSet := set.New[string]()
+	for _, pod := range pods {
+		podNodeName := nodesetutils.GetNodeName(pod)
+		podNodeNameSet.Insert(podNodeName)
+	}
+
+	for _, node := range nodeList {
+		if !podNodeNameSet.Has(node.Name) {
+			continue
+		}
+		// A power down outlasts the pod, so it takes precedence over a reboot.
+		switch {
+		case node.State.HasAny(slurmapi.NodeStatePowerDown, slurmapi.NodeStatePoweringDown, slurmapi.NodeStatePoweredDown):
+			requests[node.Name] = NodeRequestPowerDown
+		case node.State.Has(slurmapi.NodeStateRebootRequest):
+			requests[node.Name] = NodeRequestReboot
+		}
+	}
+
+	return requests, nil
+}
+
+// CancelNodeReboot implements SlurmControlInterface.
+func (r *realSlurmControl) CancelNodeReboot(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) error {
+	logger := log.FromContext(ctx)
+
+	slurmAPI := r.lookupAPI(nodeset)
+	if slurmAPI == nil {
+		return ErrNoClient
+	}
+
+	slurmNode, err := slurmAPI.GetNode(ctx, nodesetutils.GetNodeName(pod))
+	if err != nil {
+		if tolerateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !slurmNode.State.Has(slurmapi.NodeStateRebootRequest) {
+		return nil
+	}
+
+	logger.V(1).Info("cancel slurm node reboot",
+		"nodeset", klog.KObj(nodeset), "pod", klog.KObj(pod))
+	update := slurmapi.NodeUpdate{
+		State: []slurmapi.NodeState{slurmapi.NodeStateRebootCancel},
+	}
+	if err := slurmAPI.UpdateNode(ctx, slurmNode, update); err != nil {
+		if tolerateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *realSlurmControl) lookupAPI(nodeset *slinkyv1alpha1.NodeSet) slurmapi.Interface {
+	return r.slurmClusters.GetAPI(nodeset.ClusterKey())
 }
 
 var _ SlurmControlInterface = &realSlurmControl{}
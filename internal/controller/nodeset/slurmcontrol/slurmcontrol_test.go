@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/puttsk/hostlist"
@@ -23,6 +24,7 @@ import (
 	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
 	"github.com/SlinkyProject/slurm-client/pkg/client"
 	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
 	"github.com/SlinkyProject/slurm-client/pkg/object"
 	"github.com/SlinkyProject/slurm-client/pkg/types"
 
@@ -156,6 +158,37 @@ var _ = Describe("SlurmControlInterface", func() {
 			isDrain := checkNode.GetStateAsSet().Has(v0041.V0041NodeStateDRAIN)
 			Expect(isDrain).To(BeTrue())
 		})
+
+		It("Should keep the reason of a Slurm node drained by an admin", func() {
+			By("Setup initial system state")
+			nodeset = newNodeSet("foo", clusterName, 1)
+			pod = nodesetutils.NewNodeSetPod(nodeset, 0, "")
+			slurmNodename := nodesetutils.GetNodeName(pod)
+			node := &types.V0041Node{
+				V0041Node: v0041.V0041Node{
+					Name: ptr.To(slurmNodename),
+					State: ptr.To([]v0041.V0041NodeState{
+						v0041.V0041NodeStateIDLE,
+						v0041.V0041NodeStateDRAIN,
+					}),
+					Reason: ptr.To("bad DIMM"),
+				},
+			}
+			sclient = fake.NewClientBuilder().WithUpdateFn(updateFn).WithObjects(node).Build()
+			clusters := newSlurmClusters(clusterName, sclient)
+			slurmcontrol = NewSlurmControl(clusters)
+
+			By("Draining matching Slurm node")
+			err := slurmcontrol.MakeNodeDrain(ctx, nodeset, pod, "drain")
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Check Slurm Node reason")
+			checkNode := &types.V0041Node{}
+			key := object.ObjectKey(slurmNodename)
+			err = sclient.Get(ctx, key, checkNode)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ptr.Deref(checkNode.Reason, "")).To(Equal("bad DIMM"))
+		})
 	})
 
 	Context("MakeNodeUndrain()", func() {
@@ -606,7 +639,8 @@ func Test_realSlurmControl_CalculateNodeStatus(t *testing.T) {
 				},
 			},
 			want: SlurmNodeStatus{
-				Total: 1,
+				Total:  1,
+				Usable: 1,
 
 				Idle: 1,
 			},
@@ -640,7 +674,8 @@ func Test_realSlurmControl_CalculateNodeStatus(t *testing.T) {
 				},
 			},
 			want: SlurmNodeStatus{
-				Total: 1,
+				Total:  1,
+				Usable: 1,
 
 				Idle: 1,
 			},
@@ -764,7 +799,8 @@ func Test_realSlurmControl_CalculateNodeStatus(t *testing.T) {
 				},
 			},
 			want: SlurmNodeStatus{
-				Total: 7,
+				Total:  7,
+				Usable: 3,
 
 				Allocated: 1,
 				Down:      1,
@@ -979,7 +1015,8 @@ func Test_realSlurmControl_CalculateNodeStatus(t *testing.T) {
 				},
 			},
 			want: SlurmNodeStatus{
-				Total: 8,
+				Total:  8,
+				Usable: 1,
 
 				Allocated: 1,
 				Down:      1,
@@ -1017,6 +1054,904 @@ func Test_realSlurmControl_CalculateNodeStatus(t *testing.T) {
 	}
 }
 
+func Test_realSlurmControl_PingController(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 1)
+	newPingList := func(pinged string) *types.V0041ControllerPingList {
+		return &types.V0041ControllerPingList{
+			Items: []types.V0041ControllerPing{
+				{
+					V0041ControllerPing: v0041.V0041ControllerPing{
+						Hostname: ptr.To("slurmctld-0"),
+						Pinged:   ptr.To(pinged),
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		slurmClusters *resources.Clusters
+		wantErr       error
+	}{
+		{
+			name:          "No client",
+			slurmClusters: resources.NewClusters(),
+			wantErr:       ErrNoClient,
+		},
+		{
+			name: "Controller up",
+			slurmClusters: newSlurmClusters(clusterName,
+				fake.NewClientBuilder().WithLists(newPingList(types.V0041ControllerPingPingedUP)).Build()),
+		},
+		{
+			name: "Controller down",
+			slurmClusters: newSlurmClusters(clusterName,
+				fake.NewClientBuilder().WithLists(newPingList(types.V0041ControllerPingPingedDOWN)).Build()),
+			wantErr: ErrControllerDown,
+		},
+		{
+			name: "Request failed",
+			slurmClusters: newSlurmClusters(clusterName,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+						return errors.New(http.StatusText(http.StatusBadGateway))
+					},
+				}).Build()),
+			wantErr: errors.New(http.StatusText(http.StatusBadGateway)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				slurmClusters: tt.slurmClusters,
+			}
+			err := r.PingController(ctx, nodeset)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("realSlurmControl.PingController() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err.Error() != tt.wantErr.Error() {
+				t.Errorf("realSlurmControl.PingController() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_RefreshNodes(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 1)
+	tests := []struct {
+		name        string
+		noClient    bool
+		listErr     error
+		wantRefresh bool
+		wantErr     bool
+	}{
+		{
+			name:     "No client",
+			noClient: true,
+		},
+		{
+			name:        "Refresh",
+			wantRefresh: true,
+		},
+		{
+			name:        "Not found",
+			listErr:     errors.New(http.StatusText(http.StatusNotFound)),
+			wantRefresh: true,
+		},
+		{
+			name:        "Request failed",
+			listErr:     errors.New(http.StatusText(http.StatusBadGateway)),
+			wantRefresh: true,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshed := false
+			slurmClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+					options := &client.ListOptions{}
+					refreshed = options.ApplyOptions(opts).RefreshCache
+					return tt.listErr
+				},
+			}).Build()
+			r := &realSlurmControl{
+				slurmClusters: newSlurmClusters(clusterName, slurmClient),
+			}
+			if tt.noClient {
+				r.slurmClusters = resources.NewClusters()
+			}
+			if err := r.RefreshNodes(ctx, nodeset); (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.RefreshNodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if refreshed != tt.wantRefresh {
+				t.Errorf("realSlurmControl.RefreshNodes() refreshed = %v, want %v", refreshed, tt.wantRefresh)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_CalculatePendingNodes(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 1)
+	newJob := func(id int32, state v0041.V0041JobInfoJobState, partition string, nodeCount int32) types.V0041JobInfo {
+		return types.V0041JobInfo{
+			V0041JobInfo: v0041.V0041JobInfo{
+				JobId:     ptr.To(id),
+				JobState:  ptr.To([]v0041.V0041JobInfoJobState{state}),
+				Partition: ptr.To(partition),
+				NodeCount: &v0041.V0041Uint32NoValStruct{Number: ptr.To(nodeCount)},
+			},
+		}
+	}
+	jobList := &types.V0041JobInfoList{
+		Items: []types.V0041JobInfo{
+			newJob(1, v0041.V0041JobInfoJobStatePENDING, "debug", 2),
+			newJob(2, v0041.V0041JobInfoJobStatePENDING, "debug,gpu", 0),
+			newJob(3, v0041.V0041JobInfoJobStatePENDING, "gpu", 4),
+			newJob(4, v0041.V0041JobInfoJobStateRUNNING, "debug", 8),
+		},
+	}
+	tests := []struct {
+		name          string
+		slurmClusters *resources.Clusters
+		partition     string
+		want          int32
+		wantErr       bool
+	}{
+		{
+			name:          "No client",
+			slurmClusters: resources.NewClusters(),
+			wantErr:       true,
+		},
+		{
+			name:          "All partitions",
+			slurmClusters: newSlurmClusters(clusterName, fake.NewClientBuilder().WithLists(jobList).Build()),
+			want:          7,
+		},
+		{
+			name:          "Partition",
+			slurmClusters: newSlurmClusters(clusterName, fake.NewClientBuilder().WithLists(jobList).Build()),
+			partition:     "debug",
+			want:          3,
+		},
+		{
+			name:          "No pending jobs",
+			slurmClusters: newSlurmClusters(clusterName, fake.NewClientBuilder().WithLists(jobList).Build()),
+			partition:     "other",
+			want:          0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				slurmClusters: tt.slurmClusters,
+			}
+			got, err := r.CalculatePendingNodes(ctx, nodeset, tt.partition)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("realSlurmControl.CalculatePendingNodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("realSlurmControl.CalculatePendingNodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetNodeJobs(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 2)
+	pod := nodesetutils.NewNodeSetPod(nodeset, 0, "")
+	nodeName := nodesetutils.GetNodeName(pod)
+	newJob := func(id int32, state v0041.V0041JobInfoJobState, nodes string) types.V0041JobInfo {
+		return types.V0041JobInfo{
+			V0041JobInfo: v0041.V0041JobInfo{
+				JobId:    ptr.To(id),
+				JobState: ptr.To([]v0041.V0041JobInfoJobState{state}),
+				Nodes:    ptr.To(nodes),
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		slurmClusters *resources.Clusters
+		want          []int32
+		wantErr       bool
+	}{
+		{
+			name:          "No client",
+			slurmClusters: resources.NewClusters(),
+			wantErr:       true,
+		},
+		{
+			name: "Running jobs",
+			slurmClusters: newSlurmClusters(clusterName, fake.NewClientBuilder().WithLists(&types.V0041JobInfoList{
+				Items: []types.V0041JobInfo{
+					newJob(3, v0041.V0041JobInfoJobStateRUNNING, nodeName),
+					newJob(1, v0041.V0041JobInfoJobStateRUNNING, "foo-[0-1]"),
+					newJob(2, v0041.V0041JobInfoJobStateRUNNING, "foo-1"),
+					newJob(4, v0041.V0041JobInfoJobStatePENDING, ""),
+				},
+			}).Build()),
+			want: []int32{1, 3},
+		},
+		{
+			name:          "No jobs",
+			slurmClusters: newSlurmClusters(clusterName, fake.NewClientBuilder().Build()),
+			want:          []int32{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				slurmClusters: tt.slurmClusters,
+			}
+			got, err := r.GetNodeJobs(ctx, nodeset, pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("realSlurmControl.GetNodeJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("realSlurmControl.GetNodeJobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetNodeScaleInInfo(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 2)
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 2, ""),
+	}
+	newNode := func(name string, state v0041.V0041NodeState, allocCPUs int32) types.V0041Node {
+		return types.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name:      ptr.To(name),
+				State:     ptr.To([]v0041.V0041NodeState{state}),
+				AllocCpus: ptr.To(allocCPUs),
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		slurmClusters *resources.Clusters
+		want          map[string]nodesetutils.SlurmNodeInfo
+		wantErr       bool
+	}{
+		{
+			name:          "No client",
+			slurmClusters: resources.NewClusters(),
+			wantErr:       true,
+		},
+		{
+			name: "Nodes",
+			slurmClusters: newSlurmClusters(clusterName, fake.NewClientBuilder().WithLists(
+				&types.V0041NodeList{
+					Items: []types.V0041Node{
+						newNode("foo-0", v0041.V0041NodeStateALLOCATED, 8),
+						newNode("foo-1", v0041.V0041NodeStateMIXED, 2),
+						newNode("foo-2", v0041.V0041NodeStateIDLE, 0),
+						newNode("bar-0", v0041.V0041NodeStateALLOCATED, 8),
+					},
+				},
+				&types.V0041JobInfoList{
+					Items: []types.V0041JobInfo{
+						{
+							V0041JobInfo: v0041.V0041JobInfo{
+								JobId:     ptr.To[int32](1),
+								JobState:  ptr.To([]v0041.V0041JobInfoJobState{v0041.V0041JobInfoJobStateRUNNING}),
+								Nodes:     ptr.To("foo-[0-1]"),
+								StartTime: &v0041.V0041Uint64NoValStruct{Number: ptr.To[int64](100)},
+								TimeLimit: &v0041.V0041Uint32NoValStruct{Number: ptr.To[int32](10)},
+							},
+						},
+						{
+							V0041JobInfo: v0041.V0041JobInfo{
+								JobId:     ptr.To[int32](2),
+								JobState:  ptr.To([]v0041.V0041JobInfoJobState{v0041.V0041JobInfoJobStateRUNNING}),
+								Nodes:     ptr.To("foo-0"),
+								StartTime: &v0041.V0041Uint64NoValStruct{Number: ptr.To[int64](100)},
+								TimeLimit: &v0041.V0041Uint32NoValStruct{Number: ptr.To[int32](20)},
+							},
+						},
+					},
+				},
+			).Build()),
+			want: map[string]nodesetutils.SlurmNodeInfo{
+				"foo-0": {IsAllocated: true, AllocCPUs: 8, Deadline: time.Unix(100, 0).Add(20 * time.Minute)},
+				"foo-1": {IsAllocated: true, AllocCPUs: 2, Deadline: time.Unix(100, 0).Add(10 * time.Minute)},
+				"foo-2": {},
+			},
+		},
+		{
+			name:          "No nodes",
+			slurmClusters: newSlurmClusters(clusterName, fake.NewClientBuilder().Build()),
+			want:          map[string]nodesetutils.SlurmNodeInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				slurmClusters: tt.slurmClusters,
+			}
+			got, err := r.GetNodeScaleInInfo(ctx, nodeset, pods)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("realSlurmControl.GetNodeScaleInInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("realSlurmControl.GetNodeScaleInInfo() (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_MakeNodeDown(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 1)
+	pod := nodesetutils.NewNodeSetPod(nodeset, 0, "")
+	node := &types.V0041Node{
+		V0041Node: v0041.V0041Node{
+			Name:  ptr.To(nodesetutils.GetNodeName(pod)),
+			State: ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateALLOCATED, v0041.V0041NodeStateDRAIN}),
+		},
+	}
+	var gotState []v0041.V0041UpdateNodeMsgState
+	slurmClient := fake.NewClientBuilder().
+		WithObjects(node).
+		WithUpdateFn(func(_ context.Context, _ object.Object, req any, _ ...client.UpdateOption) error {
+			gotState = ptr.Deref(req.(v0041.V0041UpdateNodeMsg).State, nil)
+			return nil
+		}).
+		Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	if err := r.MakeNodeDown(ctx, nodeset, pod, "drain timeout"); err != nil {
+		t.Fatalf("realSlurmControl.MakeNodeDown() error = %v", err)
+	}
+	wantState := []v0041.V0041UpdateNodeMsgState{v0041.V0041UpdateNodeMsgStateDOWN}
+	if !apiequality.Semantic.DeepEqual(gotState, wantState) {
+		t.Errorf("realSlurmControl.MakeNodeDown() state = %v, want %v", gotState, wantState)
+	}
+}
+
+func Test_realSlurmControl_SetNodeWeights(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 3)
+	newNode := func(name string, weight int32) types.V0041Node {
+		return types.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name:   ptr.To(name),
+				Weight: ptr.To(weight),
+			},
+		}
+	}
+	nodeList := &types.V0041NodeList{
+		Items: []types.V0041Node{
+			newNode("foo-0", 1),
+			newNode("foo-1", 1),
+			newNode("foo-2", 3),
+			newNode("bar-0", 1),
+		},
+	}
+	gotWeights := map[string]int32{}
+	slurmClient := fake.NewClientBuilder().
+		WithLists(nodeList).
+		WithUpdateFn(func(_ context.Context, obj object.Object, req any, _ ...client.UpdateOption) error {
+			weight := req.(v0041.V0041UpdateNodeMsg).Weight
+			gotWeights[string(obj.GetKey())] = ptr.Deref(weight.Number, 0)
+			return nil
+		}).
+		Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	weights := map[string]int32{
+		"foo-0": 1,
+		"foo-1": 2,
+		"foo-2": 3,
+		"foo-3": 4,
+	}
+	if err := r.SetNodeWeights(ctx, nodeset, weights); err != nil {
+		t.Fatalf("realSlurmControl.SetNodeWeights() error = %v", err)
+	}
+	wantWeights := map[string]int32{"foo-1": 2}
+	if !apiequality.Semantic.DeepEqual(gotWeights, wantWeights) {
+		t.Errorf("realSlurmControl.SetNodeWeights() updated = %v, want %v", gotWeights, wantWeights)
+	}
+
+	r = &realSlurmControl{
+		slurmClusters: resources.NewClusters(),
+	}
+	if err := r.SetNodeWeights(ctx, nodeset, weights); err != nil {
+		t.Errorf("realSlurmControl.SetNodeWeights() without client error = %v", err)
+	}
+}
+
+func Test_realSlurmControl_GetNodeWeights(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 3)
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 2, ""),
+	}
+	newNode := func(name string, weight int32) types.V0041Node {
+		return types.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name:   ptr.To(name),
+				Weight: ptr.To(weight),
+			},
+		}
+	}
+	nodeList := &types.V0041NodeList{
+		Items: []types.V0041Node{
+			newNode("foo-0", 1),
+			newNode("foo-1", 10),
+			newNode("bar-0", 1),
+		},
+	}
+	slurmClient := fake.NewClientBuilder().WithLists(nodeList).Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	got, err := r.GetNodeWeights(ctx, nodeset, pods)
+	if err != nil {
+		t.Fatalf("realSlurmControl.GetNodeWeights() error = %v", err)
+	}
+	want := map[string]int32{"foo-0": 1, "foo-1": 10}
+	if !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("realSlurmControl.GetNodeWeights() = %v, want %v", got, want)
+	}
+
+	r = &realSlurmControl{
+		slurmClusters: resources.NewClusters(),
+	}
+	got, err = r.GetNodeWeights(ctx, nodeset, pods)
+	if err != nil || len(got) != 0 {
+		t.Errorf("realSlurmControl.GetNodeWeights() without client = %v, %v", got, err)
+	}
+}
+
+func Test_realSlurmControl_CancelJobs(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 1)
+	jobList := &types.V0041JobInfoList{
+		Items: []types.V0041JobInfo{
+			{V0041JobInfo: v0041.V0041JobInfo{JobId: ptr.To[int32](1)}},
+			{V0041JobInfo: v0041.V0041JobInfo{JobId: ptr.To[int32](2)}},
+		},
+	}
+	slurmClient := fake.NewClientBuilder().WithLists(jobList).Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	// Job 3 does not exist, which is tolerated.
+	if err := r.CancelJobs(ctx, nodeset, []int32{1, 3}); err != nil {
+		t.Fatalf("realSlurmControl.CancelJobs() error = %v", err)
+	}
+	got := &types.V0041JobInfoList{}
+	if err := slurmClient.List(ctx, got); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got.Items) != 1 || ptr.Deref(got.Items[0].JobId, 0) != 2 {
+		t.Errorf("realSlurmControl.CancelJobs() jobs left = %+v, want job 2", got.Items)
+	}
+}
+
+func Test_realSlurmControl_MakeJobsRequeueable(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 1)
+	jobList := &types.V0041JobInfoList{
+		Items: []types.V0041JobInfo{
+			{V0041JobInfo: v0041.V0041JobInfo{JobId: ptr.To[int32](1)}},
+			{V0041JobInfo: v0041.V0041JobInfo{JobId: ptr.To[int32](2)}},
+		},
+	}
+	gotRequeue := map[string]bool{}
+	slurmClient := fake.NewClientBuilder().
+		WithLists(jobList).
+		WithUpdateFn(func(_ context.Context, obj object.Object, req any, _ ...client.UpdateOption) error {
+			gotRequeue[string(obj.GetKey())] = ptr.Deref(req.(v0041.V0041JobDescMsg).Requeue, false)
+			return nil
+		}).
+		Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	// Job 3 does not exist, which is tolerated.
+	if err := r.MakeJobsRequeueable(ctx, nodeset, []int32{1, 3}); err != nil {
+		t.Fatalf("realSlurmControl.MakeJobsRequeueable() error = %v", err)
+	}
+	wantRequeue := map[string]bool{"1": true}
+	if !apiequality.Semantic.DeepEqual(gotRequeue, wantRequeue) {
+		t.Errorf("realSlurmControl.MakeJobsRequeueable() requeue = %v, want %v", gotRequeue, wantRequeue)
+	}
+}
+
+func Test_realSlurmControl_MakeNodeResume(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 1)
+	pod := nodesetutils.NewNodeSetPod(nodeset, 0, "")
+	tests := []struct {
+		name      string
+		state     []v0041.V0041NodeState
+		reason    string
+		wantState []v0041.V0041UpdateNodeMsgState
+	}{
+		{
+			name:      "Down by slurm-operator",
+			state:     []v0041.V0041NodeState{v0041.V0041NodeStateDOWN},
+			reason:    nodeReasonPrefix + " Pod (default/foo-0) was deleted",
+			wantState: []v0041.V0041UpdateNodeMsgState{v0041.V0041UpdateNodeMsgStateRESUME},
+		},
+		{
+			name:   "Down by other",
+			state:  []v0041.V0041NodeState{v0041.V0041NodeStateDOWN},
+			reason: "maintenance",
+		},
+		{
+			name:   "Idle",
+			state:  []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+			reason: nodeReasonPrefix + " Pod (default/foo-0) is uncordoned",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &types.V0041Node{
+				V0041Node: v0041.V0041Node{
+					Name:   ptr.To(nodesetutils.GetNodeName(pod)),
+					State:  ptr.To(tt.state),
+					Reason: ptr.To(tt.reason),
+				},
+			}
+			var gotState []v0041.V0041UpdateNodeMsgState
+			slurmClient := fake.NewClientBuilder().
+				WithObjects(node).
+				WithUpdateFn(func(_ context.Context, _ object.Object, req any, _ ...client.UpdateOption) error {
+					gotState = ptr.Deref(req.(v0041.V0041UpdateNodeMsg).State, nil)
+					return nil
+				}).
+				Build()
+
+			r := &realSlurmControl{
+				slurmClusters: newSlurmClusters(clusterName, slurmClient),
+			}
+			if err := r.MakeNodeResume(ctx, nodeset, pod, "Pod (default/foo-0) is running"); err != nil {
+				t.Fatalf("realSlurmControl.MakeNodeResume() error = %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(gotState, tt.wantState) {
+				t.Errorf("realSlurmControl.MakeNodeResume() state = %v, want %v", gotState, tt.wantState)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetNodeDrainReasons(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 4)
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 2, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 3, ""),
+	}
+	newNode := func(name, reason string, state ...v0041.V0041NodeState) types.V0041Node {
+		return types.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name:   ptr.To(name),
+				State:  ptr.To(state),
+				Reason: ptr.To(reason),
+			},
+		}
+	}
+	nodeList := &types.V0041NodeList{
+		Items: []types.V0041Node{
+			newNode("foo-0", "bad DIMM", v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN),
+			newNode("foo-1", nodeReasonPrefix+" Pod (default/foo-1) is cordoned", v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN),
+			newNode("foo-2", "", v0041.V0041NodeStateIDLE),
+			newNode("foo-3", "not responding", v0041.V0041NodeStateDOWN),
+			newNode("bar-0", "bad DIMM", v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN),
+		},
+	}
+	slurmClient := fake.NewClientBuilder().WithLists(nodeList).Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	got, err := r.GetNodeDrainReasons(ctx, nodeset, pods)
+	if err != nil {
+		t.Fatalf("realSlurmControl.GetNodeDrainReasons() error = %v", err)
+	}
+	want := map[string]string{"foo-0": "bad DIMM"}
+	if !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("realSlurmControl.GetNodeDrainReasons() = %v, want %v", got, want)
+	}
+
+	r = &realSlurmControl{
+		slurmClusters: resources.NewClusters(),
+	}
+	got, err = r.GetNodeDrainReasons(ctx, nodeset, pods)
+	if err != nil || len(got) != 0 {
+		t.Errorf("realSlurmControl.GetNodeDrainReasons() without client = %v, %v", got, err)
+	}
+}
+
+func Test_realSlurmControl_GetNodeConditions(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 4)
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 2, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 3, ""),
+	}
+	newNode := func(name, reason string, state ...v0041.V0041NodeState) types.V0041Node {
+		return types.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name:   ptr.To(name),
+				State:  ptr.To(state),
+				Reason: ptr.To(reason),
+			},
+		}
+	}
+	nodeList := &types.V0041NodeList{
+		Items: []types.V0041Node{
+			newNode("foo-0", "", v0041.V0041NodeStateIDLE),
+			newNode("foo-1", "bad DIMM", v0041.V0041NodeStateMIXED, v0041.V0041NodeStateDRAIN),
+			newNode("foo-2", "Not responding", v0041.V0041NodeStateDOWN, v0041.V0041NodeStateNOTRESPONDING),
+		},
+	}
+	slurmClient := fake.NewClientBuilder().WithLists(nodeList).Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	got, err := r.GetNodeConditions(ctx, nodeset, pods)
+	if err != nil {
+		t.Fatalf("realSlurmControl.GetNodeConditions() error = %v", err)
+	}
+	want := map[string][]corev1.PodCondition{
+		"foo-0": {
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReady, Status: corev1.ConditionTrue, Reason: "Ready", Message: "Slurm node (foo-0) is IDLE"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeState, Status: corev1.ConditionTrue, Reason: "Registered", Message: "IDLE"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReason, Status: corev1.ConditionFalse},
+		},
+		"foo-1": {
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReady, Status: corev1.ConditionTrue, Reason: "Ready", Message: "Slurm node (foo-1) is DRAIN+MIXED"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeState, Status: corev1.ConditionTrue, Reason: "Registered", Message: "DRAIN+MIXED"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReason, Status: corev1.ConditionTrue, Message: "bad DIMM"},
+		},
+		"foo-2": {
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReady, Status: corev1.ConditionFalse, Reason: "NotReady", Message: "Slurm node (foo-2) is DOWN+NOT_RESPONDING"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeState, Status: corev1.ConditionTrue, Reason: "Registered", Message: "DOWN+NOT_RESPONDING"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReason, Status: corev1.ConditionTrue, Message: "Not responding"},
+		},
+		"foo-3": {
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReady, Status: corev1.ConditionFalse, Reason: "NotRegistered", Message: "Slurm node (foo-3) is not registered"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeState, Status: corev1.ConditionFalse, Reason: "NotRegistered", Message: "Slurm node (foo-3) is not registered"},
+			{Type: slinkyv1alpha1.PodConditionSlurmNodeReason, Status: corev1.ConditionFalse},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("realSlurmControl.GetNodeConditions() (-want,+got):\n%s", diff)
+	}
+
+	r = &realSlurmControl{
+		slurmClusters: resources.NewClusters(),
+	}
+	if _, err := r.GetNodeConditions(ctx, nodeset, pods); !errors.Is(err, ErrNoClient) {
+		t.Errorf("realSlurmControl.GetNodeConditions() without client error = %v, want %v", err, ErrNoClient)
+	}
+
+	slurmClient = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+			return errors.New(http.StatusText(http.StatusNotFound))
+		},
+	}).Build()
+	r = &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	if got, err := r.GetNodeConditions(ctx, nodeset, pods); err == nil {
+		t.Errorf("realSlurmControl.GetNodeConditions() with list error = %v, want error", got)
+	}
+}
+
+func Test_realSlurmControl_GetUnhealthyNodes(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	newNode := func(name, reason, user string, state ...v0041.V0041NodeState) types.V0041Node {
+		return types.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name:            ptr.To(name),
+				State:           ptr.To(state),
+				Reason:          ptr.To(reason),
+				ReasonSetByUser: ptr.To(user),
+			},
+		}
+	}
+	nodeList := &types.V0041NodeList{
+		Items: []types.V0041Node{
+			newNode("foo-0", "Not responding", "slurm", v0041.V0041NodeStateDOWN, v0041.V0041NodeStateNOTRESPONDING),
+			newNode("foo-1", "", "", v0041.V0041NodeStateIDLE, v0041.V0041NodeStateFAIL),
+			newNode("foo-2", nodeReasonPrefix+" Pod (default/foo-2) is remediated", "operator", v0041.V0041NodeStateDOWN),
+			newNode("foo-3", "bad PSU", "alice", v0041.V0041NodeStateDOWN),
+			newNode("foo-4", "", "", v0041.V0041NodeStateIDLE),
+			newNode("foo-5", "Kill task failed", "slurm", v0041.V0041NodeStateDRAIN, v0041.V0041NodeStateDOWN),
+			newNode("foo-6", "bad DIMM", "root", v0041.V0041NodeStateDRAIN, v0041.V0041NodeStateDOWN),
+			newNode("foo-7", "Prolog error", "slurmctld", v0041.V0041NodeStateDRAIN, v0041.V0041NodeStateDOWN),
+			newNode("bar-0", "Not responding", "slurm", v0041.V0041NodeStateDOWN, v0041.V0041NodeStateNOTRESPONDING),
+		},
+	}
+	tests := []struct {
+		name      string
+		slurmUser string
+		want      map[string]UnhealthyNode
+	}{
+		{
+			name: "Default SlurmUser",
+			want: map[string]UnhealthyNode{
+				"foo-0": {State: "DOWN+NOT_RESPONDING", Reason: "Not responding"},
+				"foo-1": {State: "FAIL+IDLE"},
+				"foo-2": {State: "DOWN", Reason: nodeReasonPrefix + " Pod (default/foo-2) is remediated"},
+				"foo-5": {State: "DOWN+DRAIN", Reason: "Kill task failed"},
+			},
+		},
+		{
+			name:      "Configured SlurmUser",
+			slurmUser: "slurmctld",
+			want: map[string]UnhealthyNode{
+				"foo-1": {State: "FAIL+IDLE"},
+				"foo-2": {State: "DOWN", Reason: nodeReasonPrefix + " Pod (default/foo-2) is remediated"},
+				"foo-7": {State: "DOWN+DRAIN", Reason: "Prolog error"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 8)
+			nodeset.Spec.Remediation = &slinkyv1alpha1.NodeSetRemediation{
+				SlurmUser: tt.slurmUser,
+			}
+			pods := make([]*corev1.Pod, 0, 8)
+			for i := range 8 {
+				pods = append(pods, nodesetutils.NewNodeSetPod(nodeset, i, ""))
+			}
+			slurmClient := fake.NewClientBuilder().WithLists(nodeList).Build()
+
+			r := &realSlurmControl{
+				slurmClusters: newSlurmClusters(clusterName, slurmClient),
+			}
+			got, err := r.GetUnhealthyNodes(ctx, nodeset, pods)
+			if err != nil {
+				t.Fatalf("realSlurmControl.GetUnhealthyNodes() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("realSlurmControl.GetUnhealthyNodes() (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetNodeRequests(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	nodeset := newNodeSet("foo", clusterName, 4)
+	pods := []*corev1.Pod{
+		nodesetutils.NewNodeSetPod(nodeset, 0, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 1, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 2, ""),
+		nodesetutils.NewNodeSetPod(nodeset, 3, ""),
+	}
+	newNode := func(name string, state ...v0041.V0041NodeState) types.V0041Node {
+		return types.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name:  ptr.To(name),
+				State: ptr.To(state),
+			},
+		}
+	}
+	nodeList := &types.V0041NodeList{
+		Items: []types.V0041Node{
+			newNode("foo-0", v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN, v0041.V0041NodeStateREBOOTREQUESTED),
+			newNode("foo-1", v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWERDOWN),
+			newNode("foo-2", v0041.V0041NodeStateIDLE, v0041.V0041NodeStatePOWEREDDOWN, v0041.V0041NodeStateREBOOTREQUESTED),
+			newNode("foo-3", v0041.V0041NodeStateALLOCATED),
+			newNode("bar-0", v0041.V0041NodeStateIDLE, v0041.V0041NodeStateREBOOTREQUESTED),
+		},
+	}
+	slurmClient := fake.NewClientBuilder().WithLists(nodeList).Build()
+
+	r := &realSlurmControl{
+		slurmClusters: newSlurmClusters(clusterName, slurmClient),
+	}
+	got, err := r.GetNodeRequests(ctx, nodeset, pods)
+	if err != nil {
+		t.Fatalf("realSlurmControl.GetNodeRequests() error = %v", err)
+	}
+	want := map[string]NodeRequest{
+		"foo-0": NodeRequestReboot,
+		"foo-1": NodeRequestPowerDown,
+		"foo-2": NodeRequestPowerDown,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("realSlurmControl.GetNodeRequests() (-want,+got):\n%s", diff)
+	}
+}
+
+func Test_realSlurmControl_CancelNodeReboot(t *testing.T) {
+	ctx := context.Background()
+	const clusterName string = "slurm"
+	tests := []struct {
+		name      string
+		state     []v0041.V0041NodeState
+		wantState []v0041.V0041UpdateNodeMsgState
+	}{
+		{
+			name:      "Reboot requested",
+			state:     []v0041.V0041NodeState{v0041.V0041NodeStateDOWN, v0041.V0041NodeStateREBOOTREQUESTED},
+			wantState: []v0041.V0041UpdateNodeMsgState{v0041.V0041UpdateNodeMsgStateREBOOTCANCELED},
+		},
+		{
+			name:      "No reboot requested",
+			state:     []v0041.V0041NodeState{v0041.V0041NodeStateIDLE},
+			wantState: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo", clusterName, 1)
+			pod := nodesetutils.NewNodeSetPod(nodeset, 0, "")
+			node := &types.V0041Node{
+				V0041Node: v0041.V0041Node{
+					Name:  ptr.To(nodesetutils.GetNodeName(pod)),
+					State: ptr.To(tt.state),
+				},
+			}
+			var gotState []v0041.V0041UpdateNodeMsgState
+			slurmClient := fake.NewClientBuilder().
+				WithObjects(node).
+				WithUpdateFn(func(_ context.Context, _ object.Object, req any, _ ...client.UpdateOption) error {
+					gotState = ptr.Deref(req.(v0041.V0041UpdateNodeMsg).State, nil)
+					return nil
+				}).
+				Build()
+
+			r := &realSlurmControl{
+				slurmClusters: newSlurmClusters(clusterName, slurmClient),
+			}
+			if err := r.CancelNodeReboot(ctx, nodeset, pod); err != nil {
+				t.Fatalf("realSlurmControl.CancelNodeReboot() error = %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(gotState, tt.wantState) {
+				t.Errorf("realSlurmControl.CancelNodeReboot() state = %v, want %v", gotState, tt.wantState)
+			}
+		})
+	}
+}
+
 func Test_tolerateError(t *testing.T) {
 	type args struct {
 		err error
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+// IsPowerSave returns true if Slurm power saving decides which pods of the nodeset run.
+func IsPowerSave(nodeset *slinkyv1alpha1.NodeSet) bool {
+	return nodeset.Spec.PowerSave != nil && nodeset.Spec.PowerSave.Enabled
+}
+
+// GetResumedOrdinals returns the ordinals of the pods that Slurm power saving has resumed. Malformed entries are
+// ignored.
+func GetResumedOrdinals(nodeset *slinkyv1alpha1.NodeSet) set.Set[int] {
+	ordinals := set.New[int]()
+	value := nodeset.GetAnnotations()[slinkyv1alpha1.AnnotationPowerSaveResumed]
+	for _, field := range strings.Split(value, ",") {
+		ordinal, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || ordinal < 0 {
+			continue
+		}
+		ordinals.Insert(ordinal)
+	}
+	return ordinals
+}
+
+// SetResumedOrdinals records the ordinals of the pods that Slurm power saving has resumed.
+func SetResumedOrdinals(nodeset *slinkyv1alpha1.NodeSet, ordinals set.Set[int]) {
+	if nodeset.Annotations == nil {
+		nodeset.Annotations = make(map[string]string)
+	}
+	fields := make([]string, 0, ordinals.Len())
+	for _, ordinal := range ordinals.SortedList() {
+		fields = append(fields, strconv.Itoa(ordinal))
+	}
+	nodeset.Annotations[slinkyv1alpha1.AnnotationPowerSaveResumed] = strings.Join(fields, ",")
+}
+
+// nodeOrdinalRegex is a regular expression that extracts the ordinal from the name of a Slurm node.
+var nodeOrdinalRegex = regexp.MustCompile("([0-9]+)$")
+
+// GetNodeOrdinal gets the ordinal of the nodeset pod whose Slurm node has the name. If no pod of the nodeset within
+// its replicas would have that name, -1 is returned.
+func GetNodeOrdinal(nodeset *slinkyv1alpha1.NodeSet, nodeName string) int {
+	subMatches := nodeOrdinalRegex.FindStringSubmatch(nodeName)
+	if len(subMatches) < 2 {
+		return -1
+	}
+	ordinal, err := strconv.Atoi(subMatches[1])
+	if err != nil || ordinal >= int(ptr.Deref(nodeset.Spec.Replicas, 1)) {
+		return -1
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: GetPodName(nodeset, ordinal),
+		},
+		Spec: corev1.PodSpec{
+			Hostname: nodeset.Spec.Template.Spec.Hostname,
+		},
+	}
+	initIdentity(nodeset, pod)
+	if GetNodeName(pod) != nodeName {
+		return -1
+	}
+	return ordinal
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/utils/set"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+func TestGetResumedOrdinals(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  set.Set[int]
+	}{
+		{
+			name:  "Empty",
+			value: "",
+			want:  set.New[int](),
+		},
+		{
+			name:  "Ordinals",
+			value: "0,2, 5",
+			want:  set.New(0, 2, 5),
+		},
+		{
+			name:  "Malformed",
+			value: "1,foo,-1",
+			want:  set.New(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := newNodeSet("foo")
+			nodeset.Annotations = map[string]string{
+				slinkyv1alpha1.AnnotationPowerSaveResumed: tt.value,
+			}
+			if got := GetResumedOrdinals(nodeset); !got.Equal(tt.want) {
+				t.Errorf("GetResumedOrdinals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetResumedOrdinals(t *testing.T) {
+	nodeset := newNodeSet("foo")
+	SetResumedOrdinals(nodeset, set.New(5, 0, 2))
+	if got := nodeset.Annotations[slinkyv1alpha1.AnnotationPowerSaveResumed]; got != "0,2,5" {
+		t.Errorf("SetResumedOrdinals() = %v, want %v", got, "0,2,5")
+	}
+}
+
+func TestGetNodeOrdinal(t *testing.T) {
+	withHostname := newNodeSet("foo")
+	withHostname.Spec.Template.Spec.Hostname = "compute"
+	tests := []struct {
+		name     string
+		nodeset  *slinkyv1alpha1.NodeSet
+		nodeName string
+		want     int
+	}{
+		{
+			name:     "Pod name",
+			nodeset:  newNodeSet("foo"),
+			nodeName: "foo-0",
+			want:     0,
+		},
+		{
+			name:     "Hostname",
+			nodeset:  withHostname,
+			nodeName: "compute0",
+			want:     0,
+		},
+		{
+			name:     "Other nodeset",
+			nodeset:  newNodeSet("foo"),
+			nodeName: "bar-0",
+			want:     -1,
+		},
+		{
+			name:     "Beyond replicas",
+			nodeset:  newNodeSet("foo"),
+			nodeName: "foo-1",
+			want:     -1,
+		},
+		{
+			name:     "No ordinal",
+			nodeset:  newNodeSet("foo"),
+			nodeName: "foo",
+			want:     -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetNodeOrdinal(tt.nodeset, tt.nodeName); got != tt.want {
+				t.Errorf("GetNodeOrdinal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPowerSave(t *testing.T) {
+	nodeset := newNodeSet("foo")
+	if IsPowerSave(nodeset) {
+		t.Errorf("IsPowerSave() = true, want false")
+	}
+	nodeset.Spec.PowerSave = &slinkyv1alpha1.NodeSetPowerSave{Enabled: true}
+	if !IsPowerSave(nodeset) {
+		t.Errorf("IsPowerSave() = false, want true")
+	}
+}
@@ -5,6 +5,7 @@
 package utils
 
 import (
+	"cmp"
 	"sort"
 	"time"
 
@@ -32,6 +33,12 @@ func (o ActivePods) Less(i, j int) bool {
 	pod1 := o[i]
 	pod2 := o[j]
 
+	// Step: powered down in Slurm < not powered down in Slurm
+	// The NodeSet was scaled-in for them, so they are condemned first.
+	if utils.IsPodSlurmPowerDown(pod1) != utils.IsPodSlurmPowerDown(pod2) {
+		return utils.IsPodSlurmPowerDown(pod1)
+	}
+
 	// Step: unassigned < assigned
 	// If only one of the pods is unassigned, the unassigned one is smaller
 	if pod1.Spec.NodeName != pod2.Spec.NodeName && (len(pod1.Spec.NodeName) == 0 || len(pod2.Spec.NodeName) == 0) {
@@ -51,8 +58,8 @@ func (o ActivePods) Less(i, j int) bool {
 	}
 
 	// Step: lower pod-deletion-cost < higher pod-deletion-cost
-	podDeletionCost1, _ := utils.GetNumberFromAnnotations(pod1.Annotations, slinkyv1alpha1.AnnotationPodDeletionCost)
-	podDeletionCost2, _ := utils.GetNumberFromAnnotations(pod2.Annotations, slinkyv1alpha1.AnnotationPodDeletionCost)
+	podDeletionCost1 := getPodDeletionCost(pod1)
+	podDeletionCost2 := getPodDeletionCost(pod2)
 	if podDeletionCost1 != podDeletionCost2 {
 		return podDeletionCost1 < podDeletionCost2
 	}
@@ -71,6 +78,11 @@ func (o ActivePods) Less(i, j int) bool {
 		return podCordon1
 	}
 
+	// Step: drained in Slurm < not drained in Slurm
+	if utils.IsPodSlurmDrain(pod1) != utils.IsPodSlurmDrain(pod2) {
+		return utils.IsPodSlurmDrain(pod1)
+	}
+
 	// Step: higher ordinal < lower ordinal
 	if GetOrdinal(pod1) != GetOrdinal(pod2) {
 		return GetOrdinal(pod1) > GetOrdinal(pod2)
@@ -131,6 +143,166 @@ func SplitActivePods(pods []*corev1.Pod, partition int) (pods1, pods2 []*corev1.
 	return pods1, pods2
 }
 
+// SlurmNodeInfo is the state of the Slurm node of a NodeSet pod, used to rank
+// the pod for scale-in. The zero value is a node without jobs.
+type SlurmNodeInfo struct {
+	// IsAllocated is true when the node is ALLOCATED or MIXED.
+	IsAllocated bool
+	// AllocCPUs is the number of CPUs allocated to jobs.
+	AllocCPUs int32
+	// Deadline is when the last running job on the node ends.
+	Deadline time.Time
+}
+
+// ScaleInPods type allows sorting pods by a scale-in policy, using the state
+// of their Slurm nodes, so a controller can pick the best ones to delete.
+type ScaleInPods struct {
+	Pods   []*corev1.Pod
+	Policy slinkyv1alpha1.ScaleInPolicyType
+	// Nodes maps Slurm node names to their state.
+	Nodes map[string]SlurmNodeInfo
+}
+
+func (o ScaleInPods) Len() int {
+	return len(o.Pods)
+}
+
+func (o ScaleInPods) Swap(i, j int) {
+	o.Pods[i], o.Pods[j] = o.Pods[j], o.Pods[i]
+}
+
+// Less compares two pods and returns true if the first one should be preferred for deletion.
+// The pods are compared by one key at a time, in the same order for every pair, so the order is
+// consistent for sort.Sort.
+func (o ScaleInPods) Less(i, j int) bool {
+	return o.compare(o.Pods[i], o.Pods[j]) < 0
+}
+
+func (o ScaleInPods) compare(pod1, pod2 *corev1.Pod) int {
+	// Step: powered down in Slurm < not powered down in Slurm
+	// The NodeSet was scaled-in for them, so they are condemned first.
+	if c := compareTrueFirst(utils.IsPodSlurmPowerDown(pod1), utils.IsPodSlurmPowerDown(pod2)); c != 0 {
+		return c
+	}
+
+	// Step: unassigned < assigned
+	if c := compareTrueFirst(len(pod1.Spec.NodeName) == 0, len(pod2.Spec.NodeName) == 0); c != 0 {
+		return c
+	}
+
+	// Step: PodPending < PodUnknown < PodRunning
+	podPhaseToWeight := map[corev1.PodPhase]int{corev1.PodPending: 0, corev1.PodUnknown: 1, corev1.PodRunning: 2}
+	if c := cmp.Compare(podPhaseToWeight[pod1.Status.Phase], podPhaseToWeight[pod2.Status.Phase]); c != 0 {
+		return c
+	}
+
+	// Step: not ready < ready
+	if c := compareTrueFirst(!podutil.IsPodReady(pod1), !podutil.IsPodReady(pod2)); c != 0 {
+		return c
+	}
+
+	// Step: cordon < not cordon
+	// Keeps condemning pods whose drain is already in progress.
+	podCordon1, _ := utils.GetBoolFromAnnotations(pod1.Annotations, slinkyv1alpha1.AnnotationPodCordon)
+	podCordon2, _ := utils.GetBoolFromAnnotations(pod2.Annotations, slinkyv1alpha1.AnnotationPodCordon)
+	if c := compareTrueFirst(podCordon1, podCordon2); c != 0 {
+		return c
+	}
+
+	// Step: drained in Slurm < not drained in Slurm
+	if c := compareTrueFirst(utils.IsPodSlurmDrain(pod1), utils.IsPodSlurmDrain(pod2)); c != 0 {
+		return c
+	}
+
+	node1 := o.Nodes[GetNodeName(pod1)]
+	node2 := o.Nodes[GetNodeName(pod2)]
+	switch o.Policy {
+	case slinkyv1alpha1.PreferIdleScaleInPolicyType:
+		// Step: idle < allocated
+		if c := compareTrueFirst(!node1.IsAllocated, !node2.IsAllocated); c != 0 {
+			return c
+		}
+	case slinkyv1alpha1.LeastAllocatedCPUsScaleInPolicyType:
+		// Step: fewer allocated CPUs < more allocated CPUs
+		if c := cmp.Compare(node1.AllocCPUs, node2.AllocCPUs); c != 0 {
+			return c
+		}
+	case slinkyv1alpha1.EarliestDeadlineScaleInPolicyType:
+		// Step: no jobs < earlier deadline < later deadline
+		if c := node1.Deadline.Compare(node2.Deadline); c != 0 {
+			return c
+		}
+	case slinkyv1alpha1.HighestOrdinalScaleInPolicyType:
+		// Step: higher ordinal < lower ordinal
+		if c := cmp.Compare(GetOrdinal(pod2), GetOrdinal(pod1)); c != 0 {
+			return c
+		}
+	case slinkyv1alpha1.NewestScaleInPolicyType:
+		// Step: empty creation time < newer pods < older pods
+		if !pod1.CreationTimestamp.Equal(&pod2.CreationTimestamp) {
+			if afterOrZero(pod1.CreationTimestamp.Time, pod2.CreationTimestamp.Time) {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// Step: lower pod-deletion-cost < higher pod-deletion-cost
+	if c := cmp.Compare(getPodDeletionCost(pod1), getPodDeletionCost(pod2)); c != 0 {
+		return c
+	}
+
+	// Step: higher ordinal < lower ordinal
+	return cmp.Compare(GetOrdinal(pod2), GetOrdinal(pod1))
+}
+
+// compareTrueFirst orders true before false.
+func compareTrueFirst(b1, b2 bool) int {
+	switch {
+	case b1 == b2:
+		return 0
+	case b1:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// getPodDeletionCost returns the deletion cost of the pod, from the NodeSet
+// annotation, or else the Kubernetes one.
+func getPodDeletionCost(pod *corev1.Pod) int32 {
+	if _, ok := pod.Annotations[slinkyv1alpha1.AnnotationPodDeletionCost]; ok {
+		cost, _ := utils.GetNumberFromAnnotations(pod.Annotations, slinkyv1alpha1.AnnotationPodDeletionCost)
+		return cost
+	}
+	cost, _ := utils.GetNumberFromAnnotations(pod.Annotations, corev1.PodDeletionCost)
+	return cost
+}
+
+// SplitScaleInPods returns two list of pods partitioned by a number, ordered
+// by the scale-in policy. An empty policy orders them as active pods.
+func SplitScaleInPods(
+	pods []*corev1.Pod,
+	partition int,
+	policy slinkyv1alpha1.ScaleInPolicyType,
+	nodes map[string]SlurmNodeInfo,
+) (pods1, pods2 []*corev1.Pod) {
+	if policy == "" {
+		return SplitActivePods(pods, partition)
+	}
+
+	pivot := utils.Clamp(partition, 0, len(pods))
+
+	pods1 = make([]*corev1.Pod, pivot)
+	pods2 = make([]*corev1.Pod, len(pods)-pivot)
+
+	sort.Sort(ScaleInPods{Pods: pods, Policy: policy, Nodes: nodes})
+	copy(pods1, pods[:pivot])
+	copy(pods2, pods[pivot:])
+
+	return pods1, pods2
+}
+
 // PodsByCreationTimestamp sorts a list of Pods by creation timestamp, using their names as a tie breaker.
 type PodsByCreationTimestamp []*corev1.Pod
 
@@ -129,6 +129,32 @@ func TestSortingActivePods(t *testing.T) {
 						},
 					},
 				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "runningWithSlurmDrain",
+						Annotations: map[string]string{slinkyv1alpha1.AnnotationPodSlurmDrain: "bad DIMM"},
+					},
+					Spec: corev1.PodSpec{NodeName: "foo"},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodRunning,
+						Conditions: []corev1.PodCondition{
+							{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "runningWithSlurmPowerDown",
+						Annotations: map[string]string{slinkyv1alpha1.AnnotationPodSlurmPowerDown: now.Format(time.RFC3339)},
+					},
+					Spec: corev1.PodSpec{NodeName: "foo"},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodRunning,
+						Conditions: []corev1.PodCondition{
+							{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+						},
+					},
+				},
 				{
 					ObjectMeta: metav1.ObjectMeta{Name: "runningWithOrdinal-1"},
 					Spec:       corev1.PodSpec{NodeName: "foo"},
@@ -141,11 +167,13 @@ func TestSortingActivePods(t *testing.T) {
 				},
 			},
 			wantOrder: []string{
+				"runningWithSlurmPowerDown",
 				"unscheduled",
 				"scheduledButPending",
 				"unknownPhase",
 				"runningButNotReady",
 				"runningWithCordon",
+				"runningWithSlurmDrain",
 				"runningWithOrdinal-1",
 				"runningNoLastTransitionTime",
 				"runningWithLastTransitionTime",
@@ -314,6 +342,318 @@ func TestSplitActivePods(t *testing.T) {
 	}
 }
 
+func TestSortingScaleInPods(t *testing.T) {
+	now := metav1.Now()
+	then := metav1.Time{Time: now.AddDate(0, -1, 0)}
+	newReadyPod := func(name string, created metav1.Time, annotations map[string]string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created, Annotations: annotations},
+			Spec:       corev1.PodSpec{NodeName: "foo"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		policy    slinkyv1alpha1.ScaleInPolicyType
+		nodes     map[string]SlurmNodeInfo
+		pods      []corev1.Pod
+		wantOrder []string
+	}{
+		{
+			name:   "No policy",
+			policy: "",
+			nodes: map[string]SlurmNodeInfo{
+				"foo-0": {},
+				"foo-1": {IsAllocated: true},
+				"foo-2": {IsAllocated: true},
+			},
+			pods: []corev1.Pod{
+				newReadyPod("foo-0", then, nil),
+				newReadyPod("foo-1", then, nil),
+				newReadyPod("foo-2", then, nil),
+			},
+			wantOrder: []string{"foo-2", "foo-1", "foo-0"},
+		},
+		{
+			name:   "PreferIdle",
+			policy: slinkyv1alpha1.PreferIdleScaleInPolicyType,
+			nodes: map[string]SlurmNodeInfo{
+				"foo-0": {IsAllocated: true},
+				"foo-1": {},
+				"foo-2": {IsAllocated: true},
+				"foo-4": {IsAllocated: true},
+				"foo-5": {IsAllocated: true},
+				"foo-6": {IsAllocated: true},
+			},
+			pods: []corev1.Pod{
+				newReadyPod("foo-0", then, nil),
+				newReadyPod("foo-1", then, nil),
+				newReadyPod("foo-2", then, nil),
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo-3"},
+					Spec:       corev1.PodSpec{NodeName: "foo"},
+					Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+				newReadyPod("foo-4", then, map[string]string{slinkyv1alpha1.AnnotationPodCordon: "True"}),
+				newReadyPod("foo-5", then, map[string]string{slinkyv1alpha1.AnnotationPodSlurmDrain: "bad DIMM"}),
+				newReadyPod("foo-6", then, map[string]string{slinkyv1alpha1.AnnotationPodSlurmPowerDown: now.Format(time.RFC3339)}),
+			},
+			wantOrder: []string{"foo-6", "foo-3", "foo-4", "foo-5", "foo-1", "foo-2", "foo-0"},
+		},
+		{
+			name:   "LeastAllocatedCPUs",
+			policy: slinkyv1alpha1.LeastAllocatedCPUsScaleInPolicyType,
+			nodes: map[string]SlurmNodeInfo{
+				"foo-0": {IsAllocated: true, AllocCPUs: 2},
+				"foo-1": {IsAllocated: true, AllocCPUs: 8},
+				"foo-2": {IsAllocated: true, AllocCPUs: 4},
+			},
+			pods: []corev1.Pod{
+				newReadyPod("foo-0", then, nil),
+				newReadyPod("foo-1", then, nil),
+				newReadyPod("foo-2", then, nil),
+				newReadyPod("foo-3", then, nil),
+			},
+			wantOrder: []string{"foo-3", "foo-0", "foo-2", "foo-1"},
+		},
+		{
+			name:   "EarliestDeadline",
+			policy: slinkyv1alpha1.EarliestDeadlineScaleInPolicyType,
+			nodes: map[string]SlurmNodeInfo{
+				"foo-0": {IsAllocated: true, Deadline: now.Add(2 * time.Hour)},
+				"foo-1": {IsAllocated: true, Deadline: now.Add(time.Hour)},
+				"foo-2": {},
+			},
+			pods: []corev1.Pod{
+				newReadyPod("foo-0", then, nil),
+				newReadyPod("foo-1", then, nil),
+				newReadyPod("foo-2", then, nil),
+			},
+			wantOrder: []string{"foo-2", "foo-1", "foo-0"},
+		},
+		{
+			name:   "HighestOrdinal",
+			policy: slinkyv1alpha1.HighestOrdinalScaleInPolicyType,
+			nodes:  map[string]SlurmNodeInfo{},
+			pods: []corev1.Pod{
+				newReadyPod("foo-0", then, nil),
+				newReadyPod("foo-1", then, map[string]string{slinkyv1alpha1.AnnotationPodDeletionCost: "10"}),
+				newReadyPod("foo-2", then, nil),
+			},
+			wantOrder: []string{"foo-2", "foo-1", "foo-0"},
+		},
+		{
+			name:   "PreferIdle with deletion cost",
+			policy: slinkyv1alpha1.PreferIdleScaleInPolicyType,
+			nodes: map[string]SlurmNodeInfo{
+				"foo-0": {IsAllocated: true},
+			},
+			pods: []corev1.Pod{
+				newReadyPod("foo-0", then, nil),
+				newReadyPod("foo-1", then, map[string]string{slinkyv1alpha1.AnnotationPodDeletionCost: "-1"}),
+				newReadyPod("foo-2", then, map[string]string{corev1.PodDeletionCost: "10"}),
+				newReadyPod("foo-3", then, nil),
+			},
+			wantOrder: []string{"foo-1", "foo-3", "foo-2", "foo-0"},
+		},
+		{
+			name:   "Newest",
+			policy: slinkyv1alpha1.NewestScaleInPolicyType,
+			nodes:  map[string]SlurmNodeInfo{},
+			pods: []corev1.Pod{
+				newReadyPod("foo-0", now, nil),
+				newReadyPod("foo-1", then, nil),
+				newReadyPod("foo-2", metav1.Time{Time: then.AddDate(0, -1, 0)}, nil),
+			},
+			wantOrder: []string{"foo-0", "foo-1", "foo-2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			numPods := len(test.pods)
+
+			for i := 0; i < 20; i++ {
+				idx := rand.Perm(numPods)
+				randomizedPods := make([]*corev1.Pod, numPods)
+				for j := 0; j < numPods; j++ {
+					randomizedPods[j] = &test.pods[idx[j]]
+				}
+
+				sort.Sort(ScaleInPods{Pods: randomizedPods, Policy: test.policy, Nodes: test.nodes})
+				gotOrder := make([]string, len(randomizedPods))
+				for i := range randomizedPods {
+					gotOrder[i] = randomizedPods[i].Name
+				}
+
+				if diff := cmp.Diff(test.wantOrder, gotOrder); diff != "" {
+					t.Errorf("Sorted scale-in pod names (-want,+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestScaleInPodsTransitivity(t *testing.T) {
+	now := metav1.Now()
+	ready := []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	// An idle ready pod, an allocated ready pod with a lower deletion cost,
+	// and a not ready pod.
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-0", CreationTimestamp: now},
+			Spec:       corev1.PodSpec{NodeName: "foo"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, Conditions: ready},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo-1",
+				Annotations: map[string]string{slinkyv1alpha1.AnnotationPodDeletionCost: "-10"},
+			},
+			Spec:   corev1.PodSpec{NodeName: "foo"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: ready},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-2"},
+			Spec:       corev1.PodSpec{NodeName: "foo"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	}
+	nodes := map[string]SlurmNodeInfo{
+		"foo-1": {IsAllocated: true, AllocCPUs: 4, Deadline: now.Add(time.Hour)},
+	}
+
+	policies := []slinkyv1alpha1.ScaleInPolicyType{
+		slinkyv1alpha1.PreferIdleScaleInPolicyType,
+		slinkyv1alpha1.LeastAllocatedCPUsScaleInPolicyType,
+		slinkyv1alpha1.EarliestDeadlineScaleInPolicyType,
+		slinkyv1alpha1.HighestOrdinalScaleInPolicyType,
+		slinkyv1alpha1.NewestScaleInPolicyType,
+	}
+	for _, policy := range policies {
+		t.Run(string(policy), func(t *testing.T) {
+			o := ScaleInPods{Pods: pods, Policy: policy, Nodes: nodes}
+			for i := range pods {
+				if o.Less(i, i) {
+					t.Errorf("Less(%s, %s) = true, want false", pods[i].Name, pods[i].Name)
+				}
+				for j := range pods {
+					if o.Less(i, j) && o.Less(j, i) {
+						t.Errorf("Less(%s, %s) and Less(%s, %s) are both true",
+							pods[i].Name, pods[j].Name, pods[j].Name, pods[i].Name)
+					}
+					for k := range pods {
+						if o.Less(i, j) && o.Less(j, k) && !o.Less(i, k) {
+							t.Errorf("Less(%s, %s) and Less(%s, %s), but not Less(%s, %s)",
+								pods[i].Name, pods[j].Name, pods[j].Name, pods[k].Name, pods[i].Name, pods[k].Name)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSplitScaleInPods(t *testing.T) {
+	type args struct {
+		pods      []*corev1.Pod
+		partition int
+		policy    slinkyv1alpha1.ScaleInPolicyType
+		nodes     map[string]SlurmNodeInfo
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantPods1Names []string
+		wantPods2Names []string
+	}{
+		{
+			name: "Empty",
+			args: args{
+				pods:      nil,
+				partition: 0,
+				policy:    slinkyv1alpha1.PreferIdleScaleInPolicyType,
+			},
+			wantPods1Names: []string{},
+			wantPods2Names: []string{},
+		},
+		{
+			name: "No policy",
+			args: args{
+				pods: []*corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "foo-0"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "foo-1"}},
+				},
+				partition: 1,
+				nodes: map[string]SlurmNodeInfo{
+					"foo-1": {IsAllocated: true},
+				},
+			},
+			wantPods1Names: []string{"foo-1"},
+			wantPods2Names: []string{"foo-0"},
+		},
+		{
+			name: "PreferIdle",
+			args: args{
+				pods: []*corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "foo-0"},
+						Status: corev1.PodStatus{
+							Phase: corev1.PodRunning,
+							Conditions: []corev1.PodCondition{
+								{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+							},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "foo-1"},
+						Status: corev1.PodStatus{
+							Phase: corev1.PodRunning,
+							Conditions: []corev1.PodCondition{
+								{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+							},
+						},
+					},
+				},
+				partition: 1,
+				policy:    slinkyv1alpha1.PreferIdleScaleInPolicyType,
+				nodes: map[string]SlurmNodeInfo{
+					"foo-1": {IsAllocated: true},
+				},
+			},
+			wantPods1Names: []string{"foo-0"},
+			wantPods2Names: []string{"foo-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPods1, gotPods2 := SplitScaleInPods(tt.args.pods, tt.args.partition, tt.args.policy, tt.args.nodes)
+
+			gotPods1Names := make([]string, len(gotPods1))
+			for i := range gotPods1 {
+				gotPods1Names[i] = gotPods1[i].Name
+			}
+			gotPods2Names := make([]string, len(gotPods2))
+			for i := range gotPods2 {
+				gotPods2Names[i] = gotPods2[i].Name
+			}
+
+			if diff := cmp.Diff(tt.wantPods1Names, gotPods1Names); diff != "" {
+				t.Errorf("Sorted scale-in pod names (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPods2Names, gotPods2Names); diff != "" {
+				t.Errorf("Sorted scale-in pod names (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestSplitUnhealthyPods(t *testing.T) {
 	type args struct {
 		pods []*corev1.Pod
@@ -7,12 +7,14 @@ package utils
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/controller"
 	daemonutil "k8s.io/kubernetes/pkg/controller/daemon/util"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/historycontrol"
@@ -45,9 +47,29 @@ func NewNodeSetPod(nodeset *slinkyv1alpha1.NodeSet, ordinal int, revisionHash st
 	// Adopt DaemonSet pod tolerations.
 	daemonutil.AddOrUpdateDaemonPodTolerations(&pod.Spec)
 
+	if HasDrainFinalizer(nodeset) {
+		controllerutil.AddFinalizer(pod, slinkyv1alpha1.FinalizerPodDrain)
+	}
+
+	// The pod is only Ready once its Slurm node is.
+	hasReadinessGate := slices.ContainsFunc(pod.Spec.ReadinessGates, func(gate corev1.PodReadinessGate) bool {
+		return gate.ConditionType == slinkyv1alpha1.PodConditionSlurmNodeReady
+	})
+	if !hasReadinessGate {
+		pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, corev1.PodReadinessGate{
+			ConditionType: slinkyv1alpha1.PodConditionSlurmNodeReady,
+		})
+	}
+
 	return pod
 }
 
+// HasDrainFinalizer returns true if the pods of the nodeset are held back from deletion until their Slurm node is
+// drained.
+func HasDrainFinalizer(nodeset *slinkyv1alpha1.NodeSet) bool {
+	return nodeset.Spec.PodDeletion != nil && nodeset.Spec.PodDeletion.DrainFinalizer
+}
+
 func initIdentity(nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) {
 	UpdateIdentity(nodeset, pod)
 	// Set these immutable fields only on initial Pod creation, not updates.
@@ -109,6 +109,50 @@ func newPVC(name string) corev1.PersistentVolumeClaim {
 	}
 }
 
+func TestNewNodeSetPod(t *testing.T) {
+	drainFinalizer := newNodeSet("foo")
+	drainFinalizer.Spec.PodDeletion = &slinkyv1alpha1.NodeSetPodDeletion{DrainFinalizer: true}
+	readinessGate := corev1.PodReadinessGate{ConditionType: slinkyv1alpha1.PodConditionSlurmNodeReady}
+	templateGate := newNodeSet("foo")
+	templateGate.Spec.Template.Spec.ReadinessGates = []corev1.PodReadinessGate{readinessGate}
+	tests := []struct {
+		name               string
+		nodeset            *slinkyv1alpha1.NodeSet
+		wantFinalizers     []string
+		wantReadinessGates []corev1.PodReadinessGate
+	}{
+		{
+			name:               "Without drain finalizer",
+			nodeset:            newNodeSet("foo"),
+			wantFinalizers:     nil,
+			wantReadinessGates: []corev1.PodReadinessGate{readinessGate},
+		},
+		{
+			name:               "With drain finalizer",
+			nodeset:            drainFinalizer,
+			wantFinalizers:     []string{slinkyv1alpha1.FinalizerPodDrain},
+			wantReadinessGates: []corev1.PodReadinessGate{readinessGate},
+		},
+		{
+			name:               "Readiness gate in template",
+			nodeset:            templateGate,
+			wantFinalizers:     nil,
+			wantReadinessGates: []corev1.PodReadinessGate{readinessGate},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewNodeSetPod(tt.nodeset, 0, "")
+			if !apiequality.Semantic.DeepEqual(got.Finalizers, tt.wantFinalizers) {
+				t.Errorf("NewNodeSetPod() Finalizers = %v, want %v", got.Finalizers, tt.wantFinalizers)
+			}
+			if !apiequality.Semantic.DeepEqual(got.Spec.ReadinessGates, tt.wantReadinessGates) {
+				t.Errorf("NewNodeSetPod() ReadinessGates = %v, want %v", got.Spec.ReadinessGates, tt.wantReadinessGates)
+			}
+		})
+	}
+}
+
 func TestIsPodFromNodeSet(t *testing.T) {
 	type args struct {
 		nodeset *slinkyv1alpha1.NodeSet
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package externalmetrics
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	externalmetricsv1beta1 "k8s.io/metrics/pkg/apis/external_metrics/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// authenticationConfigMap holds the request header configuration of the
+// kube-apiserver aggregation layer.
+var authenticationConfigMap = types.NamespacedName{
+	Namespace: "kube-system",
+	Name:      "extension-apiserver-authentication",
+}
+
+// requestHeaderConfig authenticates requests proxied by the kube-apiserver
+// aggregation layer, which identifies the user in request headers.
+type requestHeaderConfig struct {
+	// ClientCAs verify the client certificate of the aggregation layer.
+	ClientCAs *x509.CertPool
+	// AllowedNames are the accepted common names of the client certificate.
+	// Any name is accepted when empty.
+	AllowedNames    []string
+	UsernameHeaders []string
+	GroupHeaders    []string
+}
+
+// user is an authenticated user.
+type user struct {
+	Name   string
+	Groups []string
+}
+
+// loadRequestHeaderConfig reads the request header configuration that the
+// kube-apiserver publishes for aggregated API servers.
+func loadRequestHeaderConfig(ctx context.Context, reader client.Reader) (*requestHeaderConfig, error) {
+	cm := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, authenticationConfigMap, cm); err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap(%s): %w", authenticationConfigMap, err)
+	}
+
+	config := &requestHeaderConfig{
+		ClientCAs: x509.NewCertPool(),
+	}
+	if !config.ClientCAs.AppendCertsFromPEM([]byte(cm.Data["requestheader-client-ca-file"])) {
+		return nil, errors.New("no request header client CA in ConfigMap")
+	}
+	for key, value := range map[string]*[]string{
+		"requestheader-allowed-names":    &config.AllowedNames,
+		"requestheader-username-headers": &config.UsernameHeaders,
+		"requestheader-group-headers":    &config.GroupHeaders,
+	} {
+		if cm.Data[key] == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(cm.Data[key]), value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+	}
+	return config, nil
+}
+
+// authenticate returns the user of a request proxied by the aggregation
+// layer, or false when the request did not come from it.
+func (c *requestHeaderConfig) authenticate(r *http.Request) (*user, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	leaf := r.TLS.VerifiedChains[0][0]
+	if len(c.AllowedNames) > 0 && !slices.Contains(c.AllowedNames, leaf.Subject.CommonName) {
+		return nil, false
+	}
+
+	u := &user{}
+	for _, header := range c.UsernameHeaders {
+		if name := r.Header.Get(header); name != "" {
+			u.Name = name
+			break
+		}
+	}
+	if u.Name == "" {
+		return nil, false
+	}
+	for _, header := range c.GroupHeaders {
+		u.Groups = append(u.Groups, r.Header.Values(header)...)
+	}
+	return u, true
+}
+
+// authorize returns true when the user may get the metric in the namespace.
+func authorize(ctx context.Context, c client.Client, u *user, namespace, metric string) (bool, error) {
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   u.Name,
+			Groups: u.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     externalmetricsv1beta1.SchemeGroupVersion.Group,
+				Version:   externalmetricsv1beta1.SchemeGroupVersion.Version,
+				Resource:  metric,
+			},
+		},
+	}
+	if err := c.Create(ctx, sar); err != nil {
+		return false, err
+	}
+	return sar.Status.Allowed, nil
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package externalmetrics
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_loadRequestHeaderConfig(t *testing.T) {
+	caPEM, _, err := certutil.GenerateSelfSignedCertKey("front-proxy-ca", nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateSelfSignedCertKey() error = %v", err)
+	}
+	tests := []struct {
+		name        string
+		data        map[string]string
+		wantHeaders []string
+		wantErr     bool
+	}{
+		{
+			name: "Config",
+			data: map[string]string{
+				"requestheader-client-ca-file":   string(caPEM),
+				"requestheader-allowed-names":    `["front-proxy-client"]`,
+				"requestheader-username-headers": `["X-Remote-User"]`,
+				"requestheader-group-headers":    `["X-Remote-Group"]`,
+			},
+			wantHeaders: []string{"X-Remote-User"},
+		},
+		{
+			name: "No client CA",
+			data: map[string]string{
+				"requestheader-username-headers": `["X-Remote-User"]`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Malformed headers",
+			data: map[string]string{
+				"requestheader-client-ca-file":   string(caPEM),
+				"requestheader-username-headers": `X-Remote-User`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: authenticationConfigMap.Namespace,
+					Name:      authenticationConfigMap.Name,
+				},
+				Data: tt.data,
+			}
+			c := fake.NewClientBuilder().WithObjects(cm).Build()
+			got, err := loadRequestHeaderConfig(context.TODO(), c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadRequestHeaderConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(got.UsernameHeaders, tt.wantHeaders) {
+				t.Errorf("loadRequestHeaderConfig() username headers = %v, want %v", got.UsernameHeaders, tt.wantHeaders)
+			}
+		})
+	}
+}
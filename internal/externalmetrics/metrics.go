@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package externalmetrics
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+// Metric names.
+const (
+	MetricPendingJobs    = "slurm_partition_pending_jobs"
+	MetricPendingCPUs    = "slurm_partition_pending_cpus"
+	MetricIdleNodes      = "slurm_partition_idle_nodes"
+	MetricAllocatedNodes = "slurm_partition_allocated_nodes"
+)
+
+// Metrics lists the served metric names.
+var Metrics = []string{
+	MetricPendingJobs,
+	MetricPendingCPUs,
+	MetricIdleNodes,
+	MetricAllocatedNodes,
+}
+
+// Metric labels, which a metric selector may match.
+const (
+	LabelCluster   = "cluster"
+	LabelPartition = "partition"
+)
+
+// PartitionMetrics are the queue metrics of a Slurm partition.
+type PartitionMetrics struct {
+	Partition string
+	// PendingJobs is the number of pending jobs that may run in the partition.
+	PendingJobs int64
+	// PendingCPUs is the number of CPUs requested by the pending jobs.
+	PendingCPUs int64
+	// IdleNodes is the number of idle nodes that are not drained.
+	IdleNodes int64
+	// AllocatedNodes is the number of nodes running jobs.
+	AllocatedNodes int64
+}
+
+// Value returns the value of the metric.
+func (m *PartitionMetrics) Value(metric string) (int64, bool) {
+	switch metric {
+	case MetricPendingJobs:
+		return m.PendingJobs, true
+	case MetricPendingCPUs:
+		return m.PendingCPUs, true
+	case MetricIdleNodes:
+		return m.IdleNodes, true
+	case MetricAllocatedNodes:
+		return m.AllocatedNodes, true
+	default:
+		return 0, false
+	}
+}
+
+// calculatePartitionMetrics calculates the metrics of each partition. A
+// pending job counts in every partition it may run in.
+func calculatePartitionMetrics(
+	partitions []slurmapi.Partition,
+	nodes []slurmapi.Node,
+	jobs []slurmapi.Job,
+) []PartitionMetrics {
+	metrics := make(map[string]*PartitionMetrics, len(partitions))
+	for _, partition := range partitions {
+		metrics[partition.Name] = &PartitionMetrics{Partition: partition.Name}
+	}
+
+	for _, node := range nodes {
+		for _, name := range node.Partitions {
+			m, ok := metrics[name]
+			if !ok {
+				continue
+			}
+			switch {
+			case node.State.Has(slurmapi.NodeStateAllocated), node.State.Has(slurmapi.NodeStateMixed):
+				m.AllocatedNodes++
+			case node.State.Has(slurmapi.NodeStateIdle) && !node.State.Has(slurmapi.NodeStateDrain):
+				m.IdleNodes++
+			}
+		}
+	}
+
+	for _, job := range jobs {
+		if !job.IsPending {
+			continue
+		}
+		for _, name := range job.Partitions {
+			m, ok := metrics[name]
+			if !ok {
+				continue
+			}
+			m.PendingJobs++
+			m.PendingCPUs += int64(max(job.CPUs, 1))
+		}
+	}
+
+	list := make([]PartitionMetrics, 0, len(metrics))
+	for _, m := range metrics {
+		list = append(list, *m)
+	}
+	slices.SortFunc(list, func(a, b PartitionMetrics) int {
+		return strings.Compare(a.Partition, b.Partition)
+	})
+	return list
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package externalmetrics
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/utils/set"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+func Test_calculatePartitionMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		partitions []slurmapi.Partition
+		nodes      []slurmapi.Node
+		jobs       []slurmapi.Job
+		want       []PartitionMetrics
+	}{
+		{
+			name: "Empty",
+			want: []PartitionMetrics{},
+		},
+		{
+			name: "Partitions",
+			partitions: []slurmapi.Partition{
+				{Name: "gpu"},
+				{Name: "debug"},
+			},
+			nodes: []slurmapi.Node{
+				{
+					Name:       "node-0",
+					State:      set.New(slurmapi.NodeStateIdle),
+					Partitions: []string{"debug", "gpu"},
+				},
+				{
+					Name:       "node-1",
+					State:      set.New(slurmapi.NodeStateIdle, slurmapi.NodeStateDrain),
+					Partitions: []string{"debug"},
+				},
+				{
+					Name:       "node-2",
+					State:      set.New(slurmapi.NodeStateMixed),
+					Partitions: []string{"debug"},
+				},
+				{
+					Name:       "node-3",
+					State:      set.New(slurmapi.NodeStateAllocated),
+					Partitions: []string{"unknown"},
+				},
+			},
+			jobs: []slurmapi.Job{
+				{
+					ID:         1,
+					IsRunning:  true,
+					Partitions: []string{"debug"},
+					CPUs:       4,
+				},
+				{
+					ID:         2,
+					IsPending:  true,
+					Partitions: []string{"debug", "gpu"},
+					CPUs:       8,
+				},
+				{
+					ID:         3,
+					IsPending:  true,
+					Partitions: []string{"debug"},
+				},
+			},
+			want: []PartitionMetrics{
+				{
+					Partition:      "debug",
+					PendingJobs:    2,
+					PendingCPUs:    9,
+					IdleNodes:      1,
+					AllocatedNodes: 1,
+				},
+				{
+					Partition:   "gpu",
+					PendingJobs: 1,
+					PendingCPUs: 8,
+					IdleNodes:   1,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculatePartitionMetrics(tt.partitions, tt.nodes, tt.jobs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calculatePartitionMetrics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
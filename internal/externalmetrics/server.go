@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package externalmetrics serves Slurm queue metrics through the
+// `external.metrics.k8s.io` API, so a HorizontalPodAutoscaler can scale a
+// NodeSet without KEDA or the Prometheus Adapter.
+package externalmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+	externalmetricsv1beta1 "k8s.io/metrics/pkg/apis/external_metrics/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+)
+
+var _ manager.Runnable = &Server{}
+var _ manager.LeaderElectionRunnable = &Server{}
+
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// Server is an aggregated API server for `external.metrics.k8s.io`. It serves
+// the queue metrics of the Slurm partitions of the Clusters in the requested
+// namespace, read from the Slurm client caches.
+type Server struct {
+	client.Client
+	// APIReader reads the aggregation layer configuration without a cache.
+	APIReader client.Reader
+	// SlurmClusters holds the Slurm clients of the Clusters.
+	SlurmClusters *resources.Clusters
+
+	// BindAddress is the address the server listens on.
+	BindAddress string
+	// CertDir holds the `tls.crt` and `tls.key` of the server. A self-signed
+	// certificate is generated when empty.
+	CertDir string
+
+	requestHeader *requestHeaderConfig
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Only the
+// leader connects to the Slurm clusters.
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("externalmetrics")
+
+	requestHeader, err := loadRequestHeaderConfig(ctx, s.APIReader)
+	if err != nil {
+		return err
+	}
+	s.requestHeader = requestHeader
+
+	cert, err := s.loadCertificate()
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.VerifyClientCertIfGiven,
+			ClientCAs:    requestHeader.ClientCAs,
+			NextProtos:   []string{"http/1.1"},
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "unable to shutdown external metrics server")
+		}
+	}()
+
+	logger.Info("Starting external metrics server", "address", s.BindAddress)
+	if err := srv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// loadCertificate loads the serving certificate, or generates a self-signed
+// one.
+func (s *Server) loadCertificate() (tls.Certificate, error) {
+	if s.CertDir != "" {
+		return tls.LoadX509KeyPair(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	}
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("slurm-operator", nil, nil)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// Handler returns the HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	prefix := "/apis/" + externalmetricsv1beta1.SchemeGroupVersion.String()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+prefix, s.authenticated(s.handleDiscovery))
+	mux.HandleFunc("GET "+prefix+"/namespaces/{namespace}/{metric}", s.authenticated(s.handleMetric))
+	return mux
+}
+
+type userHandlerFunc func(w http.ResponseWriter, r *http.Request, u *user)
+
+// authenticated rejects requests that were not proxied by the aggregation
+// layer.
+func (s *Server) authenticated(next userHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, ok := s.requestHeader.authenticate(r)
+		if !ok {
+			writeStatus(w, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, "Unauthorized")
+			return
+		}
+		next(w, r, u)
+	}
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, _ *http.Request, _ *user) {
+	list := &metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: externalmetricsv1beta1.SchemeGroupVersion.String(),
+	}
+	for _, metric := range Metrics {
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       metric,
+			Namespaced: true,
+			Kind:       "ExternalMetricValueList",
+			Verbs:      metav1.Verbs{"get"},
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleMetric(w http.ResponseWriter, r *http.Request, u *user) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("externalmetrics")
+
+	namespace := r.PathValue("namespace")
+	metric := r.PathValue("metric")
+
+	allowed, err := authorize(ctx, s.Client, u, namespace, metric)
+	if err != nil {
+		logger.Error(err, "unable to authorize request", "user", u.Name)
+		writeStatus(w, http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		return
+	}
+	if !allowed {
+		writeStatus(w, http.StatusForbidden, metav1.StatusReasonForbidden,
+			fmt.Sprintf("user %q cannot get %s in namespace %q", u.Name, metric, namespace))
+		return
+	}
+
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+
+	values, err := s.getMetric(ctx, namespace, metric, selector)
+	if err != nil {
+		if errors.Is(err, errUnknownMetric) {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, err.Error())
+			return
+		}
+		logger.Error(err, "unable to get external metric", "namespace", namespace, "metric", metric)
+		writeStatus(w, http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, &externalmetricsv1beta1.ExternalMetricValueList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ExternalMetricValueList",
+			APIVersion: externalmetricsv1beta1.SchemeGroupVersion.String(),
+		},
+		Items: values,
+	})
+}
+
+var errUnknownMetric = errors.New("unknown metric")
+
+// getMetric returns a value of the metric for each partition of the Clusters
+// in the namespace whose labels match the selector.
+func (s *Server) getMetric(
+	ctx context.Context,
+	namespace, metric string,
+	selector labels.Selector,
+) ([]externalmetricsv1beta1.ExternalMetricValue, error) {
+	logger := log.FromContext(ctx).WithName("externalmetrics")
+
+	if _, ok := (&PartitionMetrics{}).Value(metric); !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownMetric, metric)
+	}
+
+	clusterList := &slinkyv1alpha1.ClusterList{}
+	if err := s.List(ctx, clusterList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	now := metav1.Now()
+	values := make([]externalmetricsv1beta1.ExternalMetricValue, 0)
+	for _, cluster := range clusterList.Items {
+		clusterKey := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+		if name, ok := selector.RequiresExactMatch(LabelCluster); ok && name != cluster.Name {
+			continue
+		}
+		api := s.SlurmClusters.GetAPI(clusterKey)
+		if api == nil {
+			logger.V(1).Info("Cluster has no Slurm client, skipping", "cluster", klog.KRef(cluster.Namespace, cluster.Name))
+			continue
+		}
+		partitions, err := api.ListPartitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := api.ListNodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		jobs, err := api.ListJobs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range calculatePartitionMetrics(partitions, nodes, jobs) {
+			metricLabels := map[string]string{
+				LabelCluster:   cluster.Name,
+				LabelPartition: m.Partition,
+			}
+			if !selector.Matches(labels.Set(metricLabels)) {
+				continue
+			}
+			value, _ := m.Value(metric)
+			values = append(values, externalmetricsv1beta1.ExternalMetricValue{
+				MetricName:   metric,
+				MetricLabels: metricLabels,
+				Timestamp:    now,
+				Value:        *resource.NewQuantity(value, resource.DecimalSI),
+			})
+		}
+	}
+	return values, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	writeJSON(w, code, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status:  metav1.StatusFailure,
+		Code:    int32(code),
+		Reason:  reason,
+		Message: message,
+	})
+}
@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package externalmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	externalmetricsv1beta1 "k8s.io/metrics/pkg/apis/external_metrics/v1beta1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurmfake "github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/resources"
+)
+
+func newServer() *Server {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	cluster := &slinkyv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithObjects(cluster).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if sar, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+					sar.Status.Allowed = sar.Spec.User == "system:serviceaccount:kube-system:horizontal-pod-autoscaler"
+					return nil
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	node := &slurmtypes.V0041Node{
+		V0041Node: v0041.V0041Node{
+			Name:       ptr.To("node-0"),
+			State:      ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE}),
+			Partitions: ptr.To(v0041.V0041CsvString{"debug"}),
+		},
+	}
+	jobList := &slurmtypes.V0041JobInfoList{
+		Items: []slurmtypes.V0041JobInfo{
+			{
+				V0041JobInfo: v0041.V0041JobInfo{
+					JobId:     ptr.To[int32](1),
+					JobState:  ptr.To([]v0041.V0041JobInfoJobState{v0041.V0041JobInfoJobStatePENDING}),
+					Partition: ptr.To("debug"),
+					Cpus:      &v0041.V0041Uint32NoValStruct{Number: ptr.To[int32](4)},
+				},
+			},
+		},
+	}
+	partitionList := &slurmtypes.V0041PartitionInfoList{
+		Items: []slurmtypes.V0041PartitionInfo{
+			{V0041PartitionInfo: v0041.V0041PartitionInfo{Name: ptr.To("debug")}},
+			{V0041PartitionInfo: v0041.V0041PartitionInfo{Name: ptr.To("gpu")}},
+		},
+	}
+	slurmClient := slurmfake.NewClientBuilder().
+		WithObjects(node).
+		WithLists(jobList, partitionList).
+		Build()
+	slurmClusters := resources.NewClusters()
+	slurmClusters.Add(types.NamespacedName{Namespace: corev1.NamespaceDefault, Name: "slurm"}, slurmClient)
+
+	return &Server{
+		Client:        c,
+		SlurmClusters: slurmClusters,
+		requestHeader: &requestHeaderConfig{
+			AllowedNames:    []string{"front-proxy-client"},
+			UsernameHeaders: []string{"X-Remote-User"},
+			GroupHeaders:    []string{"X-Remote-Group"},
+		},
+	}
+}
+
+func TestServer_Handler(t *testing.T) {
+	const hpaUser = "system:serviceaccount:kube-system:horizontal-pod-autoscaler"
+	const prefix = "/apis/external.metrics.k8s.io/v1beta1"
+	tests := []struct {
+		name       string
+		path       string
+		clientName string
+		user       string
+		wantCode   int
+		wantValues map[string]int64
+	}{
+		{
+			name:       "Discovery",
+			path:       prefix,
+			clientName: "front-proxy-client",
+			user:       hpaUser,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "Untrusted client",
+			path:       prefix + "/namespaces/default/slurm_partition_pending_jobs",
+			clientName: "other",
+			user:       hpaUser,
+			wantCode:   http.StatusUnauthorized,
+		},
+		{
+			name:       "Forbidden",
+			path:       prefix + "/namespaces/default/slurm_partition_pending_jobs",
+			clientName: "front-proxy-client",
+			user:       "alice",
+			wantCode:   http.StatusForbidden,
+		},
+		{
+			name:       "Unknown metric",
+			path:       prefix + "/namespaces/default/slurm_unknown",
+			clientName: "front-proxy-client",
+			user:       hpaUser,
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "All partitions",
+			path:       prefix + "/namespaces/default/slurm_partition_pending_cpus",
+			clientName: "front-proxy-client",
+			user:       hpaUser,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]int64{
+				"debug": 4,
+				"gpu":   0,
+			},
+		},
+		{
+			name:       "Partition selector",
+			path:       prefix + "/namespaces/default/slurm_partition_idle_nodes?labelSelector=cluster%3Dslurm%2Cpartition%3Ddebug",
+			clientName: "front-proxy-client",
+			user:       hpaUser,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]int64{
+				"debug": 1,
+			},
+		},
+		{
+			name:       "Other cluster",
+			path:       prefix + "/namespaces/default/slurm_partition_pending_jobs?labelSelector=cluster%3Dother",
+			clientName: "front-proxy-client",
+			user:       hpaUser,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]int64{},
+		},
+		{
+			name:       "Other namespace",
+			path:       prefix + "/namespaces/slurm/slurm_partition_pending_jobs",
+			clientName: "front-proxy-client",
+			user:       hpaUser,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{
+					{{Subject: pkix.Name{CommonName: tt.clientName}}},
+				},
+			}
+			req.Header.Set("X-Remote-User", tt.user)
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("Handler() code = %v, want %v: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantValues == nil {
+				return
+			}
+
+			list := &externalmetricsv1beta1.ExternalMetricValueList{}
+			if err := json.Unmarshal(rec.Body.Bytes(), list); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			got := make(map[string]int64, len(list.Items))
+			for _, item := range list.Items {
+				got[item.MetricLabels[LabelPartition]] = item.Value.Value()
+			}
+			if len(got) != len(tt.wantValues) {
+				t.Fatalf("Handler() values = %v, want %v", got, tt.wantValues)
+			}
+			for partition, want := range tt.wantValues {
+				if got[partition] != want {
+					t.Errorf("Handler() values = %v, want %v", got, tt.wantValues)
+				}
+			}
+		})
+	}
+}
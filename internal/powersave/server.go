@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package powersave
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/puttsk/hostlist"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+)
+
+const (
+	ActionResume  = "resume"
+	ActionSuspend = "suspend"
+
+	// maxBodySize bounds the hostlist of a request.
+	maxBodySize = 1 << 20
+)
+
+var _ manager.Runnable = &Server{}
+var _ manager.LeaderElectionRunnable = &Server{}
+
+// Server bridges the Slurm power saving ResumeProgram and SuspendProgram to
+// NodeSets. The programs POST the hostlist they were given to
+// `/clusters/{namespace}/{name}/{resume,suspend}`, which marks the matching
+// NodeSet pods resumed or suspended. Requests are authenticated by the bearer
+// token of that Cluster.
+type Server struct {
+	client.Client
+
+	// BindAddress is the address the server listens on.
+	BindAddress string
+	// CertDir holds the `tls.crt` and `tls.key` of the server. A self-signed
+	// certificate is generated when empty.
+	CertDir string
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica
+// serves requests, since they only update NodeSet annotations.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("powersave")
+
+	cert, err := s.loadCertificate()
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"http/1.1"},
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "unable to shutdown power save server")
+		}
+	}()
+
+	logger.Info("Starting power save server", "address", s.BindAddress)
+	if err := srv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// loadCertificate loads the serving certificate, or generates a self-signed
+// one.
+func (s *Server) loadCertificate() (tls.Certificate, error) {
+	if s.CertDir != "" {
+		return tls.LoadX509KeyPair(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	}
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("slurm-operator", nil, nil)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// Handler returns the HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /clusters/{namespace}/{name}/{action}", s.handle)
+	return mux
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("powersave")
+
+	clusterKey := types.NamespacedName{
+		Namespace: r.PathValue("namespace"),
+		Name:      r.PathValue("name"),
+	}
+	authorized, err := s.isAuthorized(ctx, r, clusterKey)
+	if err != nil {
+		logger.Error(err, "unable to authorize power save request", "cluster", clusterKey)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !authorized {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	action := r.PathValue("action")
+	if action != ActionResume && action != ActionSuspend {
+		http.Error(w, fmt.Sprintf("unknown action %q", action), http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hostNames, err := hostlist.Expand(strings.TrimSpace(string(body)))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid hostlist: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	unknown, err := s.apply(ctx, clusterKey, action, hostNames)
+	if err != nil {
+		logger.Error(err, "unable to apply power save action",
+			"cluster", clusterKey, "action", action)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	logger.Info("Applied power save action", "cluster", clusterKey,
+		"action", action, "hosts", len(hostNames), "unknown", len(unknown))
+	if len(unknown) > 0 {
+		http.Error(w, fmt.Sprintf("unknown hosts: %s", strings.Join(unknown, ",")), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// isAuthorized returns true when the request carries the bearer token of the
+// cluster. Requests for a cluster without a power save token are rejected.
+func (s *Server) isAuthorized(ctx context.Context, r *http.Request, clusterKey types.NamespacedName) (bool, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false, nil
+	}
+
+	cluster := &slinkyv1alpha1.Cluster{}
+	if err := s.Get(ctx, clusterKey, cluster); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if cluster.Spec.PowerSave == nil || cluster.Spec.PowerSave.TokenSecretRef == "" {
+		return false, nil
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Spec.PowerSave.TokenSecretRef,
+	}
+	if err := s.Get(ctx, secretKey, secret); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	want := []byte(strings.TrimSpace(string(secret.Data[slinkyv1alpha1.PowerSaveTokenSecretKey])))
+	if len(want) == 0 {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare([]byte(token), want) == 1, nil
+}
+
+// apply resumes or suspends the hosts on the power saving NodeSets of the
+// cluster. It returns the hosts that belong to none of them.
+func (s *Server) apply(
+	ctx context.Context,
+	clusterKey types.NamespacedName,
+	action string,
+	hostNames []string,
+) ([]string, error) {
+	nodesetList := &slinkyv1alpha1.NodeSetList{}
+	if err := s.List(ctx, nodesetList, client.InNamespace(clusterKey.Namespace)); err != nil {
+		return nil, err
+	}
+
+	unknown := set.New(hostNames...)
+	for i := range nodesetList.Items {
+		nodeset := &nodesetList.Items[i]
+		if nodeset.ClusterKey() != clusterKey || !nodesetutils.IsPowerSave(nodeset) {
+			continue
+		}
+		ordinals := set.New[int]()
+		for _, hostName := range hostNames {
+			if ordinal := nodesetutils.GetNodeOrdinal(nodeset, hostName); ordinal >= 0 {
+				ordinals.Insert(ordinal)
+				unknown.Delete(hostName)
+			}
+		}
+		if ordinals.Len() == 0 {
+			continue
+		}
+		if err := s.updateResumed(ctx, client.ObjectKeyFromObject(nodeset), action, ordinals); err != nil {
+			return nil, fmt.Errorf("failed to update NodeSet(%s): %w", klog.KObj(nodeset), err)
+		}
+	}
+
+	return unknown.SortedList(), nil
+}
+
+// updateResumed adds or removes the ordinals from the resumed ordinals of the
+// NodeSet.
+func (s *Server) updateResumed(
+	ctx context.Context,
+	key types.NamespacedName,
+	action string,
+	ordinals set.Set[int],
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nodeset := &slinkyv1alpha1.NodeSet{}
+		if err := s.Get(ctx, key, nodeset); err != nil {
+			return err
+		}
+		toUpdate := nodeset.DeepCopy()
+		resumed := nodesetutils.GetResumedOrdinals(toUpdate)
+		switch action {
+		case ActionResume:
+			resumed = resumed.Union(ordinals)
+		case ActionSuspend:
+			resumed = resumed.Difference(ordinals)
+		}
+		nodesetutils.SetResumedOrdinals(toUpdate, resumed)
+		patch := client.MergeFromWithOptions(nodeset, client.MergeFromWithOptimisticLock{})
+		return s.Patch(ctx, toUpdate, patch)
+	})
+}
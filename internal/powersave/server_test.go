@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package powersave
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+func newNodeSet(name, resumed string, powerSave bool) *slinkyv1alpha1.NodeSet {
+	return &slinkyv1alpha1.NodeSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+			Annotations: map[string]string{
+				slinkyv1alpha1.AnnotationPowerSaveResumed: resumed,
+			},
+		},
+		Spec: slinkyv1alpha1.NodeSetSpec{
+			ClusterName: "slurm",
+			Replicas:    ptr.To[int32](4),
+			PowerSave: &slinkyv1alpha1.NodeSetPowerSave{
+				Enabled: powerSave,
+			},
+		},
+	}
+}
+
+func newCluster(name, tokenSecretRef string) *slinkyv1alpha1.Cluster {
+	cluster := &slinkyv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+	}
+	if tokenSecretRef != "" {
+		cluster.Spec.PowerSave = &slinkyv1alpha1.ClusterPowerSave{
+			TokenSecretRef: tokenSecretRef,
+		}
+	}
+	return cluster
+}
+
+func newSecret(name, token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+		Data: map[string][]byte{
+			slinkyv1alpha1.PowerSaveTokenSecretKey: []byte(token + "\n"),
+		},
+	}
+}
+
+func TestServer_handle(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	tests := []struct {
+		name        string
+		path        string
+		token       string
+		body        string
+		wantCode    int
+		wantResumed map[string]string
+	}{
+		{
+			name:     "Unauthorized",
+			path:     "/clusters/default/slurm/resume",
+			token:    "wrong",
+			body:     "foo-[1-2]",
+			wantCode: http.StatusUnauthorized,
+			wantResumed: map[string]string{
+				"foo": "0",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Unknown action",
+			path:     "/clusters/default/slurm/reboot",
+			token:    "secret",
+			body:     "foo-1",
+			wantCode: http.StatusNotFound,
+			wantResumed: map[string]string{
+				"foo": "0",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Invalid hostlist",
+			path:     "/clusters/default/slurm/resume",
+			token:    "secret",
+			body:     "foo-[1-",
+			wantCode: http.StatusBadRequest,
+			wantResumed: map[string]string{
+				"foo": "0",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Resume",
+			path:     "/clusters/default/slurm/resume",
+			token:    "secret",
+			body:     "foo-[1-2]\n",
+			wantCode: http.StatusNoContent,
+			wantResumed: map[string]string{
+				"foo": "0,1,2",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Suspend",
+			path:     "/clusters/default/slurm/suspend",
+			token:    "secret",
+			body:     "foo-0",
+			wantCode: http.StatusNoContent,
+			wantResumed: map[string]string{
+				"foo": "",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Unknown hosts",
+			path:     "/clusters/default/slurm/resume",
+			token:    "secret",
+			body:     "foo-3,foo-9,bar-0,static-0",
+			wantCode: http.StatusNotFound,
+			wantResumed: map[string]string{
+				"foo": "0,3",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Other cluster",
+			path:     "/clusters/default/other/resume",
+			token:    "other-secret",
+			body:     "foo-1",
+			wantCode: http.StatusNotFound,
+			wantResumed: map[string]string{
+				"foo": "0",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Token of other cluster",
+			path:     "/clusters/default/slurm/resume",
+			token:    "other-secret",
+			body:     "foo-1",
+			wantCode: http.StatusUnauthorized,
+			wantResumed: map[string]string{
+				"foo": "0",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Cluster without token",
+			path:     "/clusters/default/notoken/resume",
+			token:    "secret",
+			body:     "foo-1",
+			wantCode: http.StatusUnauthorized,
+			wantResumed: map[string]string{
+				"foo": "0",
+				"bar": "",
+			},
+		},
+		{
+			name:     "Cluster not found",
+			path:     "/clusters/default/missing/resume",
+			token:    "secret",
+			body:     "foo-1",
+			wantCode: http.StatusUnauthorized,
+			wantResumed: map[string]string{
+				"foo": "0",
+				"bar": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithObjects(newNodeSet("foo", "0", true), newNodeSet("bar", "", false)).
+				WithObjects(newCluster("slurm", "slurm-power-save"), newSecret("slurm-power-save", "secret")).
+				WithObjects(newCluster("other", "other-power-save"), newSecret("other-power-save", "other-secret")).
+				WithObjects(newCluster("notoken", "")).
+				Build()
+			s := &Server{Client: c}
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("handle() code = %v, want %v: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+
+			for name, want := range tt.wantResumed {
+				nodeset := &slinkyv1alpha1.NodeSet{}
+				key := client.ObjectKey{Namespace: corev1.NamespaceDefault, Name: name}
+				if err := c.Get(context.TODO(), key, nodeset); err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				if got := nodeset.Annotations[slinkyv1alpha1.AnnotationPowerSaveResumed]; got != want {
+					t.Errorf("NodeSet(%s) resumed = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_handle_otherNamespace(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	// A NodeSet outside of the namespace of the Cluster is not powered by it.
+	nodeset := newNodeSet("baz", "0", true)
+	nodeset.Namespace = "other"
+	nodeset.Spec.ClusterNamespace = corev1.NamespaceDefault
+	c := fake.NewClientBuilder().
+		WithObjects(nodeset).
+		WithObjects(newCluster("slurm", "slurm-power-save"), newSecret("slurm-power-save", "secret")).
+		Build()
+	s := &Server{Client: c}
+
+	req := httptest.NewRequest(http.MethodPost, "/clusters/default/slurm/resume", strings.NewReader("baz-1"))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("handle() code = %v, want %v: %s", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+
+	got := &slinkyv1alpha1.NodeSet{}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(nodeset), got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if resumed := got.Annotations[slinkyv1alpha1.AnnotationPowerSaveResumed]; resumed != "0" {
+		t.Errorf("NodeSet(%s) resumed = %q, want %q", nodeset.Name, resumed, "0")
+	}
+}
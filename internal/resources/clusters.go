@@ -10,16 +10,22 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/SlinkyProject/slurm-client/pkg/client"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
 )
 
 type Clusters struct {
-	lock    sync.RWMutex
-	clients map[string]client.Client
+	lock      sync.RWMutex
+	clients   map[string]client.Client
+	endpoints map[string]*Endpoints
+	apis      map[string]slurmapi.Interface
 }
 
 func NewClusters() *Clusters {
 	return &Clusters{
-		clients: make(map[string]client.Client),
+		clients:   make(map[string]client.Client),
+		endpoints: make(map[string]*Endpoints),
+		apis:      make(map[string]slurmapi.Interface),
 	}
 }
 
@@ -33,6 +39,32 @@ func (c *Clusters) Get(name types.NamespacedName) client.Client {
 	return nil
 }
 
+// GetEndpoints returns the endpoints the cluster client sends requests to, or
+// nil when the client was added without endpoints.
+func (c *Clusters) GetEndpoints(name types.NamespacedName) *Endpoints {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.endpoints[name.String()]
+}
+
+// GetAPI returns the Slurm REST API adapter of the cluster client, or nil when
+// there is no client.
+func (c *Clusters) GetAPI(name types.NamespacedName) slurmapi.Interface {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.apis[name.String()]
+}
+
+// GetVersion returns the Slurm REST API version of the cluster client, or an
+// empty string when there is no client.
+func (c *Clusters) GetVersion(name types.NamespacedName) string {
+	api := c.GetAPI(name)
+	if api == nil {
+		return ""
+	}
+	return api.Version()
+}
+
 func (c *Clusters) Has(names ...types.NamespacedName) bool {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
@@ -44,24 +76,45 @@ func (c *Clusters) Has(names ...types.NamespacedName) bool {
 	return false
 }
 
-func (c *Clusters) add(name types.NamespacedName, client client.Client) bool {
+func (c *Clusters) add(name types.NamespacedName, client client.Client, endpoints *Endpoints, version string) bool {
 	if _, ok := c.clients[name.String()]; !ok {
+		api, err := slurmapi.New(client, version)
+		if err != nil {
+			return false
+		}
+		c.apis[name.String()] = api
 		ctx := context.TODO()
 		go client.Start(ctx)
 		c.clients[name.String()] = client
+		if endpoints != nil {
+			go endpoints.Start(ctx)
+			c.endpoints[name.String()] = endpoints
+		}
 		return true
 	}
 	return false
 }
 
 func (c *Clusters) Add(name types.NamespacedName, client client.Client) bool {
+	return c.AddWithEndpoints(name, client, nil, slurmapi.DefaultVersion)
+}
+
+// AddWithEndpoints adds a client whose requests are routed through endpoints,
+// which are health checked until the client is removed. The client is used
+// through the Slurm REST API version adapter.
+func (c *Clusters) AddWithEndpoints(name types.NamespacedName, client client.Client, endpoints *Endpoints, version string) bool {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.remove(name)
-	return c.add(name, client)
+	return c.add(name, client, endpoints, version)
 }
 
 func (c *Clusters) remove(name types.NamespacedName) bool {
+	if endpoints, ok := c.endpoints[name.String()]; ok {
+		endpoints.Stop()
+		delete(c.endpoints, name.String())
+	}
+	delete(c.apis, name.String())
 	if client, ok := c.clients[name.String()]; ok {
 		client.Stop()
 		delete(c.clients, name.String())
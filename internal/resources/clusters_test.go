@@ -12,6 +12,8 @@ import (
 
 	"github.com/SlinkyProject/slurm-client/pkg/client"
 	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
 )
 
 func TestNewClusters(t *testing.T) {
@@ -22,7 +24,9 @@ func TestNewClusters(t *testing.T) {
 		{
 			name: "Test new clusters",
 			want: &Clusters{
-				clients: make(map[string]client.Client),
+				clients:   make(map[string]client.Client),
+				endpoints: make(map[string]*Endpoints),
+				apis:      make(map[string]slurmapi.Interface),
 			},
 		},
 	}
@@ -142,8 +146,9 @@ func TestClusters_add(t *testing.T) {
 			c := &Clusters{
 				lock:    sync.RWMutex{},
 				clients: tt.fields.clients,
+				apis:    make(map[string]slurmapi.Interface),
 			}
-			if got := c.add(tt.args.name, tt.args.client); got != tt.want {
+			if got := c.add(tt.args.name, tt.args.client, nil, ""); got != tt.want {
 				t.Errorf("Clusters.add() = %v, want %v", got, tt.want)
 			}
 		})
@@ -201,6 +206,7 @@ func TestClusters_Add(t *testing.T) {
 			c := &Clusters{
 				lock:    sync.RWMutex{},
 				clients: tt.fields.clients,
+				apis:    make(map[string]slurmapi.Interface),
 			}
 			if got := c.Add(tt.args.name, tt.args.client); got != tt.want {
 				t.Errorf("Clusters.Add() = %v, want %v", got, tt.want)
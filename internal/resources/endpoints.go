@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	tokenHeader = "X-SLURM-USER-TOKEN"
+)
+
+var (
+	// EndpointProbeInterval is how often each endpoint is health checked.
+	EndpointProbeInterval = 10 * time.Second
+	// EndpointProbeTimeout bounds a single endpoint health check.
+	EndpointProbeTimeout = 5 * time.Second
+)
+
+// Endpoints is an ordered list of slurmrestd servers for a single cluster.
+// It implements http.RoundTripper, sending each request to the most preferred
+// healthy server and failing over to the next one when a server does not
+// respond. When a token is set, it replaces the token of every request, so the
+// token can be rotated without recreating the client.
+type Endpoints struct {
+	// ConfigHash identifies the transport configuration the endpoints were
+	// built with. It must not be changed after the endpoints are in use.
+	ConfigHash string
+
+	lock    sync.RWMutex
+	names   []string
+	servers []*url.URL
+	healthy []bool
+	active  int
+	token   string
+
+	transport http.RoundTripper
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewEndpoints returns Endpoints for the servers, in order of preference. All
+// servers start out healthy. Servers may only differ by scheme and host. When
+// transport is nil, http.DefaultTransport is used.
+func NewEndpoints(servers []string, transport http.RoundTripper) (*Endpoints, error) {
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no servers given")
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	e := &Endpoints{
+		names:     slices.Clone(servers),
+		servers:   make([]*url.URL, len(servers)),
+		healthy:   make([]bool, len(servers)),
+		transport: transport,
+		stopCh:    make(chan struct{}),
+	}
+	for i, server := range servers {
+		u, err := url.Parse(server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse server %q: %w", server, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("server %q must be an absolute URL", server)
+		}
+		e.servers[i] = u
+		e.healthy[i] = true
+	}
+	return e, nil
+}
+
+// Servers returns the configured servers, in order of preference.
+func (e *Endpoints) Servers() []string {
+	return slices.Clone(e.names)
+}
+
+// Equal returns true when the endpoints were built from the same servers.
+func (e *Endpoints) Equal(servers []string) bool {
+	return slices.Equal(e.names, servers)
+}
+
+// Active returns the server that requests are currently sent to.
+func (e *Endpoints) Active() string {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.names[e.active]
+}
+
+// Healthy returns the servers that passed their last health check.
+func (e *Endpoints) Healthy() []string {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	servers := make([]string, 0, len(e.servers))
+	for i, name := range e.names {
+		if e.healthy[i] {
+			servers = append(servers, name)
+		}
+	}
+	return servers
+}
+
+// Token returns the token set on requests, if any.
+func (e *Endpoints) Token() string {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.token
+}
+
+// SetToken replaces the token set on requests.
+func (e *Endpoints) SetToken(token string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.token = token
+}
+
+// setHealthy records the health of a server and reselects the active server:
+// the first healthy server in order of preference. When none are healthy, the
+// active server is kept.
+func (e *Endpoints) setHealthy(i int, healthy bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.healthy[i] = healthy
+	for j := range e.servers {
+		if e.healthy[j] {
+			e.active = j
+			return
+		}
+	}
+}
+
+// order returns the server indexes to try, starting with the active server.
+func (e *Endpoints) order() []int {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	order := []int{e.active}
+	for i := range e.servers {
+		if i != e.active {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// RoundTrip implements http.RoundTripper.
+func (e *Endpoints) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests with a body that cannot be replayed are only sent once.
+	canRetry := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	token := e.Token()
+
+	var res *http.Response
+	var err error
+	for n, i := range e.order() {
+		if n > 0 && !canRetry {
+			break
+		}
+		outReq := req.Clone(req.Context())
+		if n > 0 && req.GetBody != nil {
+			outReq.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+		outReq.URL.Scheme = e.servers[i].Scheme
+		outReq.URL.Host = e.servers[i].Host
+		outReq.Host = ""
+		if token != "" {
+			outReq.Header.Set(tokenHeader, token)
+		}
+
+		if res != nil {
+			drainBody(res)
+		}
+		res, err = e.transport.RoundTrip(outReq)
+		if err == nil && !isUnavailable(res.StatusCode) {
+			e.setHealthy(i, true)
+			return res, nil
+		}
+		if req.Context().Err() != nil {
+			// The caller gave up, this says nothing about the server.
+			break
+		}
+		e.setHealthy(i, false)
+	}
+	return res, err
+}
+
+// Start periodically health checks every server until Stop is called.
+func (e *Endpoints) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-e.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	wait.UntilWithContext(ctx, e.probe, EndpointProbeInterval)
+}
+
+// Stop ends health checking. It is safe to call before Start.
+func (e *Endpoints) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+	})
+}
+
+// probe health checks every server. Any response that is not a gateway error
+// means slurmrestd is serving, authentication is not checked.
+func (e *Endpoints) probe(ctx context.Context) {
+	for i, u := range e.servers {
+		e.setHealthy(i, e.probeServer(ctx, u))
+	}
+}
+
+func (e *Endpoints) probeServer(ctx context.Context, u *url.URL) bool {
+	ctx, cancel := context.WithTimeout(ctx, EndpointProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	res, err := e.transport.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	drainBody(res)
+	return !isUnavailable(res.StatusCode)
+}
+
+// isUnavailable returns true for status codes that indicate the server, or a
+// proxy in front of it, could not serve the request.
+func isUnavailable(code int) bool {
+	switch code {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func drainBody(res *http.Response) {
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+}
+
+var _ http.RoundTripper = &Endpoints{}
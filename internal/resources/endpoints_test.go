@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+func newTestServer(code int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		_, _ = io.WriteString(w, body)
+	}))
+}
+
+func TestNewEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers []string
+		wantErr bool
+	}{
+		{
+			name:    "Empty",
+			servers: nil,
+			wantErr: true,
+		},
+		{
+			name:    "Relative",
+			servers: []string{"slurmrestd:6820"},
+			wantErr: true,
+		},
+		{
+			name:    "Valid",
+			servers: []string{"http://slurmrestd-0:6820", "http://slurmrestd-1:6820"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEndpoints(tt.servers, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !got.Equal(tt.servers) {
+				t.Errorf("Servers() = %v, want %v", got.Servers(), tt.servers)
+			}
+			if got.Active() != tt.servers[0] {
+				t.Errorf("Active() = %v, want %v", got.Active(), tt.servers[0])
+			}
+		})
+	}
+}
+
+func TestEndpoints_RoundTrip(t *testing.T) {
+	up := newTestServer(http.StatusOK, "up")
+	defer up.Close()
+	unavailable := newTestServer(http.StatusServiceUnavailable, "unavailable")
+	defer unavailable.Close()
+	down := newTestServer(http.StatusOK, "down")
+	down.Close()
+
+	tests := []struct {
+		name       string
+		servers    []string
+		method     string
+		body       io.Reader
+		wantBody   string
+		wantCode   int
+		wantActive string
+		wantErr    bool
+	}{
+		{
+			name:       "Preferred server is up",
+			servers:    []string{up.URL, unavailable.URL},
+			method:     http.MethodGet,
+			wantBody:   "up",
+			wantCode:   http.StatusOK,
+			wantActive: up.URL,
+		},
+		{
+			name:       "Fail over on connection error",
+			servers:    []string{down.URL, up.URL},
+			method:     http.MethodGet,
+			wantBody:   "up",
+			wantCode:   http.StatusOK,
+			wantActive: up.URL,
+		},
+		{
+			name:       "Fail over on gateway error",
+			servers:    []string{unavailable.URL, up.URL},
+			method:     http.MethodPost,
+			body:       strings.NewReader("{}"),
+			wantBody:   "up",
+			wantCode:   http.StatusOK,
+			wantActive: up.URL,
+		},
+		{
+			name:       "No body replay",
+			servers:    []string{unavailable.URL, up.URL},
+			method:     http.MethodPost,
+			body:       io.NopCloser(strings.NewReader("{}")),
+			wantBody:   "unavailable",
+			wantCode:   http.StatusServiceUnavailable,
+			wantActive: up.URL,
+		},
+		{
+			name:       "All servers down",
+			servers:    []string{down.URL, down.URL + "/"},
+			method:     http.MethodGet,
+			wantErr:    true,
+			wantActive: down.URL + "/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEndpoints(tt.servers, nil)
+			if err != nil {
+				t.Fatalf("NewEndpoints() error = %v", err)
+			}
+			client := &http.Client{Transport: e}
+			req, err := http.NewRequest(tt.method, tt.servers[0]+"/slurm/v0.0.41/ping", tt.body)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			res, err := client.Do(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				defer res.Body.Close()
+				body, _ := io.ReadAll(res.Body)
+				if res.StatusCode != tt.wantCode {
+					t.Errorf("StatusCode = %v, want %v", res.StatusCode, tt.wantCode)
+				}
+				if string(body) != tt.wantBody {
+					t.Errorf("Body = %v, want %v", string(body), tt.wantBody)
+				}
+			}
+			if got := e.Active(); got != tt.wantActive {
+				t.Errorf("Active() = %v, want %v", got, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestEndpoints_probe(t *testing.T) {
+	up := newTestServer(http.StatusUnauthorized, "")
+	defer up.Close()
+	unavailable := newTestServer(http.StatusBadGateway, "")
+	defer unavailable.Close()
+
+	e, err := NewEndpoints([]string{unavailable.URL, up.URL}, nil)
+	if err != nil {
+		t.Fatalf("NewEndpoints() error = %v", err)
+	}
+	e.probe(context.TODO())
+	if got := e.Active(); got != up.URL {
+		t.Errorf("Active() = %v, want %v", got, up.URL)
+	}
+	if got := e.Healthy(); len(got) != 1 || got[0] != up.URL {
+		t.Errorf("Healthy() = %v, want %v", got, []string{up.URL})
+	}
+}
+
+func TestClusters_AddWithEndpoints(t *testing.T) {
+	name := types.NamespacedName{
+		Name:      "foo",
+		Namespace: "default",
+	}
+	e, err := NewEndpoints([]string{"http://slurmrestd:6820"}, nil)
+	if err != nil {
+		t.Fatalf("NewEndpoints() error = %v", err)
+	}
+	c := NewClusters()
+	if !c.AddWithEndpoints(name, fake.NewFakeClient(), e, slurmapi.V0040) {
+		t.Errorf("Clusters.AddWithEndpoints() = false, want true")
+	}
+	if got := c.GetEndpoints(name); got != e {
+		t.Errorf("Clusters.GetEndpoints() = %v, want %v", got, e)
+	}
+	if got := c.GetVersion(name); got != slurmapi.V0040 {
+		t.Errorf("Clusters.GetVersion() = %v, want %v", got, slurmapi.V0040)
+	}
+	if !c.Remove(name) {
+		t.Errorf("Clusters.Remove() = false, want true")
+	}
+	if got := c.GetEndpoints(name); got != nil {
+		t.Errorf("Clusters.GetEndpoints() = %v, want nil", got)
+	}
+	if got := c.GetAPI(name); got != nil {
+		t.Errorf("Clusters.GetAPI() = %v, want nil", got)
+	}
+	select {
+	case <-e.stopCh:
+	case <-time.After(time.Second):
+		t.Errorf("Endpoints were not stopped")
+	}
+}
+
+func TestEndpoints_SetToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Header.Get(tokenHeader))
+	}))
+	defer server.Close()
+
+	e, err := NewEndpoints([]string{server.URL}, nil)
+	if err != nil {
+		t.Fatalf("NewEndpoints() error = %v", err)
+	}
+	client := &http.Client{Transport: e}
+	for _, token := range []string{"first", "second"} {
+		e.SetToken(token)
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		req.Header.Add(tokenHeader, "stale")
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if string(body) != token {
+			t.Errorf("token = %v, want %v", string(body), token)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMint(t *testing.T) {
+	key := []byte("secret")
+	now := time.Unix(1700000000, 0)
+	token, err := Mint(key, "slurm", now, time.Hour)
+	if err != nil {
+		t.Fatalf("Mint() error = %v", err)
+	}
+	claims, err := Verify(key, token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := Claims{
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		IssuedAt:  now.Unix(),
+		Username:  "slurm",
+	}
+	if *claims != want {
+		t.Errorf("Verify() = %v, want %v", *claims, want)
+	}
+	if !claims.Expiry().Equal(now.Add(time.Hour)) {
+		t.Errorf("Expiry() = %v, want %v", claims.Expiry(), now.Add(time.Hour))
+	}
+
+	if _, err := Mint(nil, "slurm", now, time.Hour); err == nil {
+		t.Errorf("Mint() with empty key error = nil, want error")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key := []byte("secret")
+	token, err := Mint(key, "slurm", time.Now(), time.Hour)
+	if err != nil {
+		t.Fatalf("Mint() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		key     []byte
+		token   string
+		wantErr error
+	}{
+		{
+			name:    "Valid",
+			key:     key,
+			token:   token,
+			wantErr: nil,
+		},
+		{
+			name:    "Wrong key",
+			key:     []byte("other"),
+			token:   token,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "Malformed",
+			key:     key,
+			token:   "not-a-token",
+			wantErr: ErrMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(tt.key, tt.token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		wantExpiry time.Time
+		wantErr    bool
+	}{
+		{
+			name: "Slurm token",
+			// {"alg":"HS256","typ":"JWT"}.{"exp":1700003600,"iat":1700000000,"sun":"slurm"}
+			token:      "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJleHAiOjE3MDAwMDM2MDAsImlhdCI6MTcwMDAwMDAwMCwic3VuIjoic2x1cm0ifQ.c2ln",
+			wantExpiry: time.Unix(1700003600, 0),
+		},
+		{
+			name:       "No expiry",
+			token:      "eyJhbGciOiJIUzI1NiJ9.eyJzdW4iOiJzbHVybSJ9.c2ln",
+			wantExpiry: time.Time{},
+		},
+		{
+			name:    "Opaque token",
+			token:   "abcdef",
+			wantErr: true,
+		},
+		{
+			name:    "Bad payload",
+			token:   "a.!!!.c",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := Parse(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !claims.Expiry().Equal(tt.wantExpiry) {
+				t.Errorf("Expiry() = %v, want %v", claims.Expiry(), tt.wantExpiry)
+			}
+		})
+	}
+}
@@ -16,11 +16,31 @@ func IsPodCordon(pod *corev1.Pod) bool {
 	return pod.GetAnnotations()[slinkyv1alpha1.AnnotationPodCordon] == "true"
 }
 
+// IsPodSlurmDrain returns true if the Slurm node of the pod was drained by someone other than the NodeSet controller.
+func IsPodSlurmDrain(pod *corev1.Pod) bool {
+	return pod.GetAnnotations()[slinkyv1alpha1.AnnotationPodSlurmDrain] != ""
+}
+
+// IsPodSlurmPowerDown returns true if the NodeSet was scaled-in for the power down requested on the Slurm node of the pod.
+func IsPodSlurmPowerDown(pod *corev1.Pod) bool {
+	return pod.GetAnnotations()[slinkyv1alpha1.AnnotationPodSlurmPowerDown] != ""
+}
+
 // isRunningAndReady returns true if pod is in the PodRunning Phase, if it has a condition of PodReady.
 func IsRunningAndReady(pod *corev1.Pod) bool {
 	return pod.Status.Phase == corev1.PodRunning && podutil.IsPodReady(pod)
 }
 
+// IsRunningAndContainersReady returns true if pod is in the PodRunning Phase, and all its containers are ready. Unlike
+// IsRunningAndReady, it ignores the readiness gates of the pod.
+func IsRunningAndContainersReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	_, condition := podutil.GetPodCondition(&pod.Status, corev1.ContainersReady)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
 func IsRunningAndAvailable(pod *corev1.Pod, minReadySeconds int32) bool {
 	return podutil.IsPodAvailable(pod, minReadySeconds, metav1.Now())
 }
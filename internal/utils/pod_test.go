@@ -55,6 +55,42 @@ func TestIsRunningAndReady(t *testing.T) {
 	}
 }
 
+func TestIsRunningAndContainersReady(t *testing.T) {
+	newPod := func(phase corev1.PodPhase, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Phase: phase, Conditions: conditions}}
+	}
+	containersReady := corev1.PodCondition{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}
+	notReady := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionFalse}
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{
+			name: "Containers ready, pod not ready",
+			pod:  newPod(corev1.PodRunning, containersReady, notReady),
+			want: true,
+		},
+		{
+			name: "Containers not ready",
+			pod:  newPod(corev1.PodRunning, notReady),
+			want: false,
+		},
+		{
+			name: "Not running",
+			pod:  newPod(corev1.PodPending, containersReady),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRunningAndContainersReady(tt.pod); got != tt.want {
+				t.Errorf("IsRunningAndContainersReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func newPod(now metav1.Time, ready bool, beforeSec int) *corev1.Pod {
 	conditionStatus := corev1.ConditionFalse
 	if ready {
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/utils/ptr"
+)
+
+// ClusterInfo describes the Slurm cluster behind slurmrestd.
+type ClusterInfo struct {
+	// ClusterName is the Slurm cluster name.
+	ClusterName string
+	// SlurmVersion is the Slurm release (e.g. `24.11.1`).
+	SlurmVersion string
+}
+
+// versionPath returns the path segment of a version (e.g. `v0.0.41`).
+func versionPath(version string) string {
+	if len(version) != len("v0000") {
+		return version
+	}
+	return fmt.Sprintf("v%s.%s.%s", version[1:2], version[2:3], version[3:])
+}
+
+// GetClusterInfo returns the cluster information slurmrestd reports in the
+// metadata of its responses. The slurm-client drops response metadata, so the
+// ping endpoint is requested directly.
+func GetClusterInfo(ctx context.Context, httpClient *http.Client, server, version string) (*ClusterInfo, error) {
+	if version == "" {
+		version = DefaultVersion
+	}
+	url := fmt.Sprintf("%s/slurm/%s/ping/", strings.TrimSuffix(server, "/"), versionPath(version))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get cluster info: %s", http.StatusText(resp.StatusCode))
+	}
+
+	// The metadata is the same across versions.
+	body := struct {
+		Meta struct {
+			Slurm struct {
+				Cluster *string `json:"cluster"`
+				Release *string `json:"release"`
+				Version *struct {
+					Major *string `json:"major"`
+					Minor *string `json:"minor"`
+					Micro *string `json:"micro"`
+				} `json:"version"`
+			} `json:"slurm"`
+		} `json:"meta"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode cluster info: %w", err)
+	}
+
+	slurm := body.Meta.Slurm
+	info := &ClusterInfo{
+		ClusterName:  ptr.Deref(slurm.Cluster, ""),
+		SlurmVersion: ptr.Deref(slurm.Release, ""),
+	}
+	if info.SlurmVersion == "" && slurm.Version != nil {
+		info.SlurmVersion = fmt.Sprintf("%s.%s.%s",
+			ptr.Deref(slurm.Version.Major, "0"),
+			ptr.Deref(slurm.Version.Minor, "0"),
+			ptr.Deref(slurm.Version.Micro, "0"))
+	}
+	return info, nil
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetClusterInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		path    string
+		code    int
+		body    string
+		want    ClusterInfo
+		wantErr bool
+	}{
+		{
+			name:    "Release",
+			version: V0041,
+			path:    "/slurm/v0.0.41/ping/",
+			code:    http.StatusOK,
+			body:    `{"meta": {"slurm": {"cluster": "slurm", "release": "24.11.1"}}, "pings": []}`,
+			want: ClusterInfo{
+				ClusterName:  "slurm",
+				SlurmVersion: "24.11.1",
+			},
+		},
+		{
+			name:    "Version",
+			version: V0040,
+			path:    "/slurm/v0.0.40/ping/",
+			code:    http.StatusOK,
+			body:    `{"meta": {"slurm": {"cluster": "slurm", "version": {"major": "24", "minor": "05", "micro": "5"}}}}`,
+			want: ClusterInfo{
+				ClusterName:  "slurm",
+				SlurmVersion: "24.05.5",
+			},
+		},
+		{
+			name:    "Unauthorized",
+			version: V0041,
+			path:    "/slurm/v0.0.41/ping/",
+			code:    http.StatusUnauthorized,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := GetClusterInfo(context.TODO(), server.Client(), server.URL, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetClusterInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != tt.want {
+				t.Errorf("GetClusterInfo() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// openAPIPath is where slurmrestd serves its OpenAPI specification.
+const openAPIPath = "/openapi/v3"
+
+// slurmPathRegex matches the version of slurmrestd paths (e.g. `/slurm/v0.0.41/ping/`).
+var slurmPathRegex = regexp.MustCompile(`^/slurm/v(\d+)\.(\d+)\.(\d+)/`)
+
+// AdvertisedVersions returns the Slurm REST API versions served by
+// slurmrestd, sorted oldest first.
+func AdvertisedVersions(ctx context.Context, httpClient *http.Client, server string) ([]string, error) {
+	url := strings.TrimSuffix(server, "/") + openAPIPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", openAPIPath, http.StatusText(resp.StatusCode))
+	}
+
+	spec := struct {
+		Paths map[string]json.RawMessage `json:"paths"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", openAPIPath, err)
+	}
+
+	versions := []string{}
+	for path := range spec.Paths {
+		match := slurmPathRegex.FindStringSubmatch(path)
+		if match == nil {
+			continue
+		}
+		version := "v" + match[1] + match[2] + match[3]
+		if !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+// Negotiate returns the newest Slurm REST API version that is both served by
+// slurmrestd and supported by an adapter.
+func Negotiate(ctx context.Context, httpClient *http.Client, server string) (string, error) {
+	versions, err := AdvertisedVersions(ctx, httpClient, server)
+	if err != nil {
+		return "", err
+	}
+	for _, version := range slices.Backward(versions) {
+		if IsSupported(version) {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("slurmrestd serves Slurm REST API versions %v, supported versions are %v",
+		versions, SupportedVersions)
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func newOpenAPIServer(code int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != openAPIPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         int
+		body         string
+		wantVersions []string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name: "Newest supported",
+			code: http.StatusOK,
+			body: `{"paths": {
+				"/slurm/v0.0.40/ping/": {},
+				"/slurm/v0.0.41/ping/": {},
+				"/slurm/v0.0.41/nodes/": {},
+				"/slurmdb/v0.0.41/jobs/": {}
+			}}`,
+			wantVersions: []string{V0040, V0041},
+			want:         V0041,
+		},
+		{
+			name: "Newer unsupported",
+			code: http.StatusOK,
+			body: `{"paths": {
+				"/slurm/v0.0.40/ping/": {},
+				"/slurm/v0.0.42/ping/": {}
+			}}`,
+			wantVersions: []string{V0040, "v0042"},
+			want:         V0040,
+		},
+		{
+			name: "None supported",
+			code: http.StatusOK,
+			body: `{"paths": {
+				"/slurm/v0.0.42/ping/": {}
+			}}`,
+			wantVersions: []string{"v0042"},
+			wantErr:      true,
+		},
+		{
+			name:    "Not found",
+			code:    http.StatusNotFound,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid",
+			code:    http.StatusOK,
+			body:    `paths`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOpenAPIServer(tt.code, tt.body)
+			defer server.Close()
+
+			versions, _ := AdvertisedVersions(context.TODO(), server.Client(), server.URL+"/")
+			if tt.wantVersions != nil && !slices.Equal(versions, tt.wantVersions) {
+				t.Errorf("AdvertisedVersions() = %v, want %v", versions, tt.wantVersions)
+			}
+
+			got, err := Negotiate(context.TODO(), server.Client(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Negotiate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Negotiate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package slurmapi wraps a Slurm client with a version independent view of
+// the node, job, and ping operations the operator relies on. Each supported
+// Slurm REST API version is implemented by an adapter.
+package slurmapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/utils/set"
+
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+)
+
+// Slurm REST API versions.
+const (
+	V0040 = "v0040"
+	V0041 = "v0041"
+
+	// DefaultVersion is used when no version was selected or negotiated.
+	DefaultVersion = V0041
+)
+
+// SupportedVersions lists the versions with an adapter, oldest first. Only the
+// versions whose types are provided by slurm-client can have one.
+var SupportedVersions = []string{V0040, V0041}
+
+// IsSupported returns true when the version has an adapter.
+func IsSupported(version string) bool {
+	return slices.Contains(SupportedVersions, version)
+}
+
+// NodeState is a Slurm node base or flag state.
+type NodeState string
+
+// Slurm node states.
+const (
+	NodeStateAllocated     NodeState = "ALLOCATED"
+	NodeStateCompleting    NodeState = "COMPLETING"
+	NodeStateDown          NodeState = "DOWN"
+	NodeStateDrain         NodeState = "DRAIN"
+	NodeStateError         NodeState = "ERROR"
+	NodeStateFail          NodeState = "FAIL"
+	NodeStateFuture        NodeState = "FUTURE"
+	NodeStateIdle          NodeState = "IDLE"
+	NodeStateInvalid       NodeState = "INVALID"
+	NodeStateInvalidReg    NodeState = "INVALID_REG"
+	NodeStateMaintenance   NodeState = "MAINTENANCE"
+	NodeStateMixed         NodeState = "MIXED"
+	NodeStateNotResponding NodeState = "NOT_RESPONDING"
+	NodeStatePowerDown     NodeState = "POWER_DOWN"
+	NodeStatePoweredDown   NodeState = "POWERED_DOWN"
+	NodeStatePoweringDown  NodeState = "POWERING_DOWN"
+	NodeStateRebootCancel  NodeState = "REBOOT_CANCELED"
+	NodeStateRebootRequest NodeState = "REBOOT_REQUESTED"
+	NodeStateResume        NodeState = "RESUME"
+	NodeStateUndrain       NodeState = "UNDRAIN"
+	NodeStateUnknown       NodeState = "UNKNOWN"
+)
+
+// Node is a Slurm node.
+type Node struct {
+	Name       string
+	State      set.Set[NodeState]
+	Reason     string
+	Comment    string
+	Partitions []string
+	// ReasonSetByUser is the user that set the reason. slurmctld sets its
+	// reasons as its `SlurmUser`.
+	ReasonSetByUser string
+	// AllocCPUs is the number of CPUs allocated to jobs.
+	AllocCPUs int32
+	// Weight is the scheduling weight, lower weights are allocated first.
+	Weight int32
+
+	// object is the Slurm client object the node was converted from.
+	object object.Object
+}
+
+// NodeUpdate is a request to update a Slurm node. Nil fields are unchanged.
+type NodeUpdate struct {
+	State   []NodeState
+	Reason  *string
+	Comment *string
+	Weight  *int32
+}
+
+// InfiniteDuration is the time limit of jobs without one.
+const InfiniteDuration = time.Duration(math.MaxInt64)
+
+// Job is a Slurm job.
+type Job struct {
+	ID int32
+	// Nodes is the hostlist expression of the nodes allocated to the job.
+	Nodes     string
+	IsRunning bool
+	IsPending bool
+	// Partitions are the partitions the job may run in.
+	Partitions []string
+	// NodeCount is the number of nodes requested by the job.
+	NodeCount int32
+	// CPUs is the number of CPUs requested by the job.
+	CPUs      int32
+	StartTime time.Time
+	// TimeLimit is the wall time of the job, or InfiniteDuration.
+	TimeLimit time.Duration
+}
+
+// JobUpdate is a request to update a Slurm job. Nil fields are unchanged.
+type JobUpdate struct {
+	// Requeue allows the job to be requeued when its nodes fail.
+	Requeue *bool
+}
+
+// Partition is a Slurm partition.
+type Partition struct {
+	Name  string
+	State []string
+	// TotalNodes is the number of nodes configured in the partition.
+	TotalNodes int32
+}
+
+// ControllerPing is the result of pinging a slurmctld.
+type ControllerPing struct {
+	Hostname string
+	IsUp     bool
+	// Latency is zero when slurmrestd does not report it.
+	Latency time.Duration
+}
+
+// Interface is a version independent Slurm client.
+type Interface interface {
+	// Version returns the Slurm REST API version of the adapter.
+	Version() string
+	// Client returns the underlying Slurm client.
+	Client() slurmclient.Client
+
+	// GetNode returns the Slurm node.
+	GetNode(ctx context.Context, name string) (*Node, error)
+	// ListNodes returns all Slurm nodes.
+	ListNodes(ctx context.Context, opts ...slurmclient.ListOption) ([]Node, error)
+	// UpdateNode updates the Slurm node.
+	UpdateNode(ctx context.Context, node *Node, update NodeUpdate) error
+	// ListJobs returns all Slurm jobs.
+	ListJobs(ctx context.Context) ([]Job, error)
+	// UpdateJob updates the Slurm job.
+	UpdateJob(ctx context.Context, id int32, update JobUpdate) error
+	// CancelJob cancels the Slurm job.
+	CancelJob(ctx context.Context, id int32) error
+	// ListPartitions returns all Slurm partitions.
+	ListPartitions(ctx context.Context) ([]Partition, error)
+	// PingControllers pings all slurmctld.
+	PingControllers(ctx context.Context) ([]ControllerPing, error)
+
+	// NodeObjectType returns the Slurm client object type of nodes, to look
+	// up their informer.
+	NodeObjectType() object.ObjectType
+	// ToNode converts a node object from the informer.
+	ToNode(obj any) (*Node, bool)
+}
+
+// New returns the adapter of the version for the client.
+func New(client slurmclient.Client, version string) (Interface, error) {
+	switch version {
+	case V0040:
+		return &v0040Adapter{client: client}, nil
+	case V0041, "":
+		return &v0041Adapter{client: client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported Slurm REST API version %q, supported versions are %v",
+			version, SupportedVersions)
+	}
+}
+
+// UncachedObjects returns the objects of all versions that must always be
+// read from slurmrestd rather than from an informer cache.
+func UncachedObjects() []object.Object {
+	return []object.Object{
+		v0040PingObject(),
+		v0041PingObject(),
+	}
+}
+
+// toPartitions splits the comma separated partitions of a job.
+func toPartitions(partitions string) []string {
+	if partitions == "" {
+		return nil
+	}
+	return strings.Split(partitions, ",")
+}
+
+func toTimeLimit(minutes int64, infinite bool) time.Duration {
+	if infinite {
+		return InfiniteDuration
+	}
+	return time.Duration(minutes) * time.Minute
+}
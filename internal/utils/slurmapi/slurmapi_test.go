@@ -0,0 +1,383 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmapi
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+
+	v0040 "github.com/SlinkyProject/slurm-client/api/v0040"
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:        "Default",
+			version:     "",
+			wantVersion: DefaultVersion,
+		},
+		{
+			name:        "v0040",
+			version:     V0040,
+			wantVersion: V0040,
+		},
+		{
+			name:        "v0041",
+			version:     V0041,
+			wantVersion: V0041,
+		},
+		{
+			name:    "Unsupported",
+			version: "v0042",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(fake.NewFakeClient(), tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Version() != tt.wantVersion {
+				t.Errorf("Version() = %v, want %v", got.Version(), tt.wantVersion)
+			}
+		})
+	}
+}
+
+func newV0040Client() slurmclient.Client {
+	node := &slurmtypes.V0040Node{
+		V0040Node: v0040.V0040Node{
+			Name:            ptr.To("node-0"),
+			State:           ptr.To([]v0040.V0040NodeState{v0040.V0040NodeStateIDLE}),
+			Comment:         ptr.To("comment"),
+			ReasonSetByUser: ptr.To("slurm"),
+			AllocCpus:       ptr.To[int32](4),
+		},
+	}
+	jobList := &slurmtypes.V0040JobInfoList{
+		Items: []slurmtypes.V0040JobInfo{
+			{
+				V0040JobInfo: v0040.V0040JobInfo{
+					JobId:     ptr.To[int32](1),
+					Nodes:     ptr.To("node-0"),
+					JobState:  ptr.To([]v0040.V0040JobInfoJobState{v0040.V0040JobInfoJobStateRUNNING}),
+					StartTime: &v0040.V0040Uint64NoVal{Number: ptr.To[int64](100)},
+					TimeLimit: &v0040.V0040Uint32NoVal{Infinite: ptr.To(true)},
+				},
+			},
+			{
+				V0040JobInfo: v0040.V0040JobInfo{
+					JobId:     ptr.To[int32](2),
+					JobState:  ptr.To([]v0040.V0040JobInfoJobState{v0040.V0040JobInfoJobStatePENDING}),
+					Partition: ptr.To("debug,all"),
+					NodeCount: &v0040.V0040Uint32NoVal{Number: ptr.To[int64](2)},
+					Cpus:      &v0040.V0040Uint32NoVal{Number: ptr.To[int64](8)},
+				},
+			},
+		},
+	}
+	pingList := &slurmtypes.V0040ControllerPingList{
+		Items: []slurmtypes.V0040ControllerPing{
+			{
+				V0040ControllerPing: v0040.V0040ControllerPing{
+					Hostname: ptr.To("slurmctld-0"),
+					Pinged:   ptr.To(slurmtypes.V0040ControllerPingPingedUP),
+					Latency:  ptr.To[int64](10),
+				},
+			},
+		},
+	}
+	partitionList := &slurmtypes.V0040PartitionInfoList{
+		Items: []slurmtypes.V0040PartitionInfo{
+			{
+				V0040PartitionInfo: v0040.V0040PartitionInfo{
+					Name: ptr.To("debug"),
+				},
+			},
+		},
+	}
+	return fake.NewClientBuilder().
+		WithObjects(node).
+		WithLists(jobList, pingList, partitionList).
+		WithUpdateFn(updateFn).
+		Build()
+}
+
+func newV0041Client() slurmclient.Client {
+	node := &slurmtypes.V0041Node{
+		V0041Node: v0041.V0041Node{
+			Name:            ptr.To("node-0"),
+			State:           ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE}),
+			Comment:         ptr.To("comment"),
+			ReasonSetByUser: ptr.To("slurm"),
+			AllocCpus:       ptr.To[int32](4),
+		},
+	}
+	jobList := &slurmtypes.V0041JobInfoList{
+		Items: []slurmtypes.V0041JobInfo{
+			{
+				V0041JobInfo: v0041.V0041JobInfo{
+					JobId:     ptr.To[int32](1),
+					Nodes:     ptr.To("node-0"),
+					JobState:  ptr.To([]v0041.V0041JobInfoJobState{v0041.V0041JobInfoJobStateRUNNING}),
+					StartTime: &v0041.V0041Uint64NoValStruct{Number: ptr.To[int64](100)},
+					TimeLimit: &v0041.V0041Uint32NoValStruct{Infinite: ptr.To(true)},
+				},
+			},
+			{
+				V0041JobInfo: v0041.V0041JobInfo{
+					JobId:     ptr.To[int32](2),
+					JobState:  ptr.To([]v0041.V0041JobInfoJobState{v0041.V0041JobInfoJobStatePENDING}),
+					Partition: ptr.To("debug,all"),
+					NodeCount: &v0041.V0041Uint32NoValStruct{Number: ptr.To[int32](2)},
+					Cpus:      &v0041.V0041Uint32NoValStruct{Number: ptr.To[int32](8)},
+				},
+			},
+		},
+	}
+	pingList := &slurmtypes.V0041ControllerPingList{
+		Items: []slurmtypes.V0041ControllerPing{
+			{
+				V0041ControllerPing: v0041.V0041ControllerPing{
+					Hostname: ptr.To("slurmctld-0"),
+					Pinged:   ptr.To(slurmtypes.V0041ControllerPingPingedUP),
+					Latency:  ptr.To[int64](10),
+				},
+			},
+		},
+	}
+	partitionList := &slurmtypes.V0041PartitionInfoList{
+		Items: []slurmtypes.V0041PartitionInfo{
+			{
+				V0041PartitionInfo: v0041.V0041PartitionInfo{
+					Name: ptr.To("debug"),
+				},
+			},
+		},
+	}
+	return fake.NewClientBuilder().
+		WithObjects(node).
+		WithLists(jobList, pingList, partitionList).
+		WithUpdateFn(updateFn).
+		Build()
+}
+
+// updateFn adds the requested states to the node, and sets its weight. Jobs
+// take the requested requeue.
+func updateFn(_ context.Context, obj object.Object, req any, _ ...slurmclient.UpdateOption) error {
+	switch o := obj.(type) {
+	case *slurmtypes.V0040JobInfo:
+		r := req.(v0040.V0040JobDescMsg)
+		o.Requeue = r.Requeue
+	case *slurmtypes.V0040Node:
+		r := req.(v0040.V0040UpdateNodeMsg)
+		for _, state := range ptr.Deref(r.State, nil) {
+			o.State = ptr.To(append(ptr.Deref(o.State, nil), v0040.V0040NodeState(state)))
+		}
+		o.Reason = r.Reason
+		if r.Weight != nil {
+			o.Weight = ptr.To(int32(ptr.Deref(r.Weight.Number, 0)))
+		}
+	case *slurmtypes.V0041JobInfo:
+		r := req.(v0041.V0041JobDescMsg)
+		o.Requeue = r.Requeue
+	case *slurmtypes.V0041Node:
+		r := req.(v0041.V0041UpdateNodeMsg)
+		for _, state := range ptr.Deref(r.State, nil) {
+			o.State = ptr.To(append(ptr.Deref(o.State, nil), v0041.V0041NodeState(state)))
+		}
+		o.Reason = r.Reason
+		if r.Weight != nil {
+			o.Weight = r.Weight.Number
+		}
+	}
+	return nil
+}
+
+func TestAdapters(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		client  slurmclient.Client
+	}{
+		{
+			name:    "v0040",
+			version: V0040,
+			client:  newV0040Client(),
+		},
+		{
+			name:    "v0041",
+			version: V0041,
+			client:  newV0041Client(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			api, err := New(tt.client, tt.version)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			node, err := api.GetNode(ctx, "node-0")
+			if err != nil {
+				t.Fatalf("GetNode() error = %v", err)
+			}
+			if node.Name != "node-0" || node.Comment != "comment" || node.ReasonSetByUser != "slurm" || node.AllocCPUs != 4 ||
+				!node.State.Equal(set.New(NodeStateIdle)) {
+				t.Errorf("GetNode() = %+v", node)
+			}
+
+			update := NodeUpdate{
+				State:  []NodeState{NodeStateDrain},
+				Reason: ptr.To("reason"),
+				Weight: ptr.To[int32](10),
+			}
+			if err := api.UpdateNode(ctx, node, update); err != nil {
+				t.Fatalf("UpdateNode() error = %v", err)
+			}
+			nodes, err := api.ListNodes(ctx)
+			if err != nil {
+				t.Fatalf("ListNodes() error = %v", err)
+			}
+			if len(nodes) != 1 || nodes[0].Reason != "reason" || nodes[0].Weight != 10 ||
+				!nodes[0].State.Equal(set.New(NodeStateIdle, NodeStateDrain)) {
+				t.Errorf("ListNodes() = %+v", nodes)
+			}
+
+			jobs, err := api.ListJobs(ctx)
+			if err != nil {
+				t.Fatalf("ListJobs() error = %v", err)
+			}
+			// The fake client lists in no particular order.
+			slices.SortFunc(jobs, func(a, b Job) int { return cmp.Compare(a.ID, b.ID) })
+			wantJobs := []Job{
+				{
+					ID:        1,
+					Nodes:     "node-0",
+					IsRunning: true,
+					StartTime: time.Unix(100, 0),
+					TimeLimit: InfiniteDuration,
+				},
+				{
+					ID:         2,
+					IsPending:  true,
+					Partitions: []string{"debug", "all"},
+					NodeCount:  2,
+					CPUs:       8,
+					StartTime:  time.Unix(0, 0),
+				},
+			}
+			if !reflect.DeepEqual(jobs, wantJobs) {
+				t.Errorf("ListJobs() = %+v, want %+v", jobs, wantJobs)
+			}
+
+			partitions, err := api.ListPartitions(ctx)
+			if err != nil {
+				t.Fatalf("ListPartitions() error = %v", err)
+			}
+			if len(partitions) != 1 || partitions[0].Name != "debug" {
+				t.Errorf("ListPartitions() = %+v", partitions)
+			}
+
+			pings, err := api.PingControllers(ctx)
+			if err != nil {
+				t.Fatalf("PingControllers() error = %v", err)
+			}
+			wantPing := ControllerPing{
+				Hostname: "slurmctld-0",
+				IsUp:     true,
+				Latency:  10 * time.Microsecond,
+			}
+			if len(pings) != 1 || pings[0] != wantPing {
+				t.Errorf("PingControllers() = %+v, want %+v", pings, wantPing)
+			}
+
+			if err := api.UpdateJob(ctx, 2, JobUpdate{Requeue: ptr.To(true)}); err != nil {
+				t.Fatalf("UpdateJob() error = %v", err)
+			}
+
+			if err := api.CancelJob(ctx, 1); err != nil {
+				t.Fatalf("CancelJob() error = %v", err)
+			}
+			jobs, err = api.ListJobs(ctx)
+			if err != nil {
+				t.Fatalf("ListJobs() error = %v", err)
+			}
+			if len(jobs) != 1 || jobs[0].ID != 2 {
+				t.Errorf("ListJobs() after CancelJob() = %+v", jobs)
+			}
+		})
+	}
+}
+
+func TestAdapters_ToNode(t *testing.T) {
+	v0040API, _ := New(nil, V0040)
+	v0041API, _ := New(nil, V0041)
+	v0040Node := &slurmtypes.V0040Node{V0040Node: v0040.V0040Node{Name: ptr.To("node-0")}}
+	v0041Node := &slurmtypes.V0041Node{V0041Node: v0041.V0041Node{Name: ptr.To("node-0")}}
+	tests := []struct {
+		name string
+		api  Interface
+		obj  any
+		want bool
+	}{
+		{
+			name: "v0040",
+			api:  v0040API,
+			obj:  v0040Node,
+			want: true,
+		},
+		{
+			name: "v0040 mismatch",
+			api:  v0040API,
+			obj:  v0041Node,
+			want: false,
+		},
+		{
+			name: "v0041",
+			api:  v0041API,
+			obj:  v0041Node,
+			want: true,
+		},
+		{
+			name: "v0041 mismatch",
+			api:  v0041API,
+			obj:  v0040Node,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, ok := tt.api.ToNode(tt.obj)
+			if ok != tt.want {
+				t.Fatalf("ToNode() ok = %v, want %v", ok, tt.want)
+			}
+			if ok && node.Name != "node-0" {
+				t.Errorf("ToNode() name = %v, want node-0", node.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmapi
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+)
+
+// v0041Adapter implements Interface for the v0041 Slurm REST API.
+type v0041Adapter struct {
+	client slurmclient.Client
+}
+
+func v0041PingObject() object.Object {
+	return &slurmtypes.V0041ControllerPing{}
+}
+
+// Version implements Interface.
+func (a *v0041Adapter) Version() string {
+	return V0041
+}
+
+// Client implements Interface.
+func (a *v0041Adapter) Client() slurmclient.Client {
+	return a.client
+}
+
+// GetNode implements Interface.
+func (a *v0041Adapter) GetNode(ctx context.Context, name string) (*Node, error) {
+	node := &slurmtypes.V0041Node{}
+	if err := a.client.Get(ctx, object.ObjectKey(name), node); err != nil {
+		return nil, err
+	}
+	return v0041ToNode(node), nil
+}
+
+// ListNodes implements Interface.
+func (a *v0041Adapter) ListNodes(ctx context.Context, opts ...slurmclient.ListOption) ([]Node, error) {
+	nodeList := &slurmtypes.V0041NodeList{}
+	if err := a.client.List(ctx, nodeList, opts...); err != nil {
+		return nil, err
+	}
+	nodes := make([]Node, 0, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes = append(nodes, *v0041ToNode(&nodeList.Items[i]))
+	}
+	return nodes, nil
+}
+
+// UpdateNode implements Interface.
+func (a *v0041Adapter) UpdateNode(ctx context.Context, node *Node, update NodeUpdate) error {
+	obj, ok := node.object.(*slurmtypes.V0041Node)
+	if !ok {
+		obj = &slurmtypes.V0041Node{
+			V0041Node: v0041.V0041Node{
+				Name: ptr.To(node.Name),
+			},
+		}
+	}
+	req := v0041.V0041UpdateNodeMsg{
+		Reason:  update.Reason,
+		Comment: update.Comment,
+	}
+	if len(update.State) > 0 {
+		states := make([]v0041.V0041UpdateNodeMsgState, 0, len(update.State))
+		for _, state := range update.State {
+			states = append(states, v0041.V0041UpdateNodeMsgState(state))
+		}
+		req.State = ptr.To(states)
+	}
+	if update.Weight != nil {
+		req.Weight = &v0041.V0041Uint32NoValStruct{
+			Set:    ptr.To(true),
+			Number: update.Weight,
+		}
+	}
+	return a.client.Update(ctx, obj, req)
+}
+
+// ListJobs implements Interface.
+func (a *v0041Adapter) ListJobs(ctx context.Context) ([]Job, error) {
+	jobList := &slurmtypes.V0041JobInfoList{}
+	if err := a.client.List(ctx, jobList); err != nil {
+		return nil, err
+	}
+	jobs := make([]Job, 0, len(jobList.Items))
+	for _, job := range jobList.Items {
+		startTime := ptr.Deref(job.StartTime, v0041.V0041Uint64NoValStruct{})
+		nodeCount := ptr.Deref(job.NodeCount, v0041.V0041Uint32NoValStruct{})
+		cpus := ptr.Deref(job.Cpus, v0041.V0041Uint32NoValStruct{})
+		timeLimit := ptr.Deref(job.TimeLimit, v0041.V0041Uint32NoValStruct{})
+		jobs = append(jobs, Job{
+			ID:         ptr.Deref(job.JobId, 0),
+			Nodes:      ptr.Deref(job.Nodes, ""),
+			IsRunning:  job.GetStateAsSet().Has(v0041.V0041JobInfoJobStateRUNNING),
+			IsPending:  job.GetStateAsSet().Has(v0041.V0041JobInfoJobStatePENDING),
+			Partitions: toPartitions(ptr.Deref(job.Partition, "")),
+			NodeCount:  int32(ptr.Deref(nodeCount.Number, 0)),
+			CPUs:       int32(ptr.Deref(cpus.Number, 0)),
+			StartTime:  time.Unix(ptr.Deref(startTime.Number, 0), 0),
+			TimeLimit:  toTimeLimit(int64(ptr.Deref(timeLimit.Number, 0)), ptr.Deref(timeLimit.Infinite, false)),
+		})
+	}
+	return jobs, nil
+}
+
+// UpdateJob implements Interface.
+func (a *v0041Adapter) UpdateJob(ctx context.Context, id int32, update JobUpdate) error {
+	job := &slurmtypes.V0041JobInfo{
+		V0041JobInfo: v0041.V0041JobInfo{
+			JobId: ptr.To(id),
+		},
+	}
+	req := v0041.V0041JobDescMsg{
+		Requeue: update.Requeue,
+	}
+	return a.client.Update(ctx, job, req)
+}
+
+// CancelJob implements Interface.
+func (a *v0041Adapter) CancelJob(ctx context.Context, id int32) error {
+	job := &slurmtypes.V0041JobInfo{
+		V0041JobInfo: v0041.V0041JobInfo{
+			JobId: ptr.To(id),
+		},
+	}
+	return a.client.Delete(ctx, job)
+}
+
+// ListPartitions implements Interface.
+func (a *v0041Adapter) ListPartitions(ctx context.Context) ([]Partition, error) {
+	partitionList := &slurmtypes.V0041PartitionInfoList{}
+	if err := a.client.List(ctx, partitionList); err != nil {
+		return nil, err
+	}
+	partitions := make([]Partition, 0, len(partitionList.Items))
+	for _, partition := range partitionList.Items {
+		out := Partition{
+			Name: ptr.Deref(partition.Name, ""),
+		}
+		if partition.Partition != nil {
+			for _, state := range ptr.Deref(partition.Partition.State, nil) {
+				out.State = append(out.State, string(state))
+			}
+		}
+		if partition.Nodes != nil {
+			out.TotalNodes = ptr.Deref(partition.Nodes.Total, 0)
+		}
+		partitions = append(partitions, out)
+	}
+	return partitions, nil
+}
+
+// PingControllers implements Interface.
+func (a *v0041Adapter) PingControllers(ctx context.Context) ([]ControllerPing, error) {
+	pingList := &slurmtypes.V0041ControllerPingList{}
+	if err := a.client.List(ctx, pingList); err != nil {
+		return nil, err
+	}
+	pings := make([]ControllerPing, 0, len(pingList.Items))
+	for _, ping := range pingList.Items {
+		pings = append(pings, ControllerPing{
+			Hostname: ptr.Deref(ping.Hostname, ""),
+			IsUp:     ptr.Deref(ping.Pinged, "") == slurmtypes.V0041ControllerPingPingedUP,
+			Latency:  time.Duration(ptr.Deref(ping.Latency, 0)) * time.Microsecond,
+		})
+	}
+	return pings, nil
+}
+
+// NodeObjectType implements Interface.
+func (a *v0041Adapter) NodeObjectType() object.ObjectType {
+	return slurmtypes.ObjectTypeV0041Node
+}
+
+// ToNode implements Interface.
+func (a *v0041Adapter) ToNode(obj any) (*Node, bool) {
+	node, ok := obj.(*slurmtypes.V0041Node)
+	if !ok {
+		return nil, false
+	}
+	return v0041ToNode(node), true
+}
+
+func v0041ToNode(node *slurmtypes.V0041Node) *Node {
+	states := set.New[NodeState]()
+	for state := range node.GetStateAsSet() {
+		states.Insert(NodeState(state))
+	}
+	return &Node{
+		Name:            ptr.Deref(node.Name, ""),
+		State:           states,
+		Reason:          ptr.Deref(node.Reason, ""),
+		ReasonSetByUser: ptr.Deref(node.ReasonSetByUser, ""),
+		Comment:         ptr.Deref(node.Comment, ""),
+		Partitions:      ptr.Deref(node.Partitions, nil),
+		AllocCPUs:       ptr.Deref(node.AllocCpus, 0),
+		Weight:          ptr.Deref(node.Weight, 0),
+		object:          node,
+	}
+}
+
+var _ Interface = &v0041Adapter{}